@@ -0,0 +1,22 @@
+# == Schema Information
+#
+# Table name: api_keys
+#
+#  id           :integer          not null, primary key
+#  name         :string(255)      not null
+#  key_digest   :string(255)      not null
+#  key_prefix   :string(255)      not null
+#  last_used_at :datetime
+#  revoked_at   :datetime
+#  created_at   :datetime         not null
+#  updated_at   :datetime         not null
+#  user_id      :integer
+#
+
+class ApiKey < ApplicationRecord
+  belongs_to :user, optional: true
+
+  validates :name, presence: true
+  validates :key_digest, presence: true, uniqueness: true
+  validates :key_prefix, presence: true
+end
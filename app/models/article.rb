@@ -2,16 +2,34 @@
 #
 # Table name: articles
 #
-#  id         :integer          not null, primary key
-#  title      :string(255)      default(""), not null
-#  text       :text(65535)
-#  created_at :datetime         not null
-#  updated_at :datetime         not null
+#  id           :integer          not null, primary key
+#  title        :string(255)      default(""), not null
+#  text         :text(65535)
+#  created_at   :datetime         not null
+#  updated_at   :datetime         not null
+#  user_id      :integer
+#  slug         :string(100)      not null
+#  status       :string(16)       default("draft"), not null
+#  published_at :datetime
+#  text_html    :text(16777215)
 #
 
 class Article < ApplicationRecord
+  belongs_to :user, optional: true
   has_many :comments, dependent: :destroy
+  has_many :article_tags, dependent: :destroy
+  has_many :tags, through: :article_tags
+  has_many :article_slugs, dependent: :destroy
+  has_many :article_revisions, -> { order(:rev) }, dependent: :destroy
+
+  STATUSES = %w(draft scheduled published archived).freeze
 
   validates :title, presence: true, length: { in: 10..30 }
   validates :text, presence: true, length: { minimum: 20 }
+  validates :status, inclusion: { in: STATUSES }
+
+  scope :published, -> { where(status: "published") }
+
+  # the cached HTML is rendered again from the new text by the app
+  before_save { self.text_html = nil if text_changed? }
 end
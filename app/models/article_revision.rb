@@ -0,0 +1,21 @@
+# == Schema Information
+#
+# Table name: article_revisions
+#
+#  id         :integer          not null, primary key
+#  article_id :integer          not null
+#  rev        :integer          not null
+#  title      :string(255)      not null
+#  text       :text(65535)
+#  user_id    :integer
+#  created_at :datetime         not null
+#  updated_at :datetime         not null
+#
+
+class ArticleRevision < ApplicationRecord
+  belongs_to :article
+  belongs_to :user, optional: true
+
+  validates :rev, presence: true, uniqueness: { scope: :article_id }
+  validates :title, presence: true
+end
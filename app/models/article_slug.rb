@@ -0,0 +1,16 @@
+# == Schema Information
+#
+# Table name: article_slugs
+#
+#  id         :integer          not null, primary key
+#  article_id :integer          not null
+#  slug       :string(100)      not null
+#  created_at :datetime         not null
+#  updated_at :datetime         not null
+#
+
+class ArticleSlug < ApplicationRecord
+  belongs_to :article
+
+  validates :slug, presence: true, uniqueness: true
+end
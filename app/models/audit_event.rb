@@ -0,0 +1,28 @@
+# == Schema Information
+#
+# Table name: audit_events
+#
+#  id           :integer          not null, primary key
+#  resource     :string(32)       not null
+#  record_id    :integer          not null
+#  action       :string(16)       not null
+#  actor_id     :integer
+#  actor        :string(255)      default(""), not null
+#  request_id   :string(128)      default(""), not null
+#  ip           :string(45)       default(""), not null
+#  before_state :text(65535)
+#  after_state  :text(65535)
+#  created_at   :datetime         not null
+#
+
+class AuditEvent < ApplicationRecord
+  ACTIONS = %w(create update destroy).freeze
+
+  validates :resource, :record_id, presence: true
+  validates :action, inclusion: { in: ACTIONS }
+
+  # the log is append only
+  def readonly?
+    persisted?
+  end
+end
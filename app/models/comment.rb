@@ -8,11 +8,27 @@
 #  article_id :integer
 #  created_at :datetime         not null
 #  updated_at :datetime         not null
+#  user_id    :integer
+#  status     :string(255)      default("pending"), not null
+#  spam_score :integer          default(0), not null
+#  parent_id  :integer
+#  body_html  :text(16777215)
 #
 
 class Comment < ApplicationRecord
+  STATUSES = %w(pending approved rejected spam).freeze
+
   belongs_to :article
+  belongs_to :user, optional: true
+  belongs_to :parent, class_name: "Comment", optional: true
+  has_many :replies, class_name: "Comment", foreign_key: :parent_id
 
   validates :commenter, presence: true
   validates :body, presence: true, length: { minimum: 20 }
+  validates :status, inclusion: { in: STATUSES }
+
+  scope :approved, -> { where(status: "approved") }
+
+  # the cached HTML is rendered again from the new body by the app
+  before_save { self.body_html = nil if body_changed? }
 end
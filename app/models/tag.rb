@@ -0,0 +1,16 @@
+# == Schema Information
+#
+# Table name: tags
+#
+#  id         :integer          not null, primary key
+#  name       :string(64)       not null
+#  created_at :datetime         not null
+#  updated_at :datetime         not null
+#
+
+class Tag < ApplicationRecord
+  has_many :article_tags, dependent: :destroy
+  has_many :articles, through: :article_tags
+
+  validates :name, presence: true, uniqueness: true, length: { maximum: 64 }
+end
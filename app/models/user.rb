@@ -0,0 +1,26 @@
+# == Schema Information
+#
+# Table name: users
+#
+#  id              :integer          not null, primary key
+#  name            :string(255)      not null
+#  email           :string(255)      not null
+#  created_at      :datetime         not null
+#  updated_at      :datetime         not null
+#  password_digest :string(255)
+#  role            :string(255)      default("commenter"), not null
+#
+
+class User < ApplicationRecord
+  ROLES = %w(reader commenter editor moderator admin).freeze
+
+  has_many :articles
+  has_many :comments
+  has_many :api_keys
+
+  has_secure_password validations: false
+
+  validates :name, presence: true
+  validates :email, presence: true, uniqueness: true
+  validates :role, inclusion: { in: ROLES }
+end
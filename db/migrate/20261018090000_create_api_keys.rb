@@ -0,0 +1,14 @@
+class CreateApiKeys < ActiveRecord::Migration[5.0]
+  def change
+    create_table :api_keys do |t|
+      t.string :name, null: false
+      t.string :key_digest, null: false
+      t.string :key_prefix, null: false
+      t.datetime :last_used_at
+      t.datetime :revoked_at
+
+      t.timestamps
+    end
+    add_index :api_keys, :key_digest, unique: true
+  end
+end
@@ -0,0 +1,7 @@
+class AddUserToArticlesCommentsAndApiKeys < ActiveRecord::Migration[5.0]
+  def change
+    add_reference :articles, :user, foreign_key: true
+    add_reference :comments, :user, foreign_key: true
+    add_reference :api_keys, :user, foreign_key: true
+  end
+end
@@ -0,0 +1,13 @@
+class ReplaceAdminWithRoleOnUsers < ActiveRecord::Migration[5.0]
+  def up
+    add_column :users, :role, :string, null: false, default: "commenter"
+    execute "UPDATE users SET role = 'admin' WHERE admin = 1"
+    remove_column :users, :admin
+  end
+
+  def down
+    add_column :users, :admin, :boolean, null: false, default: false
+    execute "UPDATE users SET admin = 1 WHERE role = 'admin'"
+    remove_column :users, :role
+  end
+end
@@ -0,0 +1,15 @@
+class AddModerationToComments < ActiveRecord::Migration[5.0]
+  def up
+    add_column :comments, :status, :string, null: false, default: "pending"
+    add_column :comments, :spam_score, :integer, null: false, default: 0
+    add_index :comments, [:article_id, :status]
+    # the comments so far were published right away
+    execute "UPDATE comments SET status = 'approved'"
+  end
+
+  def down
+    remove_index :comments, [:article_id, :status]
+    remove_column :comments, :spam_score
+    remove_column :comments, :status
+  end
+end
@@ -0,0 +1,7 @@
+class AddParentToComments < ActiveRecord::Migration[5.0]
+  def change
+    add_reference :comments, :parent, index: true
+    # the replies of a comment deleted outside of the app move up to the top level
+    add_foreign_key :comments, :comments, column: :parent_id, on_delete: :nullify
+  end
+end
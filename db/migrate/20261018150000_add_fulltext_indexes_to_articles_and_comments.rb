@@ -0,0 +1,7 @@
+class AddFulltextIndexesToArticlesAndComments < ActiveRecord::Migration[5.0]
+  def change
+    # used by the mysql search backend
+    add_index :articles, [:title, :text], type: :fulltext
+    add_index :comments, :body, type: :fulltext
+  end
+end
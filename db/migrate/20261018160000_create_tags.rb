@@ -0,0 +1,10 @@
+class CreateTags < ActiveRecord::Migration[5.0]
+  def change
+    create_table :tags do |t|
+      t.string :name, null: false, limit: 64
+
+      t.timestamps
+    end
+    add_index :tags, :name, unique: true
+  end
+end
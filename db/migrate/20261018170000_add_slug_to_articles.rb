@@ -0,0 +1,25 @@
+class AddSlugToArticles < ActiveRecord::Migration[5.0]
+  class Article < ActiveRecord::Base
+  end
+
+  def up
+    add_column :articles, :slug, :string, limit: 100
+    taken = Set.new
+    Article.find_each do |article|
+      base = article.title.to_s.parameterize.first(80).chomp('-')
+      base = 'article' if base.empty?
+      base = "article-#{base}" if base =~ /\A\d+\z/
+      slug, i = base, 1
+      slug = "#{base}-#{i += 1}" while taken.include?(slug)
+      taken << slug
+      article.update_column :slug, slug
+    end
+    change_column_null :articles, :slug, false
+    add_index :articles, :slug, unique: true
+  end
+
+  def down
+    remove_index :articles, :slug
+    remove_column :articles, :slug
+  end
+end
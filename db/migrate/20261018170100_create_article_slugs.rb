@@ -0,0 +1,11 @@
+class CreateArticleSlugs < ActiveRecord::Migration[5.0]
+  def change
+    create_table :article_slugs do |t|
+      t.references :article, null: false, foreign_key: true
+      t.string :slug, null: false, limit: 100
+
+      t.timestamps
+    end
+    add_index :article_slugs, :slug, unique: true
+  end
+end
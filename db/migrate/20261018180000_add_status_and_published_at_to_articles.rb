@@ -0,0 +1,15 @@
+class AddStatusAndPublishedAtToArticles < ActiveRecord::Migration[5.0]
+  def up
+    add_column :articles, :status, :string, limit: 16, null: false, default: 'draft'
+    add_column :articles, :published_at, :datetime
+    # the existing articles were public already
+    execute "UPDATE articles SET status = 'published', published_at = created_at"
+    add_index :articles, [:status, :published_at]
+  end
+
+  def down
+    remove_index :articles, [:status, :published_at]
+    remove_column :articles, :published_at
+    remove_column :articles, :status
+  end
+end
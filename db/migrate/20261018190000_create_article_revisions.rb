@@ -0,0 +1,25 @@
+class CreateArticleRevisions < ActiveRecord::Migration[5.0]
+  def up
+    create_table :article_revisions do |t|
+      t.references :article, null: false, foreign_key: true
+      t.integer :rev, null: false
+      t.string :title, null: false
+      t.text :text
+      t.references :user
+
+      t.timestamps
+    end
+    add_index :article_revisions, [:article_id, :rev], unique: true
+    # the revisions of a deleted user are kept without their editor
+    add_foreign_key :article_revisions, :users, on_delete: :nullify
+    # the existing articles start their history with their current state
+    execute <<-SQL
+      INSERT INTO article_revisions (article_id, rev, title, text, user_id, created_at, updated_at)
+      SELECT id, 1, title, text, user_id, updated_at, updated_at FROM articles
+    SQL
+  end
+
+  def down
+    drop_table :article_revisions
+  end
+end
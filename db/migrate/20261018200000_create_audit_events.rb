@@ -0,0 +1,36 @@
+class CreateAuditEvents < ActiveRecord::Migration[5.0]
+  def up
+    create_table :audit_events do |t|
+      t.string :resource, null: false, limit: 32
+      t.integer :record_id, null: false
+      t.string :action, null: false, limit: 16
+      t.integer :actor_id
+      t.string :actor, null: false, default: ""
+      t.string :request_id, null: false, default: "", limit: 128
+      t.string :ip, null: false, default: "", limit: 45
+      # a snapshot of an article has its text and its rendered HTML, longer than a text column
+      t.text :before_state, limit: 4.gigabytes - 1
+      t.text :after_state, limit: 4.gigabytes - 1
+      t.datetime :created_at, null: false
+    end
+    add_index :audit_events, [:resource, :record_id]
+    add_index :audit_events, :actor_id
+    add_index :audit_events, :request_id
+    # the log is append only, neither the app nor anyone else can rewrite the history.
+    # The triggers aren't in db/schema.rb, lib/tasks/audit_events.rake creates them after db:schema:load
+    execute <<-SQL
+      CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events FOR EACH ROW
+      SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append only'
+    SQL
+    execute <<-SQL
+      CREATE TRIGGER audit_events_no_delete BEFORE DELETE ON audit_events FOR EACH ROW
+      SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append only'
+    SQL
+  end
+
+  def down
+    execute "DROP TRIGGER IF EXISTS audit_events_no_update"
+    execute "DROP TRIGGER IF EXISTS audit_events_no_delete"
+    drop_table :audit_events
+  end
+end
@@ -0,0 +1,7 @@
+class AddRenderedHtmlToArticlesAndComments < ActiveRecord::Migration[5.0]
+  # the HTML rendered from the markdown is cached by the app on the first read, NULL until then
+  def change
+    add_column :articles, :text_html, :text, limit: 16.megabytes - 1
+    add_column :comments, :body_html, :text, limit: 16.megabytes - 1
+  end
+end
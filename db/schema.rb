@@ -10,13 +10,83 @@
 #
 # It's strongly recommended that you check this file into your version control system.
 
-ActiveRecord::Schema.define(version: 20170422171337) do
+ActiveRecord::Schema.define(version: 20261018210000) do
 
-  create_table "articles", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
-    t.string   "title",                    default: "", null: false
+  create_table "api_keys", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.string   "name",         null: false
+    t.string   "key_digest",   null: false
+    t.string   "key_prefix",   null: false
+    t.datetime "last_used_at"
+    t.datetime "revoked_at"
+    t.datetime "created_at",   null: false
+    t.datetime "updated_at",   null: false
+    t.integer  "user_id"
+    t.index ["key_digest"], name: "index_api_keys_on_key_digest", unique: true, using: :btree
+    t.index ["user_id"], name: "index_api_keys_on_user_id", using: :btree
+  end
+
+  create_table "article_revisions", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.integer  "article_id",               null: false
+    t.integer  "rev",                      null: false
+    t.string   "title",                    null: false
     t.text     "text",       limit: 65535
-    t.datetime "created_at",                            null: false
-    t.datetime "updated_at",                            null: false
+    t.integer  "user_id"
+    t.datetime "created_at",               null: false
+    t.datetime "updated_at",               null: false
+    t.index ["article_id", "rev"], name: "index_article_revisions_on_article_id_and_rev", unique: true, using: :btree
+    t.index ["article_id"], name: "index_article_revisions_on_article_id", using: :btree
+    t.index ["user_id"], name: "index_article_revisions_on_user_id", using: :btree
+  end
+
+  create_table "article_slugs", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.integer  "article_id",             null: false
+    t.string   "slug",       limit: 100, null: false
+    t.datetime "created_at",             null: false
+    t.datetime "updated_at",             null: false
+    t.index ["article_id"], name: "index_article_slugs_on_article_id", using: :btree
+    t.index ["slug"], name: "index_article_slugs_on_slug", unique: true, using: :btree
+  end
+
+  create_table "article_tags", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.integer  "article_id", null: false
+    t.integer  "tag_id",     null: false
+    t.datetime "created_at", null: false
+    t.datetime "updated_at", null: false
+    t.index ["article_id", "tag_id"], name: "index_article_tags_on_article_id_and_tag_id", unique: true, using: :btree
+    t.index ["article_id"], name: "index_article_tags_on_article_id", using: :btree
+    t.index ["tag_id"], name: "index_article_tags_on_tag_id", using: :btree
+  end
+
+  create_table "articles", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.string   "title",                      default: "",      null: false
+    t.text     "text",         limit: 65535
+    t.datetime "created_at",                                   null: false
+    t.datetime "updated_at",                                   null: false
+    t.integer  "user_id"
+    t.string   "slug",         limit: 100,                     null: false
+    t.string   "status",       limit: 16,    default: "draft", null: false
+    t.datetime "published_at"
+    t.text     "text_html",    limit: 16777215
+    t.index ["slug"], name: "index_articles_on_slug", unique: true, using: :btree
+    t.index ["status", "published_at"], name: "index_articles_on_status_and_published_at", using: :btree
+    t.index ["title", "text"], name: "index_articles_on_title_and_text", type: :fulltext
+    t.index ["user_id"], name: "index_articles_on_user_id", using: :btree
+  end
+
+  create_table "audit_events", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.string   "resource",     limit: 32,                 null: false
+    t.integer  "record_id",                               null: false
+    t.string   "action",       limit: 16,                 null: false
+    t.integer  "actor_id"
+    t.string   "actor",                     default: "", null: false
+    t.string   "request_id",   limit: 128,  default: "", null: false
+    t.string   "ip",           limit: 45,   default: "", null: false
+    t.text     "before_state", limit: 4294967295
+    t.text     "after_state",  limit: 4294967295
+    t.datetime "created_at",                              null: false
+    t.index ["actor_id"], name: "index_audit_events_on_actor_id", using: :btree
+    t.index ["request_id"], name: "index_audit_events_on_request_id", using: :btree
+    t.index ["resource", "record_id"], name: "index_audit_events_on_resource_and_record_id", using: :btree
   end
 
   create_table "comments", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
@@ -25,8 +95,43 @@ ActiveRecord::Schema.define(version: 20170422171337) do
     t.integer  "article_id"
     t.datetime "created_at",                            null: false
     t.datetime "updated_at",                            null: false
+    t.integer  "user_id"
+    t.string   "status",                   default: "pending", null: false
+    t.integer  "spam_score",               default: 0,         null: false
+    t.integer  "parent_id"
+    t.text     "body_html",  limit: 16777215
+    t.index ["article_id", "status"], name: "index_comments_on_article_id_and_status", using: :btree
     t.index ["article_id"], name: "index_comments_on_article_id", using: :btree
+    t.index ["body"], name: "index_comments_on_body", type: :fulltext
+    t.index ["parent_id"], name: "index_comments_on_parent_id", using: :btree
+    t.index ["user_id"], name: "index_comments_on_user_id", using: :btree
+  end
+
+  create_table "tags", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.string   "name",       limit: 64, null: false
+    t.datetime "created_at",            null: false
+    t.datetime "updated_at",            null: false
+    t.index ["name"], name: "index_tags_on_name", unique: true, using: :btree
+  end
+
+  create_table "users", force: :cascade, options: "ENGINE=InnoDB DEFAULT CHARSET=utf8" do |t|
+    t.string   "name",                                  null: false
+    t.string   "email",                                 null: false
+    t.datetime "created_at",                            null: false
+    t.datetime "updated_at",                            null: false
+    t.string   "password_digest"
+    t.string   "role",            default: "commenter", null: false
+    t.index ["email"], name: "index_users_on_email", unique: true, using: :btree
   end
 
+  add_foreign_key "api_keys", "users"
+  add_foreign_key "article_revisions", "articles"
+  add_foreign_key "article_revisions", "users", on_delete: :nullify
+  add_foreign_key "article_slugs", "articles"
+  add_foreign_key "article_tags", "articles"
+  add_foreign_key "article_tags", "tags"
+  add_foreign_key "articles", "users"
   add_foreign_key "comments", "articles"
+  add_foreign_key "comments", "comments", column: "parent_id", on_delete: :nullify
+  add_foreign_key "comments", "users"
 end
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"./src/auth"
+	imp "./src/importer"
+	m "./src/models"
+	"./src/policy"
+	"./src/search"
+)
+
+// commands are the sub commands of myapp besides serving, e.g. "myapp import articles --file x.ndjson".
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"import":  importCmd,
+	"apikeys": apikeysCmd,
+	"users":   usersCmd,
+	"search":  searchCmd,
+	"audit":   auditCmd,
+	"markup":  markupCmd,
+}
+
+// runCommand run the sub command named by the first argument if any,
+// it returns false when the arguments are for the server.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+	m.DefaultAuditActor = "cli:" + args[0]
+	if err := cmd(context.Background(), args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// myapp import articles|comments --file x.ndjson [--format csv] [--dry-run] [--upsert-by title] [--article-key title]
+func importCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: myapp import articles|comments --file FILE [--dry-run] [--upsert-by FIELD]")
+	}
+	opt := imp.Options{Resource: args[0]}
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "NDJSON or CSV file to import, - for stdin")
+	fs.StringVar(&opt.Format, "format", "", "Input format ndjson or csv, guessed by the file extension by default")
+	fs.BoolVar(&opt.DryRun, "dry-run", false, "Validate only, nothing is written")
+	fs.StringVar(&opt.UpsertBy, "upsert-by", "", "Update the existing record found by this field instead of inserting")
+	fs.StringVar(&opt.ArticleKey, "article-key", "title", "Article field matched by the article_key of comments")
+	fs.Parse(args[1:])
+	if *file == "" {
+		return errors.New("--file is required")
+	}
+	if opt.Format == "" {
+		opt.Format = imp.FormatOf(*file)
+	}
+	in := os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	report, err := imp.Run(ctx, in, opt)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d record(s) failed", report.Failed)
+	}
+	return nil
+}
+
+// myapp apikeys create NAME [--user ID] | list | revoke ID
+func apikeysCmd(ctx context.Context, args []string) error {
+	usage := errors.New("usage: myapp apikeys create NAME [--user ID] | list | revoke ID")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "create":
+		if len(args) < 2 {
+			return usage
+		}
+		fs := flag.NewFlagSet("apikeys create", flag.ExitOnError)
+		userId := fs.Int64("user", 0, "ID of the user the key acts as")
+		fs.Parse(args[2:])
+		if *userId != 0 {
+			if _, err := m.FindUser(ctx, *userId); err != nil {
+				return fmt.Errorf("Find user %d error: %v", *userId, err)
+			}
+		}
+		key, ak, err := auth.GenerateAPIKey(ctx, args[1], *userId)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created API key #%d %q, it won't be shown again:\n%s\n", ak.Id, ak.Name, key)
+	case "list":
+		aks, err := m.AllApiKeys(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tUSER\tPREFIX\tCREATED\tLAST USED\tREVOKED")
+		for _, ak := range aks {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s...\t%s\t%s\t%s\n", ak.Id, ak.Name, ak.UserId, ak.KeyPrefix,
+				ak.CreatedAt.Format(time.RFC3339), timeOrDash(ak.LastUsedAt), timeOrDash(ak.RevokedAt))
+		}
+		return w.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return usage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		if err = auth.RevokeAPIKey(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("Revoked API key #%d\n", id)
+	default:
+		return usage
+	}
+	return nil
+}
+
+func timeOrDash(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// myapp users create NAME EMAIL [--role ROLE] [--password] | role ID ROLE | passwd ID | list
+// The password is read from stdin, so it's not left in the shell history.
+func usersCmd(ctx context.Context, args []string) error {
+	usage := errors.New("usage: myapp users create NAME EMAIL [--role ROLE] [--password] | role ID ROLE | passwd ID | list")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "create":
+		if len(args) < 3 {
+			return usage
+		}
+		fs := flag.NewFlagSet("users create", flag.ExitOnError)
+		role := fs.String("role", string(policy.Commenter), fmt.Sprintf("Role of the user, one of %v", policy.Roles))
+		password := fs.Bool("password", false, "Read a password to sign in the pages from stdin")
+		fs.Parse(args[3:])
+		u := m.User{Name: args[1], Email: args[2], Role: *role}
+		if *password {
+			digest, err := readPassword()
+			if err != nil {
+				return err
+			}
+			u.PasswordDigest = digest
+		}
+		id, err := u.Create(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created user #%d %q\n", id, u.Name)
+	case "role":
+		if len(args) != 3 {
+			return usage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		if !policy.Role(args[2]).Valid() {
+			return fmt.Errorf("Unknown role %q, one of %v expected", args[2], policy.Roles)
+		}
+		u, err := m.FindUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = u.Update(ctx, map[string]interface{}{"role": args[2]}); err != nil {
+			return err
+		}
+		fmt.Printf("Changed the role of user #%d %q to %s\n", u.Id, u.Name, args[2])
+	case "passwd":
+		if len(args) != 2 {
+			return usage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		u, err := m.FindUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		digest, err := readPassword()
+		if err != nil {
+			return err
+		}
+		if err = u.Update(ctx, map[string]interface{}{"password_digest": digest}); err != nil {
+			return err
+		}
+		fmt.Printf("Changed the password of user #%d %q\n", u.Id, u.Name)
+	case "list":
+		users, err := m.AllUsers(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tEMAIL\tROLE\tCREATED")
+		for _, u := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", u.Id, u.Name, u.Email, u.Role, u.CreatedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+	default:
+		return usage
+	}
+	return nil
+}
+
+// readPassword read a password from the first line of stdin and hash it.
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return auth.HashPassword(strings.TrimRight(line, "\r\n"))
+}
+
+// myapp search reindex [--backend sqlite] [--dsn search.sqlite3]
+// The writes of the other commands, e.g. import, don't sync the index, it should be rebuilt after them.
+func searchCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "reindex" {
+		return errors.New("usage: myapp search reindex [--backend memory|mysql|sqlite|postgres] [--dsn DSN]")
+	}
+	fs := flag.NewFlagSet("search reindex", flag.ExitOnError)
+	backend := fs.String("backend", "sqlite", "Search backend to rebuild")
+	dsn := fs.String("dsn", "", "Database of the sqlite or postgres search backend")
+	fs.Parse(args[1:])
+	switch *backend {
+	case "memory":
+		fmt.Println("The memory index is rebuilt by the server at each start, nothing to do")
+		return nil
+	case "mysql":
+		fmt.Println("The FULLTEXT indexes are kept by MySQL, nothing to do")
+		return nil
+	}
+	b, err := search.Open(*backend, *dsn)
+	if err != nil {
+		return err
+	}
+	counts, err := search.Reindex(ctx, b)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reindexed %d articles and %d comments\n", counts[search.Articles], counts[search.Comments])
+	return nil
+}
+
+// myapp audit export [--resource articles] [--id 42] [--actor-id 1] [--since 2026-10-01] [--out events.ndjson]
+func auditCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		return errors.New("usage: myapp audit export [--resource articles|comments] [--id ID] [--actor-id ID] [--request-id ID] [--since TIME] [--out FILE]")
+	}
+	var q m.AuditQuery
+	fs := flag.NewFlagSet("audit export", flag.ExitOnError)
+	fs.StringVar(&q.Resource, "resource", "", "Only the events of the articles or the comments")
+	fs.Int64Var(&q.RecordId, "id", 0, "Only the events of the record of this id")
+	fs.Int64Var(&q.ActorId, "actor-id", 0, "Only the events by the user of this id")
+	fs.StringVar(&q.RequestId, "request-id", "", "Only the events of the request of this id")
+	since := fs.String("since", "", "Only the events since this RFC3339 time or 2006-01-02 date")
+	out := fs.String("out", "-", "NDJSON file to write, - for stdout")
+	fs.Parse(args[1:])
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			if t, err = time.ParseInLocation("2006-01-02", *since, time.Local); err != nil {
+				return fmt.Errorf("Parsing --since error: %v", err)
+			}
+		}
+		q.Since = t
+	}
+	where, whereArgs, err := q.Where()
+	if err != nil {
+		return err
+	}
+	w := os.Stdout
+	if *out != "-" {
+		if w, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer w.Close()
+	}
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	n := 0
+	err = m.AuditEventsInBatchesWhere(ctx, 500, where, whereArgs, func(events []m.AuditEvent) error {
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		n += len(events)
+		return nil
+	})
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d audit events\n", n)
+	return nil
+}
+
+// myapp markup clear-cache, after the allowlist of the rendered HTML changed
+func markupCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "clear-cache" {
+		return errors.New("usage: myapp markup clear-cache")
+	}
+	articles, comments, err := m.ClearHTMLCache(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Cleared the cached HTML of %d articles and %d comments, it's rendered again on the next reads\n", articles, comments)
+	return nil
+}
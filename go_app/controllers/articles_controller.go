@@ -1,63 +1,294 @@
 package controllers
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
 	m "../src/models"
+	"../src/policy"
 	"github.com/gin-gonic/gin"
 )
 
 // GET /articles
 func ArticlesIndex(c *gin.Context) {
-	articles, err := m.AllArticles()
+	if !authorize(c, policy.ReadArticle(currentActor(c))) {
+		return
+	}
+	where, args, err := articleFilters(c)
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", err.Error(), nil))
+		return
+	}
+	articles, err := m.FindArticlesWhere(c.Request.Context(), where, args...)
+	if err == nil {
+		err = m.LoadArticlesTags(c.Request.Context(), articles)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Get article index error: %v", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
+	if wantsHTML(c) {
+		renderHTML(c, http.StatusOK, "articles_index.tmpl", gin.H{"Title": "Articles", "Articles": articles})
+		return
+	}
+	m.LoadArticlesHTML(c.Request.Context(), articles)
 	resp := BuildResp("200", "Get article index success", articles)
 	c.JSON(http.StatusOK, resp)
 }
 
-// GET /articles/1
-func ArticlesShow(c *gin.Context) {
-	id, err := ToInt(c.Param("id"))
+// GET /articles/export?format=ndjson|csv
+func ArticlesExport(c *gin.Context) {
+	if !authorize(c, policy.ReadArticle(currentActor(c))) {
+		return
+	}
+	where, args, err := articleFilters(c)
 	if err != nil {
-		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+		c.JSON(http.StatusOK, BuildResp("400", err.Error(), nil))
 		return
 	}
-	article, err := m.FindArticle(id)
+	header := []string{"id", "title", "text", "created_at", "updated_at"}
+	ex, err := newExporter(c, "articles", header)
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", err.Error(), nil))
+		return
+	}
+	err = m.ArticlesInBatchesWhere(c.Request.Context(), exportBatchSize, where, args, func(articles []m.Article) error {
+		for _, ar := range articles {
+			row := []string{ToStr(ar.Id), ar.Title, ar.Text, ToTimeStr(ar.CreatedAt), ToTimeStr(ar.UpdatedAt)}
+			if err := ex.Write(ar, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	ex.Close(err)
+}
+
+// articleFilters build the where clause shared by the article index and export from the query params:
+// title, created_after and created_before, the latter two accept a RFC3339 time or a 2006-01-02 date,
+// the tag repeated for the articles with any of the tags, or all of them with tag_match=all,
+// and the status, only the published articles by default.
+func articleFilters(c *gin.Context) (string, []interface{}, error) {
+	cond, args, err := statusFilter(c)
+	if err != nil {
+		return "", nil, err
+	}
+	conds := []string{}
+	if cond != "" {
+		conds = append(conds, cond)
+	}
+	if title := c.Query("title"); title != "" {
+		conds = append(conds, "title = ?")
+		args = append(args, title)
+	}
+	for param, op := range map[string]string{"created_after": ">=", "created_before": "<"} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := ParseTime(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("Parsing %s error: %v", param, err)
+		}
+		conds = append(conds, "created_at "+op+" ?")
+		args = append(args, t)
+	}
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		tags, err := m.NormalizeTagNames(tags)
+		if err != nil {
+			return "", nil, err
+		}
+		cond := "id IN (SELECT article_tags.article_id FROM article_tags JOIN tags ON tags.id = article_tags.tag_id WHERE tags.name IN (?" + strings.Repeat(",?", len(tags)-1) + ")"
+		for _, t := range tags {
+			args = append(args, t)
+		}
+		switch c.DefaultQuery("tag_match", "any") {
+		case "any":
+			cond += ")"
+		case "all":
+			cond += " GROUP BY article_tags.article_id HAVING COUNT(DISTINCT tags.id) = ?)"
+			args = append(args, len(tags))
+		default:
+			return "", nil, fmt.Errorf("The tag_match should be any or all")
+		}
+		conds = append(conds, cond)
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// articleParams is the JSON of an article to create or update, with its tags by their names,
+// the tags are left as they are if missing.
+type articleParams struct {
+	m.Article
+	Tags *[]string `json:"tags"`
+}
+
+// tagNames normalize the tag names, nil if no tags given.
+func (p *articleParams) tagNames() ([]string, error) {
+	if p.Tags == nil {
+		return nil, nil
+	}
+	return m.NormalizeTagNames(*p.Tags)
+}
+
+// formTags get the tags of the comma separated field "tags" of the article form.
+func formTags(c *gin.Context) *[]string {
+	tags := strings.Split(c.PostForm("tags"), ",")
+	return &tags
+}
+
+// findArticleByIdOrSlug find an article by its id or its slug, current is false for an old slug.
+func findArticleByIdOrSlug(ctx context.Context, idOrSlug string) (*m.Article, bool, error) {
+	if id, err := ToInt(idOrSlug); err == nil {
+		ar, err := m.FindArticle(ctx, id)
+		return ar, true, err
+	}
+	return m.FindArticleBySlug(ctx, idOrSlug)
+}
+
+// GET /articles/1 or /articles/my-first-article?render=html|markdown|plain, an old slug is redirected to the current one
+func ArticlesShow(c *gin.Context) {
+	if !authorize(c, policy.ReadArticle(currentActor(c))) {
+		return
+	}
+	article, current, err := findArticleByIdOrSlug(c.Request.Context(), c.Param("id"))
+	if err == nil && !articleVisible(c, article) {
+		err = sql.ErrNoRows
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Get article error: %v", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
+	if !current {
+		to := "/articles/" + url.PathEscape(article.Slug)
+		if c.Request.URL.RawQuery != "" {
+			to += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, to)
+		return
+	}
+	if err = article.GetTags(c.Request.Context()); err != nil {
+		logger(c).Error("Get article tags error", "article_id", article.Id, "error", err)
+	}
+	article.LoadTextHTML(c.Request.Context())
+	if wantsHTML(c) {
+		where, args := visibleComments(c, "article_id = ?", article.Id)
+		if article.Comments, err = m.FindCommentsWhere(c.Request.Context(), where, args...); err != nil {
+			logger(c).Error("Get article comments error", "article_id", article.Id, "error", err)
+		}
+		m.LoadCommentsHTML(c.Request.Context(), article.Comments)
+		renderHTML(c, http.StatusOK, "articles_show.tmpl", gin.H{"Title": article.Title, "Article": article})
+		return
+	}
+	if !applyRender(c, &article.Text, &article.TextHtml) {
+		return
+	}
 	resp := BuildResp("200", "Get article success", article)
 	c.JSON(http.StatusOK, resp)
 }
 
+// GET /articles/new
 func ArticlesNew(c *gin.Context) {
+	if !requireLogin(c) || !authorize(c, policy.CreateArticle(currentActor(c))) {
+		return
+	}
+	renderArticleForm(c, http.StatusOK, &m.Article{}, "")
 }
 
+// GET /articles/1/edit
 func ArticlesEdit(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		redirectWithFlash(c, "/articles", "alert", "Parsing id error!")
+		return
+	}
+	ar, err := m.FindArticle(c.Request.Context(), id)
+	if err != nil {
+		redirectWithFlash(c, "/articles", "alert", fmt.Sprintf("Get article error: %v", err))
+		return
+	}
+	if !authorize(c, policy.UpdateArticle(currentActor(c), articleResource(ar))) {
+		return
+	}
+	if err = ar.GetTags(c.Request.Context()); err != nil {
+		logger(c).Error("Get article tags error", "article_id", ar.Id, "error", err)
+	}
+	renderArticleForm(c, http.StatusOK, ar, "")
+}
+
+// renderArticleForm render the form to create a new article or edit an existing one.
+func renderArticleForm(c *gin.Context, code int, ar *m.Article, errMsg string) {
+	data := gin.H{"Article": ar, "Error": errMsg}
+	if isFormRequest(c) {
+		data["Tags"] = c.PostForm("tags")
+	} else {
+		names := make([]string, len(ar.Tags))
+		for i, t := range ar.Tags {
+			names[i] = t.Name
+		}
+		data["Tags"] = strings.Join(names, ", ")
+	}
+	if ar.Id == 0 {
+		data["Title"], data["Action"], data["Method"], data["Back"] = "New article", "/articles", "POST", "/articles"
+	} else {
+		path := fmt.Sprintf("/articles/%d", ar.Id)
+		data["Title"], data["Action"], data["Method"], data["Back"] = "Edit article", path, "PUT", path
+	}
+	renderHTML(c, code, "articles_form.tmpl", data)
 }
 
 // POST /articles
 func ArticlesCreate(c *gin.Context) {
-	var ar m.Article
-	if c.BindJSON(&ar) != nil {
-		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+	if !authorize(c, policy.CreateArticle(currentActor(c))) {
+		return
+	}
+	var params articleParams
+	form := isFormRequest(c)
+	if form {
+		params.Title, params.Text = c.PostForm("title"), c.PostForm("text")
+		params.Tags = formTags(c)
+	} else if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Parsing JSON error: %v", err), nil))
 		return
 	}
-	id, err := ar.Create()
+	ar := params.Article
+	// a new article is a draft until it's published
+	ar.UserId, ar.Status, ar.PublishedAt = 0, m.ArticleDraft, nil
+	if u := CurrentUser(c); u != nil {
+		ar.UserId = u.Id
+	}
+	var id int64
+	tags, err := params.tagNames()
+	if err == nil {
+		id, err = ar.Create(c.Request.Context())
+	}
+	if err == nil && tags != nil {
+		_, err = m.SetArticleTags(c.Request.Context(), id, tags)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Create article error: %v", err)
-		log.Println(msg)
+		logger(c).Warn("Create article error", "error", err)
+		if form {
+			renderArticleForm(c, http.StatusUnprocessableEntity, &ar, msg)
+			return
+		}
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
+	saveRevision(c, id)
+	if form {
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", id), "notice", "Article created")
+		return
+	}
 	c.JSON(http.StatusOK, BuildResp("200", "Create article success", map[string]int64{"id": id}))
 }
 
@@ -68,14 +299,33 @@ func ArticlesUpdate(c *gin.Context) {
 		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
 		return
 	}
-	ar, err := m.FindArticle(id)
+	ar, err := m.FindArticle(c.Request.Context(), id)
 	if err != nil {
 		msg := fmt.Sprintf("Update article error: %v", err)
-		log.Println(msg)
+		logger(c).Warn("Update article error", "article_id", id, "error", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if !authorize(c, policy.UpdateArticle(currentActor(c), articleResource(ar))) {
+		return
+	}
+	if isFormRequest(c) {
+		// the form always submits all the fields, so validate them all like on creating
+		ar.Title, ar.Text = c.PostForm("title"), c.PostForm("text")
+		err = ar.Save(c.Request.Context())
+		if err == nil {
+			_, err = m.SetArticleTags(c.Request.Context(), ar.Id, *formTags(c))
+		}
+		if err != nil {
+			renderArticleForm(c, http.StatusUnprocessableEntity, ar, fmt.Sprintf("Update article error: %v", err))
+			return
+		}
+		saveRevision(c, ar.Id)
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", ar.Id), "notice", "Article updated")
+		return
 	}
 	am := map[string]interface{}{}
-	var json m.Article
+	var json articleParams
 	if c.BindJSON(&json) == nil {
 		if json.Title != "" {
 			am["title"] = json.Title
@@ -84,13 +334,20 @@ func ArticlesUpdate(c *gin.Context) {
 			am["text"] = json.Text
 		}
 	}
-	err = ar.Update(am)
+	tags, err := json.tagNames()
+	if err == nil && (len(am) > 0 || tags == nil) {
+		err = ar.Update(c.Request.Context(), am)
+	}
+	if err == nil && tags != nil {
+		_, err = m.SetArticleTags(c.Request.Context(), ar.Id, tags)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Update article error: %v", err)
-		log.Println(msg)
+		logger(c).Warn("Update article error", "article_id", ar.Id, "error", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
+	saveRevision(c, ar.Id)
 	resp := BuildResp("200", "Update article success", nil)
 	c.JSON(http.StatusOK, resp)
 }
@@ -102,9 +359,28 @@ func ArticlesDestroy(c *gin.Context) {
 		c.JSON(http.StatusOK, BuildResp("400", "Params error!", nil))
 		return
 	}
-	err = m.DestroyArticle(id)
+	ar, err := m.FindArticle(c.Request.Context(), id)
 	if err != nil {
-		fmt.Println(err)
+		msg := fmt.Sprintf("Destroy article error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if !authorize(c, policy.DestroyArticle(currentActor(c), articleResource(ar))) {
+		return
+	}
+	if err = m.DestroyArticle(c.Request.Context(), id); err != nil {
+		msg := fmt.Sprintf("Destroy article error: %v", err)
+		logger(c).Error("Destroy article error", "article_id", id, "error", err)
+		if isFormRequest(c) {
+			redirectWithFlash(c, fmt.Sprintf("/articles/%d", id), "alert", msg)
+			return
+		}
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if isFormRequest(c) {
+		redirectWithFlash(c, "/articles", "notice", "Article destroyed")
+		return
 	}
 	resp := BuildResp("200", "Article destroied", nil)
 	c.JSON(http.StatusOK, resp)
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	m "../src/models"
+	"../src/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditLimit is the most events returned at once by the audit log.
+const maxAuditLimit = 500
+
+// Audit is a middleware recording the writes of the request in the audit log with the client and the request id,
+// it goes after the authentication.
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := m.AuditInfo{Actor: "anonymous", RequestId: RequestId(c), IP: c.ClientIP()}
+		if p := CurrentPrincipal(c); p != nil {
+			info.ActorId, info.Actor = p.UserId, p.Kind+":"+p.Subject
+		}
+		c.Request = c.Request.WithContext(m.WithAudit(c.Request.Context(), info))
+		c.Next()
+	}
+}
+
+// GET /audit?resource=articles&id=42&actor_id=1&request_id=x&before_id=100&limit=50, the latest events first
+func AuditIndex(c *gin.Context) {
+	if !authorize(c, policy.ReadAudit(currentActor(c))) {
+		return
+	}
+	q := m.AuditQuery{Resource: c.Query("resource"), RequestId: c.Query("request_id")}
+	for param, v := range map[string]*int64{"id": &q.RecordId, "actor_id": &q.ActorId, "before_id": &q.BeforeId} {
+		if s := c.Query(param); s != "" {
+			var err error
+			if *v, err = ToInt(s); err != nil {
+				c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Parsing %s error!", param), nil))
+				return
+			}
+		}
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > maxAuditLimit {
+		c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("The limit should be 1 to %d", maxAuditLimit), nil))
+		return
+	}
+	where, args, err := q.Where()
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", err.Error(), nil))
+		return
+	}
+	events, err := m.FindAuditEventsWhere(c.Request.Context(), where+fmt.Sprintf(" ORDER BY id DESC LIMIT %d", limit), args...)
+	if err != nil {
+		msg := fmt.Sprintf("Get audit events error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	resp := BuildResp("200", "Get audit events success", events)
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"../src/auth"
+	m "../src/models"
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey is the key of the authenticated Principal in the gin context.
+const principalKey = "principal"
+
+// userKey is the key of the User of the Principal in the gin context, loaded on demand.
+const userKey = "user"
+
+// Principal is the client authenticated by an API key or a JWT.
+type Principal struct {
+	// Kind is "api_key", "jwt" or "session".
+	Kind string
+	// Subject is the API key name, the "sub" claim of the JWT or the user id of the session.
+	Subject  string
+	ApiKeyId int64
+	Claims   *auth.Claims
+	// UserId is the user the API key belongs to or the user id in the "sub" claim, 0 for none.
+	UserId int64
+}
+
+// Authenticate is a middleware to authenticate the client by the Authorization: Bearer header,
+// which is a JWT or an API key, by the X-API-Key header, or by the session of the login form.
+// Requests with invalid credentials are rejected, and so are the requests other than
+// GET, HEAD and OPTIONS without credentials. jwt can be nil to accept API keys only.
+func Authenticate(jwt *auth.JWTVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-API-Key")
+		if h := c.GetHeader("Authorization"); token == "" && h != "" {
+			if !strings.HasPrefix(h, "Bearer ") {
+				unauthorized(c, "Unsupported authorization scheme")
+				return
+			}
+			token = strings.TrimSpace(strings.TrimPrefix(h, "Bearer "))
+		}
+		if token == "" {
+			// a browser signed in by the login form, whose writes must carry the CSRF token
+			if s := CurrentSession(c); s.UserId != 0 {
+				if !isReadMethod(c.Request.Method) && !validCSRF(c, s) {
+					forbidden(c, "Invalid CSRF token")
+					return
+				}
+				c.Set(principalKey, &Principal{Kind: "session", Subject: ToStr(s.UserId), UserId: s.UserId})
+				c.Next()
+				return
+			}
+			if !isReadMethod(c.Request.Method) {
+				unauthorized(c, "Authentication required")
+				return
+			}
+			c.Next()
+			return
+		}
+		var p *Principal
+		if strings.Count(token, ".") == 2 {
+			if jwt == nil {
+				unauthorized(c, "JWT authentication is not enabled")
+				return
+			}
+			claims, err := jwt.Verify(token)
+			if err != nil {
+				logger(c).Warn("JWT verification error", "error", err)
+				unauthorized(c, "Invalid credentials")
+				return
+			}
+			p = &Principal{Kind: "jwt", Subject: claims.Subject, Claims: claims}
+			p.UserId, _ = strconv.ParseInt(claims.Subject, 10, 64)
+		} else {
+			ak, err := auth.VerifyAPIKey(c.Request.Context(), token)
+			if err != nil {
+				logger(c).Warn("API key verification error", "error", err)
+				unauthorized(c, "Invalid credentials")
+				return
+			}
+			p = &Principal{Kind: "api_key", Subject: ak.Name, ApiKeyId: ak.Id, UserId: ak.UserId}
+		}
+		c.Set(principalKey, p)
+		c.Next()
+	}
+}
+
+// CurrentPrincipal get the authenticated client of the request, nil if anonymous.
+func CurrentPrincipal(c *gin.Context) *Principal {
+	if v, ok := c.Get(principalKey); ok {
+		return v.(*Principal)
+	}
+	return nil
+}
+
+// CurrentUser get the user of the authenticated client, nil if anonymous or the client is not a user.
+func CurrentUser(c *gin.Context) *m.User {
+	if v, ok := c.Get(userKey); ok {
+		return v.(*m.User)
+	}
+	var u *m.User
+	if p := CurrentPrincipal(c); p != nil && p.UserId != 0 {
+		u, _ = m.FindUser(c.Request.Context(), p.UserId)
+	}
+	c.Set(userKey, u)
+	return u
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// forbidden reject the request with 403, or back to the articles with the reason for the pages.
+func forbidden(c *gin.Context, msg string) {
+	if isFormRequest(c) || wantsHTML(c) {
+		redirectWithFlash(c, "/articles", "alert", msg)
+		c.Abort()
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, BuildResp("403", msg, nil))
+}
+
+// unauthorized reject the request with 401, or to the login page for the pages.
+func unauthorized(c *gin.Context, msg string) {
+	if isFormRequest(c) || wantsHTML(c) {
+		redirectWithFlash(c, "/login", "alert", msg)
+		c.Abort()
+		return
+	}
+	c.Header("WWW-Authenticate", `Bearer realm="myapp"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, BuildResp("401", msg, nil))
+}
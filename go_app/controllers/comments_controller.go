@@ -1,69 +1,225 @@
 package controllers
 
 import (
+	"database/sql"
 	"fmt"
-	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	m "../src/models"
+	"../src/moderation"
+	"../src/policy"
 	"github.com/gin-gonic/gin"
 )
 
-// GET /articles/1/comments
+// CommentOrphans is what happens to the replies of a destroyed comment, main sets it by a flag.
+var CommentOrphans = m.Reparent
+
+// maxCommentDepth is the most levels of replies in a comment tree, and the default of the depth param.
+const maxCommentDepth = 5
+
+// GET /articles/1/comments, with tree=true&depth=3 to nest the replies
 func CommentsIndex(c *gin.Context) {
+	if !authorize(c, policy.ReadComment(currentActor(c))) {
+		return
+	}
 	id, err := ToInt(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
 		return
 	}
-	Comments, err := m.FindCommentsBy("article_id", id)
+	_, err = findVisibleArticle(c, id)
+	var Comments []m.Comment
+	if err == nil {
+		where, args := visibleComments(c, "article_id = ?", id)
+		Comments, err = m.FindCommentsWhere(c.Request.Context(), where, args...)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Get Comment index error: %v", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
+	if tree, _ := strconv.ParseBool(c.Query("tree")); tree {
+		depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(maxCommentDepth)))
+		if err != nil || depth < 1 || depth > maxCommentDepth {
+			c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("The depth should be 1 to %d", maxCommentDepth), nil))
+			return
+		}
+		Comments = m.CommentTree(Comments, depth)
+	}
+	m.LoadCommentsHTML(c.Request.Context(), Comments)
 	resp := BuildResp("200", "Get Comment index success", Comments)
 	c.JSON(http.StatusOK, resp)
 }
 
-// GET /comments/1
+// GET /articles/1/comments/export?format=ndjson|csv
+func CommentsExport(c *gin.Context) {
+	if !authorize(c, policy.ReadComment(currentActor(c))) {
+		return
+	}
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+		return
+	}
+	if _, err = findVisibleArticle(c, id); err != nil {
+		msg := fmt.Sprintf("Export Comment error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	header := []string{"id", "article_id", "commenter", "body", "created_at", "updated_at"}
+	ex, err := newExporter(c, fmt.Sprintf("article-%d-comments", id), header)
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", err.Error(), nil))
+		return
+	}
+	where, args := visibleComments(c, "article_id = ?", id)
+	err = m.CommentsInBatchesWhere(c.Request.Context(), exportBatchSize, where, args, func(comments []m.Comment) error {
+		for _, cm := range comments {
+			row := []string{ToStr(cm.Id), ToStr(cm.ArticleId), cm.Commenter, cm.Body, ToTimeStr(cm.CreatedAt), ToTimeStr(cm.UpdatedAt)}
+			if err := ex.Write(cm, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	ex.Close(err)
+}
+
+// GET /comments/1?render=html|markdown|plain
 func CommentsShow(c *gin.Context) {
+	if !authorize(c, policy.ReadComment(currentActor(c))) {
+		return
+	}
 	id, err := ToInt(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
 		return
 	}
-	Comment, err := m.FindComment(id)
+	Comment, err := m.FindComment(c.Request.Context(), id)
+	if err == nil && !commentVisible(c, Comment) {
+		err = sql.ErrNoRows
+	}
+	if err == nil {
+		// the comments of an article which isn't visible aren't either
+		_, err = findVisibleArticle(c, Comment.ArticleId)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Get Comment error: %v", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
+	Comment.LoadBodyHTML(c.Request.Context())
+	if !applyRender(c, &Comment.Body, &Comment.BodyHtml) {
+		return
+	}
 	resp := BuildResp("200", "Get Comment success", Comment)
 	c.JSON(http.StatusOK, resp)
 }
 
+// GET /articles/1/comments/new?parent_id=2
 func CommentsNew(c *gin.Context) {
+	if !requireLogin(c) || !authorize(c, policy.CreateComment(currentActor(c))) {
+		return
+	}
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		redirectWithFlash(c, "/articles", "alert", "Parsing id error!")
+		return
+	}
+	parentId, _ := ToInt(c.Query("parent_id"))
+	renderCommentForm(c, http.StatusOK, &m.Comment{ArticleId: id, ParentId: parentId}, "")
 }
 
+// GET /comments/1/edit
 func CommentsEdit(c *gin.Context) {
+	if !requireLogin(c) {
+		return
+	}
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		redirectWithFlash(c, "/articles", "alert", "Parsing id error!")
+		return
+	}
+	ar, err := m.FindComment(c.Request.Context(), id)
+	if err != nil {
+		redirectWithFlash(c, "/articles", "alert", fmt.Sprintf("Get Comment error: %v", err))
+		return
+	}
+	if !authorize(c, policy.UpdateComment(currentActor(c), commentResource(ar), time.Now())) {
+		return
+	}
+	renderCommentForm(c, http.StatusOK, ar, "")
+}
+
+// renderCommentForm render the form to create a new comment or edit an existing one.
+func renderCommentForm(c *gin.Context, code int, ar *m.Comment, errMsg string) {
+	data := gin.H{"Comment": ar, "Error": errMsg}
+	if ar.Id == 0 && ar.ParentId != 0 {
+		data["Title"], data["Action"], data["Method"] = "Reply", "/comments", "POST"
+	} else if ar.Id == 0 {
+		data["Title"], data["Action"], data["Method"] = "New comment", "/comments", "POST"
+	} else {
+		data["Title"], data["Action"], data["Method"] = "Edit comment", fmt.Sprintf("/comments/%d", ar.Id), "PUT"
+	}
+	renderHTML(c, code, "comments_form.tmpl", data)
 }
 
 // POST /comments
 func CommentsCreate(c *gin.Context) {
+	if !authorize(c, policy.CreateComment(currentActor(c))) {
+		return
+	}
 	var ar m.Comment
-	if c.BindJSON(&ar) != nil {
-		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+	form := isFormRequest(c)
+	if form {
+		ar.Body = c.PostForm("body")
+		ar.ArticleId, _ = ToInt(c.PostForm("article_id"))
+		ar.ParentId, _ = ToInt(c.PostForm("parent_id"))
+	} else if err := c.ShouldBindJSON(&ar); err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Parsing JSON error: %v", err), nil))
 		return
 	}
-	id, err := ar.Create()
+	ar.UserId = 0
+	if u := CurrentUser(c); u != nil {
+		// the commenter of a user can't be spoofed
+		ar.UserId, ar.Commenter = u.Id, u.Name
+	}
+	// the comments are on the articles the commenter can see
+	_, err := findVisibleArticle(c, ar.ArticleId)
+	if err == nil && ar.ParentId != 0 {
+		// a reply is to a comment of the same article the commenter can see
+		var parent *m.Comment
+		parent, err = m.FindComment(c.Request.Context(), ar.ParentId)
+		if err == nil && (parent.ArticleId != ar.ArticleId || !commentVisible(c, parent)) {
+			err = fmt.Errorf("comment %d is not on article %d", ar.ParentId, ar.ArticleId)
+		}
+	}
+	var id int64
+	if err == nil {
+		moderateComment(c, &ar)
+		id, err = ar.Create(c.Request.Context())
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Create Comment error: %v", err)
-		log.Println(msg)
+		logger(c).Warn("Create comment error", "error", err)
+		if form {
+			renderCommentForm(c, http.StatusUnprocessableEntity, &ar, msg)
+			return
+		}
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
-	c.JSON(http.StatusOK, BuildResp("200", "Create Comment success", map[string]int64{"id": id}))
+	if form {
+		msg := "Comment created"
+		if ar.Status != moderation.Approved {
+			msg = "Comment awaiting moderation"
+		}
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", ar.ArticleId), "notice", msg)
+		return
+	}
+	c.JSON(http.StatusOK, BuildResp("200", "Create Comment success", map[string]interface{}{"id": id, "status": ar.Status}))
 }
 
 // PUT /comments/1
@@ -73,29 +229,43 @@ func CommentsUpdate(c *gin.Context) {
 		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
 		return
 	}
-	ar, err := m.FindComment(id)
+	ar, err := m.FindComment(c.Request.Context(), id)
 	if err != nil {
 		msg := fmt.Sprintf("Update Comment error: %v", err)
-		log.Println(msg)
+		logger(c).Warn("Update comment error", "comment_id", id, "error", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if !authorize(c, policy.UpdateComment(currentActor(c), commentResource(ar), time.Now())) {
+		return
+	}
+	if isFormRequest(c) {
+		ar.Body = c.PostForm("body")
+		moderateComment(c, ar)
+		if err = ar.Save(c.Request.Context()); err != nil {
+			renderCommentForm(c, http.StatusUnprocessableEntity, ar, fmt.Sprintf("Update Comment error: %v", err))
+			return
+		}
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", ar.ArticleId), "notice", "Comment updated")
+		return
 	}
 	am := map[string]interface{}{}
 	var json m.Comment
 	if c.BindJSON(&json) == nil {
-		if json.Commenter != "" {
+		if json.Commenter != "" && ar.UserId == 0 {
 			am["commenter"] = json.Commenter
 		}
-		if json.Body != "" {
-			am["body"] = json.Body
-		}
-		if json.ArticleId != 0 {
-			am["article_id"] = json.ArticleId
+		if json.Body != "" && json.Body != ar.Body {
+			// an edited comment is moderated again
+			ar.Body = json.Body
+			moderateComment(c, ar)
+			am["body"], am["status"], am["spam_score"] = ar.Body, ar.Status, ar.SpamScore
 		}
 	}
-	err = ar.Update(am)
+	err = ar.Update(c.Request.Context(), am)
 	if err != nil {
 		msg := fmt.Sprintf("Update Comment error: %v", err)
-		log.Println(msg)
+		logger(c).Warn("Update comment error", "comment_id", ar.Id, "error", err)
 		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
 		return
 	}
@@ -110,9 +280,28 @@ func CommentsDestroy(c *gin.Context) {
 		c.JSON(http.StatusOK, BuildResp("400", "Params error!", nil))
 		return
 	}
-	err = m.DestroyComment(id)
+	ar, err := m.FindComment(c.Request.Context(), id)
 	if err != nil {
-		fmt.Println(err)
+		msg := fmt.Sprintf("Destroy Comment error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if !authorize(c, policy.DestroyComment(currentActor(c), commentResource(ar))) {
+		return
+	}
+	if err = m.DestroyCommentInThread(c.Request.Context(), ar, CommentOrphans); err != nil {
+		msg := fmt.Sprintf("Destroy Comment error: %v", err)
+		logger(c).Error("Destroy comment error", "comment_id", id, "error", err)
+		if isFormRequest(c) {
+			redirectWithFlash(c, fmt.Sprintf("/articles/%d", ar.ArticleId), "alert", msg)
+			return
+		}
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if isFormRequest(c) {
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", ar.ArticleId), "notice", "Comment destroyed")
+		return
 	}
 	resp := BuildResp("200", "Comment destroied", nil)
 	c.JSON(http.StatusOK, resp)
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"strconv"
+	"time"
 )
 
 type Resp struct {
@@ -18,6 +19,28 @@ func ToInt(s string) (int64, error) {
 	return res, err
 }
 
+func ToStr(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+// ToTimeStr format a time in RFC3339, the same layout as the JSON encoding of time.Time.
+func ToTimeStr(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// ParseTime parse a time in RFC3339, a local time like 2006-01-02T15:04 as sent by the datetime-local inputs,
+// or a date like 2006-01-02.
+func ParseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02T15:04", s, time.Local)
+	}
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", s, time.Local)
+	}
+	return t, err
+}
+
 func BuildResp(code, msg string, data interface{}) *Resp {
 	return &Resp{code, msg, data}
 }
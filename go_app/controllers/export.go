@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportBatchSize is the number of records loaded from the database at a time by the export actions.
+const exportBatchSize = 500
+
+// exportFlushEvery is the number of records written before the response is flushed to the client,
+// so the response goes out in chunks instead of being buffered all.
+const exportFlushEvery = 100
+
+// exporter streams records to the response as NDJSON or CSV according to the "format" query param.
+// The status and the headers are only sent with the first record, or on Close for none,
+// so an export failing on its first query still gets the error response.
+type exporter struct {
+	c       *gin.Context
+	name    string
+	format  string
+	header  []string
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+	count   int
+}
+
+// exportIncomplete is the last line of a NDJSON export failing half way, so the clients can tell it's truncated.
+var exportIncomplete = gin.H{"error": "The export failed, the records above are incomplete"}
+
+// newExporter check the requested format, the header fields are used as the first line of a CSV export.
+func newExporter(c *gin.Context, name string, header []string) (*exporter, error) {
+	format := c.DefaultQuery("format", "ndjson")
+	if format != "ndjson" && format != "csv" {
+		return nil, fmt.Errorf("Unsupported export format: %s", format)
+	}
+	return &exporter{c: c, name: name, format: format, header: header}, nil
+}
+
+// start write the status, the headers and the CSV header line.
+func (ex *exporter) start() error {
+	ex.started = true
+	c := ex.c
+	if ex.format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		ex.csv = csv.NewWriter(c.Writer)
+	} else {
+		c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+		ex.json = json.NewEncoder(c.Writer)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, ex.name, ex.format))
+	c.Status(http.StatusOK)
+	if ex.csv != nil {
+		return ex.csv.Write(ex.header)
+	}
+	return nil
+}
+
+// Write write a single record, v is used for NDJSON and row for CSV.
+func (ex *exporter) Write(v interface{}, row []string) (err error) {
+	if !ex.started {
+		if err = ex.start(); err != nil {
+			return err
+		}
+	}
+	if ex.csv != nil {
+		err = ex.csv.Write(row)
+	} else {
+		err = ex.json.Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+	ex.count++
+	if ex.count%exportFlushEvery == 0 {
+		return ex.flush()
+	}
+	return nil
+}
+
+// Close flush the remaining records. An export failing before any record responds with the error,
+// one failing half way already sent its status, so the error is logged and a NDJSON export ends with exportIncomplete.
+func (ex *exporter) Close(err error) {
+	if !ex.started && err != nil {
+		logger(ex.c).Error("Export error", "path", ex.c.Request.URL.Path, "error", err)
+		ex.c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Export error: %v", err), nil))
+		return
+	}
+	if !ex.started {
+		err = ex.start()
+	}
+	if ferr := ex.flush(); err == nil {
+		err = ferr
+	}
+	if err == nil {
+		return
+	}
+	logger(ex.c).Error("Export error", "path", ex.c.Request.URL.Path, "records", ex.count, "error", err)
+	if ex.json != nil {
+		ex.json.Encode(exportIncomplete)
+		ex.c.Writer.Flush()
+	}
+}
+
+// flush send the records written so far, it tells the error of the CSV writer if any.
+func (ex *exporter) flush() error {
+	var err error
+	if ex.csv != nil {
+		ex.csv.Flush()
+		err = ex.csv.Error()
+	}
+	ex.c.Writer.Flush()
+	return err
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"../src/health"
+	m "../src/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Health runs the checks of /readyz, main adds them and drains it on shutdown.
+var Health = health.NewChecker(2 * time.Second)
+
+// CheckDatabase ping the database.
+func CheckDatabase(ctx context.Context) (interface{}, error) {
+	return nil, m.DB.PingContext(ctx)
+}
+
+// CheckMigrations fail while a migration the models rely on isn't run yet.
+func CheckMigrations(ctx context.Context) (interface{}, error) {
+	latest, err := m.SchemaStatus(ctx)
+	return gin.H{"expected": m.SchemaVersion, "latest": latest}, err
+}
+
+// CheckPool fail when the connections in use of the database pool reach the saturation ratio of its max,
+// the requests would wait for a connection then. It always passes with no max.
+func CheckPool(saturation float64) health.CheckFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		s := m.DB.Stats()
+		detail := gin.H{"open": s.OpenConnections, "in_use": s.InUse, "idle": s.Idle, "max_open": s.MaxOpenConnections, "wait_count": s.WaitCount}
+		if s.MaxOpenConnections > 0 && float64(s.InUse) >= saturation*float64(s.MaxOpenConnections) {
+			return detail, fmt.Errorf("%d of %d connections in use", s.InUse, s.MaxOpenConnections)
+		}
+		return detail, nil
+	}
+}
+
+// GET /healthz, the process is alive
+func HealthzIndex(c *gin.Context) {
+	c.JSON(http.StatusOK, BuildResp("200", "OK", gin.H{"status": health.Pass}))
+}
+
+// GET /readyz, 503 with the failed checks when the app can't serve, e.g. the database is down or it's shutting down
+func ReadyzIndex(c *gin.Context) {
+	ready, checks := Health.Ready(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, BuildResp("503", "Not ready", gin.H{"status": health.Fail, "checks": checks}))
+		return
+	}
+	c.JSON(http.StatusOK, BuildResp("200", "OK", gin.H{"status": health.Pass, "checks": checks}))
+}
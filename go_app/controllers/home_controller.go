@@ -11,7 +11,7 @@ import (
 func HomeHandler(c *gin.Context) {
 	// you can use model functions to do CRUD
 	//
-	// user, _ := m.FindUser(1)
+	// user, _ := m.FindUser(c.Request.Context(), 1)
 	// u, err := json.Marshal(user)
 	// if err != nil {
 	// 	log.Printf("JSON encoding error: %v\n", err)
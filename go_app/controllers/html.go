@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// wantsHTML tell a request from a browser rather than an API client, by the Accept header.
+func wantsHTML(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+}
+
+// isFormRequest tell a request submitted by a HTML form of the server rendered pages.
+func isFormRequest(c *gin.Context) bool {
+	ct := c.ContentType()
+	return ct == gin.MIMEPOSTForm || ct == gin.MIMEMultipartPOSTForm
+}
+
+// renderHTML render a page with the data shared by all the pages: the current user,
+// the flash messages and the CSRF token for the forms.
+func renderHTML(c *gin.Context, code int, name string, data gin.H) {
+	s := CurrentSession(c)
+	if data == nil {
+		data = gin.H{}
+	}
+	data["CurrentUser"] = CurrentUser(c)
+	data["Actor"] = currentActor(c)
+	data["Flashes"] = s.TakeFlashes()
+	data["CSRF"] = s.CSRFToken()
+	c.HTML(code, name, data)
+}
+
+// redirectWithFlash redirect to location after a form submission with a flash message.
+func redirectWithFlash(c *gin.Context, location, kind, msg string) {
+	CurrentSession(c).AddFlash(kind, msg)
+	c.Redirect(http.StatusSeeOther, location)
+}
+
+// requireLogin redirect to the login page unless a user is signed in.
+func requireLogin(c *gin.Context) bool {
+	if CurrentUser(c) == nil {
+		redirectWithFlash(c, "/login", "alert", "Please sign in first")
+		return false
+	}
+	return true
+}
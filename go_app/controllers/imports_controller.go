@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	imp "../src/importer"
+	"../src/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// POST /imports?resource=articles&format=ndjson&dry_run=true&upsert_by=title
+// The records are read from the request body, or from the "file" field of a multipart form.
+func ImportsCreate(c *gin.Context) {
+	if !authorize(c, policy.Import(currentActor(c))) {
+		return
+	}
+	opt := imp.Options{
+		Resource:   c.Query("resource"),
+		Format:     c.Query("format"),
+		DryRun:     c.Query("dry_run") == "true" || c.Query("dry_run") == "1",
+		UpsertBy:   c.Query("upsert_by"),
+		ArticleKey: c.Query("article_key"),
+	}
+	var in io.Reader = c.Request.Body
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Open upload file error: %v", err), nil))
+			return
+		}
+		defer f.Close()
+		in = f
+		if opt.Format == "" {
+			opt.Format = imp.FormatOf(fh.Filename)
+		}
+	}
+	if opt.Format == "" {
+		opt.Format = formatOfContentType(c.ContentType())
+	}
+	report, err := imp.Run(c.Request.Context(), in, opt)
+	if err != nil {
+		msg := fmt.Sprintf("Import error: %v", err)
+		logger(c).Warn("Import error", "resource", opt.Resource, "error", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	c.JSON(http.StatusOK, BuildResp("200", "Import finished", report))
+}
+
+func formatOfContentType(ct string) string {
+	switch ct {
+	case "text/csv":
+		return "csv"
+	case "application/x-ndjson", "application/json":
+		return "ndjson"
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"../src/logging"
+	m "../src/models"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIdKey is the key of the request id in the gin context.
+const requestIdKey = "request_id"
+
+// validRequestId is the X-Request-ID of the clients kept as the id of the request, the others are replaced.
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestId get the id of the request: the X-Request-ID header of the client, or a new random one.
+// It's sent back in the X-Request-ID header of the response.
+func RequestId(c *gin.Context) string {
+	if v, ok := c.Get(requestIdKey); ok {
+		return v.(string)
+	}
+	id := c.GetHeader("X-Request-ID")
+	if !validRequestId.MatchString(id) {
+		b := make([]byte, 16)
+		rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	c.Set(requestIdKey, id)
+	c.Header("X-Request-ID", id)
+	return id
+}
+
+// Logger is a middleware giving the request an id and a logger with it, then logging the request when it's done
+// with its route, status, latency, user and the rows written. It goes after the tracing and before the recovery.
+func Logger(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		l := base.With("request_id", RequestId(c))
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.HasTraceID() {
+			l = l.With("trace_id", sc.TraceID().String())
+		}
+		ctx, rows := m.CountWrites(logging.WithLogger(c.Request.Context(), l))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method, "route", c.FullPath(), "path", c.Request.URL.Path,
+			"status", status, "latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"size", c.Writer.Size(), "ip", c.ClientIP(), "rows_affected", rows(),
+		}
+		if p := CurrentPrincipal(c); p != nil {
+			attrs = append(attrs, "actor", p.Kind+":"+p.Subject, "user_id", p.UserId)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		} else if status >= http.StatusBadRequest {
+			level = slog.LevelWarn
+		}
+		l.Log(c.Request.Context(), level, "Request", attrs...)
+	}
+}
+
+// Recovery is a middleware responding 500 when a handler panics, the panic is logged with the stack.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		logger(c).Error("Panic", "error", err, "stack", string(debug.Stack()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, BuildResp("500", "Internal server error", nil))
+	})
+}
+
+// logger get the logger of the request, with its id.
+func logger(c *gin.Context) *slog.Logger {
+	return logging.FromContext(c.Request.Context())
+}
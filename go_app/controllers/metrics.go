@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"time"
+
+	"../src/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics is a middleware counting the requests by their route and status with their latency.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.ObserveRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
+
+var metricsHandler = metrics.Handler()
+
+// GET /metrics, in the Prometheus text format
+func MetricsIndex(c *gin.Context) {
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
+}
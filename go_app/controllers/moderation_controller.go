@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	m "../src/models"
+	"../src/moderation"
+	"../src/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentModeration scores the new comments, main replaces it to set the blocklist.
+var CommentModeration = moderation.NewPipeline(nil, CommentHistory{})
+
+// CommentHistory is the moderation.History on the comments table.
+type CommentHistory struct{}
+
+// CountBody count the comments with the same body since the time.
+func (CommentHistory) CountBody(ctx context.Context, body string, since time.Time) (int64, error) {
+	return m.CommentCountWhere(ctx, "body = ? AND created_at >= ?", body, since)
+}
+
+// CountByCommenter count the comments of the same user, or the same commenter name if none, since the time.
+func (CommentHistory) CountByCommenter(ctx context.Context, cm moderation.Comment, since time.Time) (int64, error) {
+	if cm.UserId != 0 {
+		return m.CommentCountWhere(ctx, "user_id = ? AND created_at >= ?", cm.UserId, since)
+	}
+	return m.CommentCountWhere(ctx, "commenter = ? AND created_at >= ?", cm.Commenter, since)
+}
+
+// moderateComment set the status and the spam score of a new or edited comment,
+// the comments of the moderators are approved right away.
+func moderateComment(c *gin.Context, cm *m.Comment) {
+	if policy.ModerateComment(currentActor(c)).Allowed {
+		cm.Status, cm.SpamScore = moderation.Approved, 0
+		return
+	}
+	v, err := CommentModeration.Run(c.Request.Context(), moderation.Comment{
+		ArticleId: cm.ArticleId,
+		UserId:    cm.UserId,
+		Commenter: cm.Commenter,
+		Body:      cm.Body,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger(c).Error("Moderate comment error", "error", err)
+	}
+	if len(v.Signals) != 0 {
+		logger(c).Info("Comment scored", "commenter", cm.Commenter, "score", v.Score, "signals", v.Signals)
+	}
+	cm.Status, cm.SpamScore = v.Status, v.Score
+}
+
+// visibleComments restrict the where clause to the comments the current user can see:
+// the approved ones and the own pending ones, or all of them for the moderators.
+func visibleComments(c *gin.Context, where string, args ...interface{}) (string, []interface{}) {
+	a := currentActor(c)
+	if policy.ModerateComment(a).Allowed {
+		return where, args
+	}
+	vis, vargs := "status = ?", []interface{}{moderation.Approved}
+	if a != nil {
+		vis, vargs = "(status = ? OR (status = ? AND user_id = ?))", []interface{}{moderation.Approved, moderation.Pending, a.Id}
+	}
+	if where == "" {
+		return vis, vargs
+	}
+	return where + " AND " + vis, append(args, vargs...)
+}
+
+// commentVisible tell whether the current user can see the comment, as visibleComments does.
+func commentVisible(c *gin.Context, cm *m.Comment) bool {
+	a := currentActor(c)
+	return cm.Status == moderation.Approved || policy.ModerateComment(a).Allowed ||
+		(a != nil && cm.Status == moderation.Pending && cm.UserId == a.Id)
+}
+
+// GET /moderation/comments?status=pending&article_id=1
+func ModerationComments(c *gin.Context) {
+	if !authorize(c, policy.ModerateComment(currentActor(c))) {
+		return
+	}
+	status := c.DefaultQuery("status", moderation.Pending)
+	if !moderation.ValidStatus(status) {
+		c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Unknown status %q", status), nil))
+		return
+	}
+	where, args := "status = ?", []interface{}{status}
+	if v := c.Query("article_id"); v != "" {
+		id, err := ToInt(v)
+		if err != nil {
+			c.JSON(http.StatusOK, BuildResp("400", "Parsing article_id error!", nil))
+			return
+		}
+		where, args = where+" AND article_id = ?", append(args, id)
+	}
+	comments, err := m.FindCommentsWhere(c.Request.Context(), where+" ORDER BY spam_score DESC, id ASC LIMIT 100", args...)
+	if err != nil {
+		msg := fmt.Sprintf("Get moderation queue error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	m.LoadCommentsHTML(c.Request.Context(), comments)
+	if wantsHTML(c) {
+		renderHTML(c, http.StatusOK, "moderation_comments.tmpl", gin.H{
+			"Title": "Moderation", "Status": status, "Statuses": moderation.Statuses, "Comments": comments,
+		})
+		return
+	}
+	resp := BuildResp("200", "Get moderation queue success", comments)
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /comments/1/approve
+func CommentsApprove(c *gin.Context) {
+	setCommentStatus(c, moderation.Approved)
+}
+
+// POST /comments/1/reject, with spam=true to mark it as spam
+func CommentsReject(c *gin.Context) {
+	status := moderation.Rejected
+	if spam, _ := strconv.ParseBool(c.DefaultPostForm("spam", c.Query("spam"))); spam {
+		status = moderation.Spam
+	}
+	setCommentStatus(c, status)
+}
+
+func setCommentStatus(c *gin.Context, status string) {
+	if !authorize(c, policy.ModerateComment(currentActor(c))) {
+		return
+	}
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+		return
+	}
+	cm, err := m.FindComment(c.Request.Context(), id)
+	if err == nil {
+		err = cm.Update(c.Request.Context(), map[string]interface{}{"status": status})
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Moderate Comment error: %v", err)
+		logger(c).Warn("Moderate comment error", "comment_id", id, "status", status, "error", err)
+		if isFormRequest(c) {
+			redirectWithFlash(c, "/moderation/comments", "alert", msg)
+			return
+		}
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if isFormRequest(c) {
+		to := c.PostForm("return_to")
+		if !localPath(to) {
+			to = "/moderation/comments"
+		}
+		redirectWithFlash(c, to, "notice", "Comment "+status)
+		return
+	}
+	c.JSON(http.StatusOK, BuildResp("200", "Comment "+status, map[string]string{"status": status}))
+}
+
+// localPath tell whether to is a path on this site, safe to redirect to.
+// The browsers read a backslash as a slash and drop the tabs and newlines, so "/\evil.com" goes off site.
+func localPath(to string) bool {
+	if !strings.HasPrefix(to, "/") || strings.HasPrefix(to, "//") {
+		return false
+	}
+	if strings.IndexFunc(to, func(r rune) bool { return r == '\\' || unicode.IsControl(r) }) >= 0 {
+		return false
+	}
+	u, err := url.Parse(to)
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
@@ -0,0 +1,335 @@
+package controllers
+
+import (
+	"net/http"
+	"sync"
+
+	"../src/health"
+	"../src/importer"
+	m "../src/models"
+	"../src/openapi"
+	"../src/search"
+	"../src/textdiff"
+	"github.com/gin-gonic/gin"
+)
+
+// The routes of main.go are documented here, a route missing from the document fails the test of main_test.go.
+// The schemas of the bodies are derived from the structs of the models, with the constraints of their valid tags.
+
+const apiDescription = "The responses of the JSON API are wrapped in an envelope with a code, a message and the data. " +
+	"The errors of the handlers are responded with the HTTP status 200 and the code 400 in the envelope, " +
+	"the authentication, authorization and rate limit errors with the HTTP status of their code. " +
+	"The index and show routes respond with HTML to the browsers asking for text/html."
+
+var (
+	apiSpec     *openapi.Spec
+	apiSpecOnce sync.Once
+)
+
+// APISpec get the OpenAPI document of the routes.
+func APISpec() *openapi.Spec {
+	apiSpecOnce.Do(func() {
+		apiSpec = buildAPISpec()
+	})
+	return apiSpec
+}
+
+// GET /openapi.json
+func OpenAPIShow(c *gin.Context) {
+	c.JSON(http.StatusOK, APISpec())
+}
+
+// GET /docs, the Swagger UI of /openapi.json
+func DocsShow(c *gin.Context) {
+	c.HTML(http.StatusOK, "docs.tmpl", gin.H{"Title": "API", "Spec": "/openapi.json"})
+}
+
+// The security schemes of the writes, any of them.
+var writeSecurity = []string{"bearer", "apiKey", "session"}
+
+func buildAPISpec() *openapi.Spec {
+	s := openapi.New("myapp", "1.0.0", apiDescription)
+	s.SecuritySchemes["bearer"] = openapi.Object{"type": "http", "scheme": "bearer", "description": "A JWT, or an API key created by myapp apikeys create"}
+	s.SecuritySchemes["apiKey"] = openapi.Object{"type": "apiKey", "in": "header", "name": "X-API-Key"}
+	s.SecuritySchemes["session"] = openapi.Object{"type": "apiKey", "in": "cookie", "name": SessionCookie,
+		"description": "The session of the login form, the writes need its CSRF token in the _csrf field or the X-CSRF-Token header"}
+
+	invalid := apiObject(map[string]openapi.Object{"errors": {"type": "array", "items": s.Schema(openapi.Violation{})}})
+	s.Invalid = map[string]openapi.Response{
+		"400": {Description: "The params or the body don't match their schemas, with the violations", Content: map[string]openapi.Object{"application/json": apiEnvelope(s, invalid)}},
+		"413": {Description: "The body is too large", Content: map[string]openapi.Object{"application/json": apiEnvelope(s, nil)}},
+		"415": {Description: "The body is of a media type the operation doesn't take", Content: map[string]openapi.Object{"application/json": apiEnvelope(s, nil)}},
+	}
+
+	id := openapi.Param{Name: "id", In: "path", Required: true, Schema: openapi.Object{"type": "integer", "format": "int64"}}
+	article := s.Schema(m.Article{})
+	comment := s.Schema(m.Comment{})
+	articleInput := s.Input(articleParams{}, "title", "text", "tags")
+	// the form has the tags comma separated
+	articleForm := s.Input(articleParams{}, "title", "text")
+	articleForm["properties"].(openapi.Object)["tags"] = openapi.Object{"type": "string", "description": "The comma separated tags"}
+	commentInput := s.Input(m.Comment{}, "commenter", "body", "article_id", "parent_id")
+	render := apiQuery("render", "The form of the text: html, markdown or plain, both the markdown and the HTML by default",
+		openapi.Object{"type": "string", "enum": []string{"html", "markdown", "plain"}})
+	format := apiQuery("format", "The format of the export", openapi.Object{"type": "string", "enum": []string{"ndjson", "csv"}, "default": "ndjson"})
+
+	// for the articles
+	articleFilters := []openapi.Param{
+		apiQuery("status", "The status of the articles, all for any, the unpublished ones are restricted to their authors but for the editors",
+			openapi.Object{"type": "string", "enum": []string{m.ArticleDraft, m.ArticleScheduled, m.ArticlePublished, m.ArticleArchived, "all"}, "default": m.ArticlePublished}),
+		apiQuery("title", "The exact title", openapi.Object{"type": "string"}),
+		apiQuery("created_after", "A RFC3339 time or a 2006-01-02 date", openapi.Object{"type": "string"}),
+		apiQuery("created_before", "A RFC3339 time or a 2006-01-02 date", openapi.Object{"type": "string"}),
+		{Name: "tag", In: "query", Description: "The tags of the articles, repeated for several", Schema: openapi.Object{"type": "array", "items": openapi.Object{"type": "string"}}},
+		apiQuery("tag_match", "Whether the articles have any or all of the tags", openapi.Object{"type": "string", "enum": []string{"any", "all"}, "default": "any"}),
+	}
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles", Id: "ArticlesIndex", Summary: "List the articles", Tags: []string{"articles"},
+		Params: articleFilters, Responses: apiPage(s, "The articles", openapi.Object{"type": "array", "items": article})})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/export", Id: "ArticlesExport", Summary: "Export the articles", Tags: []string{"articles"},
+		Params: append([]openapi.Param{format}, articleFilters...), Responses: apiExport(s, article)})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/new", Id: "ArticlesNew", Summary: "The form of a new article", Tags: []string{"pages"},
+		Responses: apiHTML("The form"), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id/edit", Id: "ArticlesEdit", Summary: "The form to edit an article", Tags: []string{"pages"},
+		Params: []openapi.Param{id}, Responses: apiHTML("The form"), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "POST", Path: "/articles", Id: "ArticlesCreate", Summary: "Create a draft article", Tags: []string{"articles"},
+		Body: apiBodies(articleInput, articleForm), Responses: apiWrite(s, "The id of the article", apiIdData()), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id", Id: "ArticlesShow", Summary: "Get an article", Tags: []string{"articles"},
+		Description: "The id can be the slug of the article, an old slug is redirected to the current one.",
+		Params:      []openapi.Param{{Name: "id", In: "path", Required: true, Description: "The id or the slug", Schema: openapi.Object{"type": "string"}}, render},
+		Responses:   apiWithRedirect(apiPage(s, "The article", article), http.StatusMovedPermanently, "The current slug of the article")})
+	s.Add(openapi.Operation{Method: "DELETE", Path: "/articles/:id", Id: "ArticlesDestroy", Summary: "Destroy an article", Tags: []string{"articles"},
+		Params: []openapi.Param{id}, Responses: apiWrite(s, "Destroyed", nil), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "PUT", Path: "/articles/:id", Id: "ArticlesUpdate", Summary: "Update an article", Tags: []string{"articles"},
+		Description: "The fields left empty are kept, and so are the tags if missing.",
+		Params:      []openapi.Param{id}, Body: apiBodies(apiPartial(articleInput), articleForm), Responses: apiWrite(s, "Updated", nil), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "POST", Path: "/articles/:id/publish", Id: "ArticlesPublish", Summary: "Publish an article, or schedule it", Tags: []string{"articles"},
+		Params: []openapi.Param{id, apiQuery("publish_at", "The time to publish the article at, now if missing", openapi.Object{"type": "string", "format": "date-time"})},
+		Body:   apiForm(openapi.Object{"type": "object", "properties": openapi.Object{"publish_at": openapi.Object{"type": "string", "format": "date-time"}}}),
+		Responses: apiWrite(s, "The status of the article", openapi.Object{"type": "object", "properties": openapi.Object{
+			"status": openapi.Object{"type": "string"}, "published_at": openapi.Object{"type": []string{"string", "null"}, "format": "date-time"}}}),
+		Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "POST", Path: "/articles/:id/unpublish", Id: "ArticlesUnpublish", Summary: "Unpublish an article back to a draft, or archive it", Tags: []string{"articles"},
+		Params: []openapi.Param{id, apiQuery("archive", "Archive the article instead", openapi.Object{"type": "boolean"})},
+		Body:   apiForm(openapi.Object{"type": "object", "properties": openapi.Object{"archive": openapi.Object{"type": "boolean"}}}),
+		Responses: apiWrite(s, "The status of the article", openapi.Object{"type": "object", "properties": openapi.Object{
+			"status": openapi.Object{"type": "string"}, "published_at": openapi.Object{"type": []string{"string", "null"}, "format": "date-time"}}}),
+		Security: writeSecurity})
+
+	// for the revisions of the articles
+	rev := openapi.Param{Name: "rev", In: "path", Required: true, Schema: openapi.Object{"type": "integer", "format": "int64"}}
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id/revisions", Id: "ArticlesRevisions", Summary: "List the revisions of an article", Tags: []string{"revisions"},
+		Params: []openapi.Param{id}, Responses: apiPage(s, "The revisions, the latest first", openapi.Object{"type": "array", "items": s.Schema(m.ArticleRevision{})})})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id/revisions/:rev/diff", Id: "ArticlesRevisionDiff", Summary: "Compare a revision with another one", Tags: []string{"revisions"},
+		Params: []openapi.Param{id, rev,
+			apiQuery("against", "The revision to compare with, the previous one by default", openapi.Object{"type": "integer", "format": "int64"}),
+			apiQuery("mode", "A unified diff of the lines, or the edits of the words", openapi.Object{"type": "string", "enum": []string{"unified", "words"}, "default": "unified"}),
+		},
+		Responses: apiPage(s, "The diff", openapi.Object{"type": "object", "properties": openapi.Object{
+			"rev":          openapi.Object{"type": "integer", "format": "int64"},
+			"against":      openapi.Object{"type": "integer", "format": "int64"},
+			"mode":         openapi.Object{"type": "string"},
+			"diff":         openapi.Object{"oneOf": []openapi.Object{{"type": "string"}, {"type": "array", "items": s.Schema(textdiff.Edit{})}}},
+			"title_change": openapi.Object{"type": "object", "properties": openapi.Object{"from": openapi.Object{"type": "string"}, "to": openapi.Object{"type": "string"}}},
+		}})})
+	s.Add(openapi.Operation{Method: "POST", Path: "/articles/:id/revisions/:rev/restore", Id: "ArticlesRevisionRestore", Summary: "Restore an article to a revision", Tags: []string{"revisions"},
+		Params: []openapi.Param{id, rev}, Responses: apiWrite(s, "The new revision", openapi.Object{"type": "object", "properties": openapi.Object{"rev": openapi.Object{"type": "integer", "format": "int64"}}}),
+		Security: writeSecurity})
+
+	// for the tags
+	s.Add(openapi.Operation{Method: "GET", Path: "/tags", Id: "TagsIndex", Summary: "List the tags with their counts of articles", Tags: []string{"tags"},
+		Responses: apiPage(s, "The tags", openapi.Object{"type": "array", "items": s.Schema(m.TagUsage{})})})
+
+	// for the comments
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id/comments", Id: "CommentsIndex", Summary: "List the comments of an article", Tags: []string{"comments"},
+		Params: []openapi.Param{id,
+			apiQuery("tree", "Nest the replies in their comments", openapi.Object{"type": "boolean"}),
+			apiQuery("depth", "The depth of the nested replies", openapi.Object{"type": "integer", "minimum": 1, "maximum": maxCommentDepth, "default": maxCommentDepth}),
+		},
+		Responses: apiJSON(s, "The comments", openapi.Object{"type": "array", "items": comment})})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id/comments/export", Id: "CommentsExport", Summary: "Export the comments of an article", Tags: []string{"comments"},
+		Params: []openapi.Param{id, format}, Responses: apiExport(s, comment)})
+	s.Add(openapi.Operation{Method: "GET", Path: "/articles/:id/comments/new", Id: "CommentsNew", Summary: "The form of a new comment", Tags: []string{"pages"},
+		Params:    []openapi.Param{id, apiQuery("parent_id", "The comment replied to", openapi.Object{"type": "integer", "format": "int64"})},
+		Responses: apiHTML("The form"), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "GET", Path: "/comments/:id/edit", Id: "CommentsEdit", Summary: "The form to edit a comment", Tags: []string{"pages"},
+		Params: []openapi.Param{id}, Responses: apiHTML("The form"), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "POST", Path: "/comments", Id: "CommentsCreate", Summary: "Comment an article, or reply to a comment", Tags: []string{"comments"},
+		Description: "The comment is moderated, it's pending or flagged as spam until approved. The commenter of a user is the name of the user.",
+		Body:        apiBodies(commentInput, commentInput),
+		Responses: apiWrite(s, "The id and the status of the comment", openapi.Object{"type": "object", "properties": openapi.Object{
+			"id": openapi.Object{"type": "integer", "format": "int64"}, "status": openapi.Object{"type": "string"}}}),
+		Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "GET", Path: "/comments/:id", Id: "CommentsShow", Summary: "Get a comment", Tags: []string{"comments"},
+		Params: []openapi.Param{id, render}, Responses: apiJSON(s, "The comment", comment)})
+	s.Add(openapi.Operation{Method: "DELETE", Path: "/comments/:id", Id: "CommentsDestroy", Summary: "Destroy a comment", Tags: []string{"comments"},
+		Description: "Its replies move up to its parent, or it's kept as a tombstone, by the -comment-orphans flag.",
+		Params:      []openapi.Param{id}, Responses: apiWrite(s, "Destroyed", nil), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "PUT", Path: "/comments/:id", Id: "CommentsUpdate", Summary: "Update a comment", Tags: []string{"comments"},
+		Description: "The fields left empty are kept, an edited body is moderated again.",
+		Params:      []openapi.Param{id}, Body: apiBodies(apiPartial(s.Input(m.Comment{}, "commenter", "body")), s.Input(m.Comment{}, "body")), Responses: apiWrite(s, "Updated", nil), Security: writeSecurity})
+
+	// for the moderation of the comments
+	status := map[string]openapi.Object{"status": {"type": "string"}}
+	s.Add(openapi.Operation{Method: "GET", Path: "/moderation/comments", Id: "ModerationComments", Summary: "The moderation queue", Tags: []string{"moderation"},
+		Params: []openapi.Param{
+			apiQuery("status", "The status of the comments", openapi.Object{"type": "string", "enum": []string{"pending", "approved", "rejected", "spam"}, "default": "pending"}),
+			apiQuery("article_id", "The article of the comments", openapi.Object{"type": "integer", "format": "int64"}),
+		},
+		Responses: apiPage(s, "The comments, the likeliest spam first", openapi.Object{"type": "array", "items": comment}), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "POST", Path: "/comments/:id/approve", Id: "CommentsApprove", Summary: "Approve a comment", Tags: []string{"moderation"},
+		Params: []openapi.Param{id}, Responses: apiWrite(s, "The status of the comment", apiObject(status)), Security: writeSecurity})
+	s.Add(openapi.Operation{Method: "POST", Path: "/comments/:id/reject", Id: "CommentsReject", Summary: "Reject a comment, or mark it as spam", Tags: []string{"moderation"},
+		Params:    []openapi.Param{id, apiQuery("spam", "Mark the comment as spam", openapi.Object{"type": "boolean"})},
+		Body:      apiForm(openapi.Object{"type": "object", "properties": openapi.Object{"spam": openapi.Object{"type": "boolean"}}}),
+		Responses: apiWrite(s, "The status of the comment", apiObject(status)), Security: writeSecurity})
+
+	// for the search
+	s.Add(openapi.Operation{Method: "GET", Path: "/search", Id: "SearchIndex", Summary: "Search the articles and the comments", Tags: []string{"search"},
+		Params: []openapi.Param{
+			apiQuery("q", "The words to find", openapi.Object{"type": "string"}),
+			apiQuery("type", "The comma separated types of the documents", openapi.Object{"type": "string", "default": "articles,comments"}),
+			apiQuery("limit", "The max number of results", openapi.Object{"type": "integer", "minimum": 1, "maximum": maxSearchLimit, "default": maxSearchLimit}),
+		},
+		Responses: apiPage(s, "The results, the most relevant first", openapi.Object{"type": "array", "items": s.Schema(search.Hit{})})})
+
+	// for the audit log
+	s.Add(openapi.Operation{Method: "GET", Path: "/audit", Id: "AuditIndex", Summary: "List the audit events", Tags: []string{"audit"},
+		Params: []openapi.Param{
+			apiQuery("resource", "The table of the records", openapi.Object{"type": "string"}),
+			apiQuery("id", "The id of the record, with the resource", openapi.Object{"type": "integer", "format": "int64"}),
+			apiQuery("actor_id", "The user who made the changes", openapi.Object{"type": "integer", "format": "int64"}),
+			apiQuery("request_id", "The request of the changes", openapi.Object{"type": "string"}),
+			apiQuery("before_id", "The events before this one, to page through them", openapi.Object{"type": "integer", "format": "int64"}),
+			apiQuery("limit", "The max number of events", openapi.Object{"type": "integer", "minimum": 1, "maximum": maxAuditLimit, "default": 100}),
+		},
+		Responses: apiWrite(s, "The events, the latest first", openapi.Object{"type": "array", "items": s.Schema(m.AuditEvent{})}), Security: writeSecurity})
+
+	// for the bulk imports
+	s.Add(openapi.Operation{Method: "POST", Path: "/imports", Id: "ImportsCreate", Summary: "Import articles or comments", Tags: []string{"imports"},
+		Params: []openapi.Param{
+			apiQuery("resource", "The records to import", openapi.Object{"type": "string", "enum": []string{"articles", "comments"}}),
+			apiQuery("format", "The format of the input", openapi.Object{"type": "string", "enum": []string{"ndjson", "csv"}}),
+			apiQuery("dry_run", "Validate the records without saving them", openapi.Object{"type": "boolean"}),
+			apiQuery("upsert_by", "The field to update the existing records by instead of inserting them", openapi.Object{"type": "string"}),
+			apiQuery("article_key", "The field of the articles the comments refer to", openapi.Object{"type": "string"}),
+		},
+		Body: map[string]openapi.Object{
+			"application/x-ndjson": {"type": "string"},
+			"text/csv":             {"type": "string"},
+			"multipart/form-data":  {"type": "object", "properties": openapi.Object{"file": openapi.Object{"type": "string", "contentMediaType": "application/octet-stream"}}},
+		},
+		Responses: apiWrite(s, "The report of the import", s.Schema(importer.Report{})), Security: writeSecurity})
+
+	// for the pages and the login sessions
+	s.Add(openapi.Operation{Method: "GET", Path: "/", Id: "HomeHandler", Summary: "The home page", Tags: []string{"pages"}, Responses: apiHTML("The page")})
+	s.Add(openapi.Operation{Method: "GET", Path: "/login", Id: "SessionsNew", Summary: "The login form", Tags: []string{"sessions"}, Responses: apiHTML("The form")})
+	s.Add(openapi.Operation{Method: "POST", Path: "/login", Id: "SessionsCreate", Summary: "Log in", Tags: []string{"sessions"},
+		Body: apiForm(openapi.Object{"type": "object", "required": []string{"email", "password"}, "properties": openapi.Object{
+			"email": openapi.Object{"type": "string", "format": "email"}, "password": openapi.Object{"type": "string", "format": "password"}}}),
+		Responses: apiRedirect("To the articles with the session cookie, or back to the form")})
+	s.Add(openapi.Operation{Method: "POST", Path: "/logout", Id: "SessionsDestroy", Summary: "Log out", Tags: []string{"sessions"},
+		Responses: apiRedirect("To the articles")})
+
+	// for the operations
+	s.Add(openapi.Operation{Method: "GET", Path: "/healthz", Id: "HealthzIndex", Summary: "Whether the process is alive", Tags: []string{"operations"},
+		Responses: apiJSON(s, "Alive", apiObject(status))})
+	ready := apiObject(map[string]openapi.Object{"status": {"type": "string", "enum": []string{health.Pass, health.Fail}},
+		"checks": {"type": "object", "additionalProperties": s.Schema(health.Result{})}})
+	readyz := apiJSON(s, "Ready", ready)
+	readyz["503"] = openapi.Response{Description: "Not ready, e.g. the database is down or the app is shutting down",
+		Content: map[string]openapi.Object{"application/json": apiEnvelope(s, ready)}}
+	s.Add(openapi.Operation{Method: "GET", Path: "/readyz", Id: "ReadyzIndex", Summary: "Whether the app can serve", Tags: []string{"operations"}, Responses: readyz})
+	s.Add(openapi.Operation{Method: "GET", Path: "/metrics", Id: "MetricsIndex", Summary: "The metrics in the Prometheus text format", Tags: []string{"operations"},
+		Responses: map[string]openapi.Response{"200": {Description: "The metrics", Content: map[string]openapi.Object{"text/plain": {"type": "string"}}}}})
+	s.Add(openapi.Operation{Method: "GET", Path: "/openapi.json", Id: "OpenAPIShow", Summary: "This document", Tags: []string{"operations"},
+		Responses: map[string]openapi.Response{"200": {Description: "The OpenAPI document", Content: map[string]openapi.Object{"application/json": {"type": "object"}}}}})
+	s.Add(openapi.Operation{Method: "GET", Path: "/docs", Id: "DocsShow", Summary: "The Swagger UI of this document", Tags: []string{"operations"}, Responses: apiHTML("The page")})
+	return s
+}
+
+func apiQuery(name, description string, schema openapi.Object) openapi.Param {
+	return openapi.Param{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func apiObject(props map[string]openapi.Object) openapi.Object {
+	properties := openapi.Object{}
+	for k, v := range props {
+		properties[k] = v
+	}
+	return openapi.Object{"type": "object", "properties": properties}
+}
+
+func apiIdData() openapi.Object {
+	return apiObject(map[string]openapi.Object{"id": {"type": "integer", "format": "int64"}})
+}
+
+// apiEnvelope get the schema of the Resp with the data.
+func apiEnvelope(s *openapi.Spec, data openapi.Object) openapi.Object {
+	resp := s.Schema(Resp{})
+	if data == nil {
+		return resp
+	}
+	return openapi.Object{"allOf": []openapi.Object{resp, apiObject(map[string]openapi.Object{"data": data})}}
+}
+
+// apiJSON is the response of a JSON route.
+func apiJSON(s *openapi.Spec, description string, data openapi.Object) map[string]openapi.Response {
+	return map[string]openapi.Response{"200": {Description: description, Content: map[string]openapi.Object{"application/json": apiEnvelope(s, data)}}}
+}
+
+// apiPage is the response of a route responding with JSON, or HTML to the browsers.
+func apiPage(s *openapi.Spec, description string, data openapi.Object) map[string]openapi.Response {
+	r := apiJSON(s, description, data)
+	r["200"].Content["text/html"] = openapi.Object{"type": "string"}
+	return r
+}
+
+// apiWrite is the response of a route needing the authentication, which may be rate limited.
+func apiWrite(s *openapi.Spec, description string, data openapi.Object) map[string]openapi.Response {
+	r := apiJSON(s, description, data)
+	errResp := map[string]openapi.Object{"application/json": apiEnvelope(s, nil)}
+	r["401"] = openapi.Response{Description: "No or invalid credentials", Content: errResp}
+	r["403"] = openapi.Response{Description: "Not allowed to the client", Content: errResp}
+	r["429"] = openapi.Response{Description: "Rate limited", Content: errResp, Headers: map[string]openapi.Object{"Retry-After": {"type": "integer"}}}
+	return r
+}
+
+func apiExport(s *openapi.Spec, record openapi.Object) map[string]openapi.Response {
+	return map[string]openapi.Response{"200": {Description: "The records, streamed. A NDJSON export failing half way ends with an {\"error\"} line", Content: map[string]openapi.Object{
+		"application/x-ndjson": record,
+		"text/csv":             {"type": "string"},
+	}}}
+}
+
+func apiHTML(description string) map[string]openapi.Response {
+	return map[string]openapi.Response{"200": {Description: description, Content: map[string]openapi.Object{"text/html": {"type": "string"}}}}
+}
+
+func apiRedirect(description string) map[string]openapi.Response {
+	return map[string]openapi.Response{"303": {Description: description, Headers: map[string]openapi.Object{"Location": {"type": "string"}}}}
+}
+
+func apiWithRedirect(r map[string]openapi.Response, code int, description string) map[string]openapi.Response {
+	r[ToStr(int64(code))] = openapi.Response{Description: description, Headers: map[string]openapi.Object{"Location": {"type": "string"}}}
+	return r
+}
+
+// apiBodies is the request body of a route taking JSON or the fields of a form.
+func apiBodies(json, form openapi.Object) map[string]openapi.Object {
+	return map[string]openapi.Object{"application/json": json, "application/x-www-form-urlencoded": form}
+}
+
+func apiForm(schema openapi.Object) map[string]openapi.Object {
+	return map[string]openapi.Object{"application/x-www-form-urlencoded": schema}
+}
+
+// apiPartial drop the required fields of an input, for the updates.
+func apiPartial(input openapi.Object) openapi.Object {
+	copied := openapi.Object{}
+	for k, v := range input {
+		if k != "required" {
+			copied[k] = v
+		}
+	}
+	return copied
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"html/template"
+	"time"
+
+	m "../src/models"
+	"../src/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// currentActor get the policy actor of the current user, nil if anonymous or the client is not a user.
+func currentActor(c *gin.Context) *policy.Actor {
+	return actorOf(CurrentUser(c))
+}
+
+func actorOf(u *m.User) *policy.Actor {
+	if u == nil {
+		return nil
+	}
+	return &policy.Actor{Id: u.Id, Role: policy.Role(u.Role)}
+}
+
+// authorize abort the request with 403 and the reason if the policy decision is a denial.
+func authorize(c *gin.Context, d policy.Decision) bool {
+	if !d.Allowed {
+		forbidden(c, d.Reason)
+	}
+	return d.Allowed
+}
+
+func articleResource(ar *m.Article) policy.Resource {
+	return policy.Resource{OwnerId: ar.UserId, CreatedAt: ar.CreatedAt}
+}
+
+func commentResource(cm *m.Comment) policy.Resource {
+	return policy.Resource{OwnerId: cm.UserId, CreatedAt: cm.CreatedAt}
+}
+
+// TemplateFuncs are the functions for the views, they should be set before loading the templates.
+var TemplateFuncs = template.FuncMap{
+	"can":      can,
+	"rendered": rendered,
+}
+
+// can is used by the views to show only the actions allowed, e.g. {{ if can $.Actor "update" .Article }},
+// the record is "article" or "comment" for the creation, and "comment" for the moderation queue.
+func can(a *policy.Actor, action string, record interface{}) bool {
+	var d policy.Decision
+	switch r := record.(type) {
+	case string:
+		switch {
+		case action == "create" && r == "article":
+			d = policy.CreateArticle(a)
+		case action == "create" && r == "comment":
+			d = policy.CreateComment(a)
+		case action == "moderate" && r == "comment":
+			d = policy.ModerateComment(a)
+		}
+	case *m.Article:
+		d = canArticle(a, action, r)
+	case m.Article:
+		d = canArticle(a, action, &r)
+	case *m.Comment:
+		d = canComment(a, action, r)
+	case m.Comment:
+		d = canComment(a, action, &r)
+	}
+	return d.Allowed
+}
+
+func canArticle(a *policy.Actor, action string, ar *m.Article) policy.Decision {
+	switch action {
+	case "update":
+		return policy.UpdateArticle(a, articleResource(ar))
+	case "destroy":
+		return policy.DestroyArticle(a, articleResource(ar))
+	case "publish":
+		return policy.PublishArticle(a, articleResource(ar))
+	}
+	return policy.Decision{}
+}
+
+func canComment(a *policy.Actor, action string, cm *m.Comment) policy.Decision {
+	switch action {
+	case "update":
+		return policy.UpdateComment(a, commentResource(cm), time.Now())
+	case "destroy":
+		return policy.DestroyComment(a, commentResource(cm))
+	case "moderate":
+		return policy.ModerateComment(a)
+	}
+	return policy.Decision{}
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	m "../src/models"
+	"../src/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// articleVisible tell whether the current user can see the article:
+// a published one, or an unpublished one of the author or for the editors.
+func articleVisible(c *gin.Context, ar *m.Article) bool {
+	return ar.Status == m.ArticlePublished || policy.ReadUnpublishedArticle(currentActor(c), articleResource(ar)).Allowed
+}
+
+// findVisibleArticle find the article of the id if the current user can see it, as if it didn't exist otherwise.
+func findVisibleArticle(c *gin.Context, id int64) (*m.Article, error) {
+	ar, err := m.FindArticle(c.Request.Context(), id)
+	if err == nil && !articleVisible(c, ar) {
+		return nil, sql.ErrNoRows
+	}
+	return ar, err
+}
+
+// statusFilter build the condition of the status param of the article index and export, "published" by default,
+// "all" for any status. The unpublished articles are restricted to the own ones but for the editors.
+func statusFilter(c *gin.Context) (string, []interface{}, error) {
+	status := c.DefaultQuery("status", m.ArticlePublished)
+	cond, args := "status = ?", []interface{}{status}
+	if status == "all" {
+		cond, args = "", nil
+	} else if !m.ValidArticleStatus(status) {
+		return "", nil, fmt.Errorf("Unknown status %q, it should be %s or all", status, strings.Join(m.ArticleStatuses, ", "))
+	} else if status == m.ArticlePublished {
+		return cond, args, nil
+	}
+	a := currentActor(c)
+	if policy.ReadUnpublishedArticle(a, policy.Resource{}).Allowed {
+		return cond, args, nil
+	}
+	vis, vargs := "status = ?", []interface{}{m.ArticlePublished}
+	if a != nil {
+		vis, vargs = "(status = ? OR user_id = ?)", []interface{}{m.ArticlePublished, a.Id}
+	}
+	if cond == "" {
+		return vis, vargs, nil
+	}
+	return cond + " AND " + vis, append(args, vargs...), nil
+}
+
+// POST /articles/1/publish, with publish_at=2026-10-20T08:00:00Z to schedule it
+func ArticlesPublish(c *gin.Context) {
+	var at time.Time
+	if v := c.DefaultPostForm("publish_at", c.Query("publish_at")); v != "" {
+		var err error
+		if at, err = ParseTime(v); err != nil {
+			c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("Parsing publish_at error: %v", err), nil))
+			return
+		}
+	}
+	setArticleStatus(c, func(ar *m.Article) error { return m.PublishArticle(c.Request.Context(), ar, at) })
+}
+
+// POST /articles/1/unpublish, with archive=true to archive it
+func ArticlesUnpublish(c *gin.Context) {
+	archive, _ := strconv.ParseBool(c.DefaultPostForm("archive", c.Query("archive")))
+	setArticleStatus(c, func(ar *m.Article) error { return m.UnpublishArticle(c.Request.Context(), ar, archive) })
+}
+
+func setArticleStatus(c *gin.Context, change func(ar *m.Article) error) {
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+		return
+	}
+	ar, err := findVisibleArticle(c, id)
+	if err == nil && !authorize(c, policy.PublishArticle(currentActor(c), articleResource(ar))) {
+		return
+	}
+	if err == nil {
+		err = change(ar)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Publish article error: %v", err)
+		logger(c).Warn("Publish article error", "article_id", id, "error", err)
+		if isFormRequest(c) {
+			redirectWithFlash(c, fmt.Sprintf("/articles/%d", id), "alert", msg)
+			return
+		}
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	msg := "Article " + ar.Status
+	if isFormRequest(c) {
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", ar.Id), "notice", msg)
+		return
+	}
+	c.JSON(http.StatusOK, BuildResp("200", msg, gin.H{"status": ar.Status, "published_at": ar.PublishedAt}))
+}
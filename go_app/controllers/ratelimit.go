@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"../src/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit is a middleware to limit the requests of the routes in rules, keyed by "METHOD /route/:pattern".
+// Every client has its own bucket per route: the user, or else the API key, or else the client IP.
+// It should be used after Authenticate to tell the clients apart by their credentials.
+func RateLimit(store ratelimit.Store, rules map[string]ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		l, ok := rules[route]
+		if !ok {
+			c.Next()
+			return
+		}
+		res, err := store.Take(route+"|"+rateLimitClient(c), l, time.Now())
+		if err != nil {
+			// don't take the app down with the store
+			logger(c).Error("Rate limit store error", "error", err)
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+		if !res.Allowed {
+			c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			msg := fmt.Sprintf("Too many requests, retry after %s seconds", ceilSeconds(res.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, BuildResp("429", msg, nil))
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitClient identify the client to be limited.
+func rateLimitClient(c *gin.Context) string {
+	if p := CurrentPrincipal(c); p != nil {
+		if p.UserId != 0 {
+			return "user:" + ToStr(p.UserId)
+		}
+		if p.ApiKeyId != 0 {
+			return "apikey:" + ToStr(p.ApiKeyId)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+
+	"../src/markup"
+	m "../src/models"
+	"github.com/gin-gonic/gin"
+)
+
+// applyRender keep only the form of the text asked by the render param of the show endpoints:
+// html for the sanitized HTML, markdown for the source or plain for the text without markup,
+// both the source and the HTML by default. It responds with an error for an unknown form.
+func applyRender(c *gin.Context, text, html *string) bool {
+	switch c.Query("render") {
+	case "":
+	case "html":
+		*text = ""
+	case "markdown":
+		*html = ""
+	case "plain":
+		*text, *html = markup.Plain(*html), ""
+	default:
+		c.JSON(http.StatusOK, BuildResp("400", "The render should be html, markdown or plain", nil))
+		return false
+	}
+	return true
+}
+
+// rendered is used by the views to show the sanitized HTML of an article or a comment, e.g. {{ rendered .Article }},
+// it's rendered and cached if the handler didn't load it with the request context.
+func rendered(record interface{}) template.HTML {
+	ctx := context.Background()
+	var s string
+	switch r := record.(type) {
+	case *m.Article:
+		r.LoadTextHTML(ctx)
+		s = r.TextHtml
+	case m.Article:
+		r.LoadTextHTML(ctx)
+		s = r.TextHtml
+	case *m.Comment:
+		r.LoadBodyHTML(ctx)
+		s = r.BodyHtml
+	case m.Comment:
+		r.LoadBodyHTML(ctx)
+		s = r.BodyHtml
+	}
+	return template.HTML(s)
+}
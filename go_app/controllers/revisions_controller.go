@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	m "../src/models"
+	"../src/policy"
+	"../src/textdiff"
+	"github.com/gin-gonic/gin"
+)
+
+// diffContext is how many lines of context are around the changes of a unified diff.
+const diffContext = 3
+
+// saveRevision store the article as it is now as a revision by the current user,
+// a failure is only logged since the article itself is saved already.
+func saveRevision(c *gin.Context, articleId int64) {
+	var editorId int64
+	if u := CurrentUser(c); u != nil {
+		editorId = u.Id
+	}
+	if _, err := m.SaveArticleRevision(c.Request.Context(), articleId, editorId); err != nil {
+		logger(c).Error("Save article revision error", "article_id", articleId, "error", err)
+	}
+}
+
+// GET /articles/1/revisions
+func ArticlesRevisions(c *gin.Context) {
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", "Parsing id error!", nil))
+		return
+	}
+	ar, err := findVisibleArticle(c, id)
+	var revisions []m.ArticleRevision
+	if err == nil {
+		revisions, err = m.FindArticleRevisionsWhere(c.Request.Context(), "article_id = ? ORDER BY rev DESC", id)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Get article revisions error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if wantsHTML(c) {
+		renderHTML(c, http.StatusOK, "articles_revisions.tmpl", gin.H{"Title": "Revisions of " + ar.Title, "Article": ar, "Revisions": revisions})
+		return
+	}
+	resp := BuildResp("200", "Get article revisions success", revisions)
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /articles/1/revisions/3/diff?against=2&mode=unified|words, against the previous revision by default
+func ArticlesRevisionDiff(c *gin.Context) {
+	rev, against, err := findRevision(c)
+	if err == nil && c.Query("against") != "" {
+		var n int64
+		if n, err = ToInt(c.Query("against")); err == nil {
+			against, err = m.FindArticleRevisionByRev(c.Request.Context(), rev.ArticleId, n)
+		}
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Get article revision diff error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	// the first revision is compared with an empty text
+	if against == nil {
+		against = &m.ArticleRevision{ArticleId: rev.ArticleId}
+	}
+	data := gin.H{"rev": rev.Rev, "against": against.Rev, "mode": c.DefaultQuery("mode", "unified")}
+	switch data["mode"] {
+	case "unified":
+		data["diff"] = textdiff.Unified(fmt.Sprintf("rev %d", against.Rev), fmt.Sprintf("rev %d", rev.Rev), against.Text, rev.Text, diffContext)
+	case "words":
+		data["diff"] = textdiff.Words(against.Text, rev.Text)
+	default:
+		c.JSON(http.StatusOK, BuildResp("400", "The mode should be unified or words", nil))
+		return
+	}
+	if against.Title != rev.Title {
+		data["title_change"] = gin.H{"from": against.Title, "to": rev.Title}
+	}
+	if wantsHTML(c) {
+		data["Title"], data["Rev"], data["Against"] = fmt.Sprintf("Revision %d against %d", rev.Rev, against.Rev), rev, against
+		renderHTML(c, http.StatusOK, "articles_revision_diff.tmpl", data)
+		return
+	}
+	resp := BuildResp("200", "Get article revision diff success", data)
+	c.JSON(http.StatusOK, resp)
+}
+
+// POST /articles/1/revisions/3/restore
+func ArticlesRevisionRestore(c *gin.Context) {
+	rev, _, err := findRevision(c)
+	var ar *m.Article
+	if err == nil {
+		ar, err = m.FindArticle(c.Request.Context(), rev.ArticleId)
+	}
+	if err == nil && !authorize(c, policy.UpdateArticle(currentActor(c), articleResource(ar))) {
+		return
+	}
+	var restored *m.ArticleRevision
+	if err == nil {
+		var editorId int64
+		if u := CurrentUser(c); u != nil {
+			editorId = u.Id
+		}
+		restored, err = m.RestoreArticleRevision(c.Request.Context(), rev, editorId)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Restore article revision error: %v", err)
+		logger(c).Warn("Restore article revision error", "article_id", c.Param("id"), "rev", c.Param("rev"), "error", err)
+		if isFormRequest(c) {
+			redirectWithFlash(c, fmt.Sprintf("/articles/%s/revisions", c.Param("id")), "alert", msg)
+			return
+		}
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	msg := fmt.Sprintf("Revision %d restored", rev.Rev)
+	if isFormRequest(c) {
+		redirectWithFlash(c, fmt.Sprintf("/articles/%d", rev.ArticleId), "notice", msg)
+		return
+	}
+	c.JSON(http.StatusOK, BuildResp("200", msg, map[string]int64{"rev": restored.Rev}))
+}
+
+// findRevision find the revision of the params id and rev on an article the current user can see,
+// and the revision before it if any.
+func findRevision(c *gin.Context) (rev, previous *m.ArticleRevision, err error) {
+	id, err := ToInt(c.Param("id"))
+	if err != nil {
+		return nil, nil, errors.New("Parsing id error!")
+	}
+	n, err := ToInt(c.Param("rev"))
+	if err != nil {
+		return nil, nil, errors.New("Parsing rev error!")
+	}
+	if _, err = findVisibleArticle(c, id); err != nil {
+		return nil, nil, err
+	}
+	if rev, err = m.FindArticleRevisionByRev(c.Request.Context(), id, n); err != nil {
+		return nil, nil, err
+	}
+	revs, err := m.FindArticleRevisionsWhere(c.Request.Context(), "article_id = ? AND rev < ? ORDER BY rev DESC LIMIT 1", id, n)
+	if err == nil && len(revs) != 0 {
+		previous = &revs[0]
+	}
+	return rev, previous, err
+}
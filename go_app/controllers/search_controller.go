@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	m "../src/models"
+	"../src/policy"
+	"../src/search"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchBackend is the index of the articles and the comments, main replaces it by the flag -search-backend.
+var SearchBackend search.Backend = search.NewMemoryBackend()
+
+// maxSearchLimit is the most results of a search, and the default of the limit param.
+const maxSearchLimit = 50
+
+// maxSearchFetch is the most hits asked to the backend, which is asked for more
+// until the hits left by publishedHits fill the limit.
+const maxSearchFetch = 1000
+
+// GET /search?q=words&type=articles,comments&limit=20
+func SearchIndex(c *gin.Context) {
+	if !authorize(c, policy.ReadArticle(currentActor(c))) || !authorize(c, policy.ReadComment(currentActor(c))) {
+		return
+	}
+	q := c.Query("q")
+	types, err := search.ParseTypes(c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusOK, BuildResp("400", err.Error(), nil))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(maxSearchLimit)))
+	if err != nil || limit < 1 || limit > maxSearchLimit {
+		c.JSON(http.StatusOK, BuildResp("400", fmt.Sprintf("The limit should be 1 to %d", maxSearchLimit), nil))
+		return
+	}
+	hits, err := searchPublished(c.Request.Context(), search.Query{Terms: search.Tokenize(q), Types: types, Limit: limit})
+	if err != nil {
+		msg := fmt.Sprintf("Search error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if wantsHTML(c) {
+		// the snippets are escaped by the search but the <mark>
+		snippets := make([]template.HTML, len(hits))
+		for i, h := range hits {
+			snippets[i] = template.HTML(h.Snippet)
+		}
+		renderHTML(c, http.StatusOK, "search.tmpl", gin.H{"Title": "Search", "Q": q, "Hits": hits, "Snippets": snippets})
+		return
+	}
+	resp := BuildResp("200", "Search success", hits)
+	c.JSON(http.StatusOK, resp)
+}
+
+// searchPublished search the hits of the published articles, up to q.Limit of them. The backend is asked
+// for more hits while too many are dropped, as it doesn't know which articles are published.
+func searchPublished(ctx context.Context, q search.Query) ([]search.Hit, error) {
+	limit := q.Limit
+	for {
+		hits, err := search.Search(ctx, SearchBackend, q)
+		if err != nil {
+			return nil, err
+		}
+		more := len(hits) == q.Limit && q.Limit < maxSearchFetch
+		if hits, err = publishedHits(ctx, hits); err != nil {
+			return nil, err
+		}
+		if len(hits) >= limit || !more {
+			if len(hits) > limit {
+				hits = hits[:limit]
+			}
+			return hits, nil
+		}
+		q.Limit *= 4
+		if q.Limit > maxSearchFetch {
+			q.Limit = maxSearchFetch
+		}
+	}
+}
+
+// publishedHits drop the hits of the articles which aren't published and of their comments,
+// the index has them all.
+func publishedHits(ctx context.Context, hits []search.Hit) ([]search.Hit, error) {
+	if len(hits) == 0 {
+		return hits, nil
+	}
+	ids := []interface{}{}
+	for _, h := range hits {
+		ids = append(ids, h.ArticleId)
+	}
+	articles, err := m.FindArticlesWhere(ctx, "status = ? AND id IN (?"+strings.Repeat(",?", len(ids)-1)+")", append([]interface{}{m.ArticlePublished}, ids...)...)
+	if err != nil {
+		return nil, err
+	}
+	published := map[int64]bool{}
+	for _, ar := range articles {
+		published[ar.Id] = true
+	}
+	visible := hits[:0]
+	for _, h := range hits {
+		if published[h.ArticleId] {
+			visible = append(visible, h)
+		}
+	}
+	return visible, nil
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionKey is the key of the *Session in the gin context.
+const sessionKey = "session"
+
+// SessionCookie is the name of the cookie the session is stored in.
+const SessionCookie = "myapp_session"
+
+// Session is the state of a browser kept in a signed cookie, only what's safe to be read
+// by the client should be stored since the cookie is not encrypted.
+type Session struct {
+	UserId  int64   `json:"uid,omitempty"`
+	CSRF    string  `json:"csrf,omitempty"`
+	Flashes []Flash `json:"flashes,omitempty"`
+	changed bool
+}
+
+// Flash is a message shown on the next page rendered.
+type Flash struct {
+	Kind string `json:"kind"`
+	Msg  string `json:"msg"`
+}
+
+// Sessions is a middleware to load the session from the cookie signed with secret,
+// the cookie is written back before the response if the session is changed.
+func Sessions(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s := &Session{}
+		if ck, err := c.Cookie(SessionCookie); err == nil {
+			if !decodeSession(secret, ck, s) {
+				s = &Session{changed: true}
+			}
+		}
+		c.Set(sessionKey, s)
+		c.Writer = &sessionWriter{ResponseWriter: c.Writer, c: c, s: s, secret: secret}
+		c.Next()
+	}
+}
+
+// CurrentSession get the session of the request.
+func CurrentSession(c *gin.Context) *Session {
+	if v, ok := c.Get(sessionKey); ok {
+		return v.(*Session)
+	}
+	return &Session{}
+}
+
+// SetUser sign the user in or out (0), the CSRF token is rotated at the same time.
+func (s *Session) SetUser(id int64) {
+	s.UserId = id
+	s.CSRF = ""
+	s.changed = true
+}
+
+// AddFlash add a flash message of the kind "notice" or "alert".
+func (s *Session) AddFlash(kind, msg string) {
+	s.Flashes = append(s.Flashes, Flash{kind, msg})
+	s.changed = true
+}
+
+// TakeFlashes get the flash messages and remove them from the session.
+func (s *Session) TakeFlashes() []Flash {
+	fs := s.Flashes
+	if len(fs) > 0 {
+		s.Flashes = nil
+		s.changed = true
+	}
+	return fs
+}
+
+// CSRFToken get the CSRF token of the session, a new one is generated if none yet.
+func (s *Session) CSRFToken() string {
+	if s.CSRF == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		s.CSRF = base64.RawURLEncoding.EncodeToString(b)
+		s.changed = true
+	}
+	return s.CSRF
+}
+
+// validCSRF check the token of the "_csrf" form field or the X-CSRF-Token header against the session.
+func validCSRF(c *gin.Context, s *Session) bool {
+	token := c.GetHeader("X-CSRF-Token")
+	if token == "" {
+		token = c.PostForm("_csrf")
+	}
+	return s.CSRF != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.CSRF)) == 1
+}
+
+func encodeSession(secret []byte, s *Session) string {
+	b, _ := json.Marshal(s)
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signSession(secret, payload))
+}
+
+func decodeSession(secret []byte, ck string, s *Session) bool {
+	i := strings.LastIndex(ck, ".")
+	if i < 0 {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(ck[i+1:])
+	if err != nil || !hmac.Equal(sig, signSession(secret, ck[:i])) {
+		return false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(ck[:i])
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(b, s) == nil
+}
+
+func signSession(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// sessionWriter write the session cookie right before the response headers are sent.
+type sessionWriter struct {
+	gin.ResponseWriter
+	c      *gin.Context
+	s      *Session
+	secret []byte
+	saved  bool
+}
+
+func (w *sessionWriter) save() {
+	if w.saved || w.Written() {
+		return
+	}
+	w.saved = true
+	if !w.s.changed {
+		return
+	}
+	http.SetCookie(w.ResponseWriter, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    encodeSession(w.secret, w.s),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   w.c.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	w.save()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) WriteHeaderNow() {
+	w.save()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.save()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sessionWriter) WriteString(s string) (int, error) {
+	w.save()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *sessionWriter) Flush() {
+	w.save()
+	w.ResponseWriter.Flush()
+}
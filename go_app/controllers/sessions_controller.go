@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+
+	"../src/auth"
+	m "../src/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /login
+func SessionsNew(c *gin.Context) {
+	renderHTML(c, http.StatusOK, "sessions_new.tmpl", gin.H{"Title": "Sign in"})
+}
+
+// POST /login
+func SessionsCreate(c *gin.Context) {
+	s := CurrentSession(c)
+	if !validCSRF(c, s) {
+		redirectWithFlash(c, "/login", "alert", "Invalid CSRF token, please try again")
+		return
+	}
+	email := c.PostForm("email")
+	u, err := m.FindUserBy(c.Request.Context(), "email", email)
+	if err != nil || !auth.CheckPassword(u.PasswordDigest, c.PostForm("password")) {
+		s.AddFlash("alert", "Invalid email or password")
+		renderHTML(c, http.StatusUnauthorized, "sessions_new.tmpl", gin.H{"Title": "Sign in", "Email": email})
+		return
+	}
+	s.SetUser(u.Id)
+	redirectWithFlash(c, "/articles", "notice", "Signed in as "+u.Name)
+}
+
+// POST /logout
+func SessionsDestroy(c *gin.Context) {
+	s := CurrentSession(c)
+	if !validCSRF(c, s) {
+		redirectWithFlash(c, "/articles", "alert", "Invalid CSRF token, please try again")
+		return
+	}
+	s.SetUser(0)
+	redirectWithFlash(c, "/articles", "notice", "Signed out")
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	m "../src/models"
+	"../src/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// GET /tags
+func TagsIndex(c *gin.Context) {
+	if !authorize(c, policy.ReadArticle(currentActor(c))) {
+		return
+	}
+	tags, err := m.TagUsages(c.Request.Context())
+	if err != nil {
+		msg := fmt.Sprintf("Get tag index error: %v", err)
+		c.JSON(http.StatusOK, BuildResp("400", msg, nil))
+		return
+	}
+	if wantsHTML(c) {
+		renderHTML(c, http.StatusOK, "tags_index.tmpl", gin.H{"Title": "Tags", "Tags": tags})
+		return
+	}
+	resp := BuildResp("200", "Get tag index success", tags)
+	c.JSON(http.StatusOK, resp)
+}
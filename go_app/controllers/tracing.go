@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"../src/metrics"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("myapp/controllers")
+
+// Trace is a middleware making a span of the request, a child of the span of the traceparent header if any,
+// the SQL statements of the model functions called with the context of the request are its children. It goes first so the other middlewares are in the span.
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = metrics.Unmatched
+		}
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(c.Request.Method), semconv.HTTPRoute(route),
+			semconv.URLPath(c.Request.URL.Path), semconv.ClientAddress(c.ClientIP()),
+		))
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if p := CurrentPrincipal(c); p != nil {
+			span.SetAttributes(semconv.EnduserID(p.Kind + ":" + p.Subject))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d %s", status, http.StatusText(status)))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"../src/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// Validate is a middleware checking the path and query params and the JSON body of the documented routes
+// against their schemas in the OpenAPI document, before the handler runs. The request is rejected with all
+// the violations, and with strict the fields of the JSON bodies unknown to the schemas are violations too.
+// A body of a media type the operation doesn't document is rejected, so the handlers binding JSON never get
+// another one unchecked. The JSON and urlencoded form bodies are limited to maxBody bytes, the uploads of the imports aren't.
+func Validate(strict bool, maxBody int64) gin.HandlerFunc {
+	spec := APISpec()
+	return func(c *gin.Context) {
+		op, ok := spec.Operation(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+		violations := []openapi.Violation{}
+		for _, p := range op.Params {
+			switch p.In {
+			case "path":
+				violations = append(violations, spec.ValidateParam(p, []string{c.Param(p.Name)})...)
+			case "query":
+				if values, ok := c.GetQueryArray(p.Name); ok {
+					violations = append(violations, spec.ValidateParam(p, values)...)
+				}
+			}
+		}
+		ct := c.ContentType()
+		// a Content-Type is checked even with an empty body, gin binds by the header and not the length
+		hasBody := c.GetHeader("Content-Type") != "" || c.Request.ContentLength != 0
+		if _, ok := op.Body[ct]; !ok && len(op.Body) > 0 && hasBody {
+			types := make([]string, 0, len(op.Body))
+			for t := range op.Body {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+			msg := fmt.Sprintf("The body should be %s", strings.Join(types, " or "))
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, BuildResp("415", msg, nil))
+			return
+		}
+		if maxBody > 0 && (ct == gin.MIMEJSON || ct == gin.MIMEPOSTForm) {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBody)
+		}
+		if schema, ok := op.Body[gin.MIMEJSON]; ok && ct == gin.MIMEJSON {
+			body, err := io.ReadAll(c.Request.Body)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				msg := fmt.Sprintf("The body should be at most %d bytes", maxBody)
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, BuildResp("413", msg, nil))
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, BuildResp("400", fmt.Sprintf("Reading body error: %v", err), nil))
+				return
+			}
+			// the handler binds the body again
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			if len(bytes.TrimSpace(body)) > 0 {
+				violations = append(violations, spec.ValidateJSON(schema, body, strict)...)
+			} else if _, required := schema["required"]; required {
+				violations = append(violations, openapi.Violation{In: "body", Message: "The body is required"})
+			}
+		}
+		if len(violations) == 0 {
+			c.Next()
+			return
+		}
+		if isFormRequest(c) || wantsHTML(c) {
+			v := violations[0]
+			redirectWithFlash(c, "/articles", "alert", fmt.Sprintf("Invalid request: %s %s %s", v.In, v.Pointer, v.Message))
+			c.Abort()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, BuildResp("400", "Invalid request", gin.H{"errors": violations}))
+	}
+}
@@ -1,39 +1,490 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	c "./controllers"
+	"./src/auth"
+	"./src/logging"
+	"./src/markup"
+	"./src/metrics"
+	m "./src/models"
+	"./src/moderation"
+	"./src/ratelimit"
+	"./src/search"
+	"./src/tlsconfig"
+	"./src/tracing"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Run a sub command instead of the server if any, e.g. myapp import articles --file x.ndjson
+	if runCommand(os.Args[1:]) {
+		return
+	}
 	// The app will run on port 4000 by default, you can custom it with the flag -port
 	servePort := flag.String("port", "4000", "Http Server Port")
+	// The write routes accept API keys created by "myapp apikeys create",
+	// and JWTs signed by the key in the file of the flag -jwt-key if it's set
+	jwtKey := flag.String("jwt-key", "", "HS256 secret or RS256 PEM public key file to verify JWTs")
+	// The login sessions of the pages are kept in cookies signed by the secret in the file of -session-key
+	sessionKey := flag.String("session-key", "", "File of the secret to sign the session cookies")
+	// The creations are rate limited per client, e.g. "POST /comments=5/1m;POST /articles=10/1h,burst=2"
+	rateLimits := flag.String("rate-limits", "POST /comments=5/1m;POST /articles=10/1h", "Rate limits per route, empty to disable")
+	rateLimitStore := flag.String("rate-limit-store", "memory", "Rate limit buckets store: memory, or shared for the shared store stand-in")
+	// The anonymous clients are limited by their IP, taken from X-Forwarded-For only when the peer is one of these proxies
+	trustedProxies := flag.String("trusted-proxies", "", "Comma separated IPs or CIDRs of the proxies trusted for X-Forwarded-For, empty for none")
+	// The new comments are scored for spam, the ones with a word or phrase of this file score higher
+	commentBlocklist := flag.String("comment-blocklist", "", "File of the blocklisted words in comments, one per line")
+	// The replies of a destroyed comment move up to its parent, or it's kept as a "[deleted]" tombstone
+	commentOrphans := flag.String("comment-orphans", "reparent", "What happens to the replies of a destroyed comment: reparent or tombstone")
+	// The search index is kept by one of the backends, the memory one is rebuilt at each start
+	searchBackend := flag.String("search-backend", "memory", "Search backend: memory, mysql, sqlite or postgres")
+	searchDSN := flag.String("search-dsn", "", "Database of the sqlite or postgres search backend")
+	// The scheduled articles are published by a background worker checking them at this interval
+	publishInterval := flag.Duration("publish-interval", time.Minute, "Interval to publish the scheduled articles, 0 to disable")
+	// The logs are JSON lines with the request id of each request, or text for reading them in a terminal
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warn or error")
+	logFormat := flag.String("log-format", "json", "Log format: json or text")
+	// The spans of the requests and their SQL statements go to an OTLP collector, or to stdout or a file to look at them locally
+	traceExporter := flag.String("trace-exporter", "none", "Trace exporter: none, otlp, stdout or file")
+	traceEndpoint := flag.String("trace-endpoint", "", "URL of the OTLP/HTTP collector, or the file of the file exporter")
+	// The markdown of the articles and the comments is rendered to HTML keeping only these elements and attributes,
+	// run "myapp markup clear-cache" after changing them
+	htmlAllowlist := flag.String("html-allowlist", markup.DefaultAllowlist, "Elements and their [attributes] kept in the rendered HTML")
+	// The app isn't ready at /readyz when the database doesn't answer in time, a migration is pending
+	// or the connections in use reach the saturation ratio of the pool
+	dbMaxOpenConns := flag.Int("db-max-open-conns", 50, "Max open connections to the database, 0 for no limit")
+	readyTimeout := flag.Duration("ready-timeout", 2*time.Second, "Timeout of each readiness check")
+	readyPoolSaturation := flag.Float64("ready-pool-saturation", 0.9, "Ratio of the max open connections in use making the app not ready")
+	// The slow clients are cut off by these timeouts, the exports have to be written within the write timeout
+	readTimeout := flag.Duration("read-timeout", 30*time.Second, "Max duration to read a request with its body")
+	readHeaderTimeout := flag.Duration("read-header-timeout", 5*time.Second, "Max duration to read the headers of a request")
+	writeTimeout := flag.Duration("write-timeout", time.Minute, "Max duration to write a response")
+	idleTimeout := flag.Duration("idle-timeout", 2*time.Minute, "Max duration to keep an idle connection open")
+	maxHeaderBytes := flag.Int("max-header-bytes", http.DefaultMaxHeaderBytes, "Max size of the headers of a request")
+	// On SIGINT or SIGTERM the app stops being ready for -shutdown-delay so the load balancers stop sending requests,
+	// then the requests in flight have -shutdown-timeout to finish
+	shutdownDelay := flag.Duration("shutdown-delay", 5*time.Second, "Duration to fail the readiness before draining the connections")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 20*time.Second, "Max duration to drain the connections and stop the workers")
+	// The params and the JSON bodies of the routes are checked against the OpenAPI document before the handlers
+	strictBodies := flag.Bool("strict-bodies", false, "Reject the JSON bodies with fields unknown to the OpenAPI document")
+	maxBodyBytes := flag.Int64("max-body-bytes", 1<<20, "Max size of the JSON and form bodies, 0 for no limit")
+	// The app serves HTTPS and HTTP/2 with the certificate of -tls-cert and -tls-key, reloaded when the files change or on SIGHUP.
+	// The internal callers can be required a client certificate signed by the CAs of -tls-client-ca.
+	// The TLS flags not given fall back to the environment variables of tlsEnv, e.g. TLS_CERT,
+	// so a container can be given the paths of its mounted secrets by its environment.
+	tlsCert := flag.String("tls-cert", "", "PEM certificate file to serve HTTPS, with its chain ($TLS_CERT)")
+	tlsKey := flag.String("tls-key", "", "PEM private key file of the certificate ($TLS_KEY)")
+	tlsClientCA := flag.String("tls-client-ca", "", "PEM bundle of the CAs of the client certificates, empty for no mutual TLS ($TLS_CLIENT_CA)")
+	tlsClientAuth := flag.String("tls-client-auth", tlsconfig.ClientAuthRequire, "Client certificates with -tls-client-ca: require or verify-if-given ($TLS_CLIENT_AUTH)")
+	tlsReloadInterval := flag.Duration("tls-reload-interval", 10*time.Second, "Interval to check the TLS files for changes, 0 to reload on SIGHUP only ($TLS_RELOAD_INTERVAL)")
 	flag.Parse()
 
-	// Here we are instantiating the router
-	r := gin.Default()
+	logger, err := logging.New(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fatal("Set up logging error", err)
+	}
+	logging.Setup(logger)
+	if err = flagsFromEnv(flag.CommandLine, tlsEnv); err != nil {
+		fatal("Parse the environment error", err)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), "myapp", *traceExporter, *traceEndpoint)
+	if err != nil {
+		fatal("Set up tracing error", err)
+	}
+	// the messages of gin in the debug mode, e.g. the routes, are logged at the debug level
+	gin.DebugPrintFunc = func(format string, values ...interface{}) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+
+	var jwt *auth.JWTVerifier
+	if *jwtKey != "" {
+		jwt, err = auth.LoadJWTKeyFile(*jwtKey)
+		if err != nil {
+			fatal("Load JWT key error", err)
+		}
+	}
+	var tlsReloader *tlsconfig.Reloader
+	if *tlsCert != "" || *tlsKey != "" {
+		tlsReloader, err = tlsconfig.NewReloader(tlsconfig.Files{Cert: *tlsCert, Key: *tlsKey, ClientCA: *tlsClientCA, ClientAuth: *tlsClientAuth})
+		if err != nil {
+			fatal("Load TLS certificate error", err)
+		}
+	} else if *tlsClientCA != "" {
+		fatal("Load TLS certificate error", errors.New("-tls-client-ca needs -tls-cert and -tls-key"))
+	}
+	sessionSecret, err := loadSessionSecret(*sessionKey)
+	if err != nil {
+		fatal("Load session key error", err)
+	}
+	rateRules, err := ratelimit.ParseRules(*rateLimits)
+	if err != nil {
+		fatal("Parse rate limits error", err)
+	}
+	var rateStore ratelimit.Store
+	switch *rateLimitStore {
+	case "memory":
+		rateStore = ratelimit.NewMemoryStore()
+	case "shared":
+		// a real deployment plugs its own shared Backend, e.g. Redis, in the place of the local stand-in
+		rateStore = &ratelimit.SharedStore{Backend: ratelimit.NewLocalBackend(), Prefix: "myapp:ratelimit:"}
+	default:
+		fatal("Unknown rate limit store", fmt.Errorf("%q should be memory or shared", *rateLimitStore))
+	}
+	if *commentBlocklist != "" {
+		words, err := ioutil.ReadFile(*commentBlocklist)
+		if err != nil {
+			fatal("Load comment blocklist error", err)
+		}
+		c.CommentModeration = moderation.NewPipeline(moderation.LoadBlocklist(string(words)), c.CommentHistory{})
+	}
+	if err = markup.SetAllowlist(*htmlAllowlist); err != nil {
+		fatal("Parse HTML allowlist error", err)
+	}
+	c.CommentOrphans, err = m.ParseOrphanMode(*commentOrphans)
+	if err != nil {
+		fatal("Parse comment orphans error", err)
+	}
+	c.SearchBackend, err = search.Open(*searchBackend, *searchDSN)
+	if err != nil {
+		fatal("Open search backend error", err)
+	}
+	m.OnWrite(search.Sync(c.SearchBackend))
+	// The durations of the model functions and the stats of the database pool are served at /metrics
+	m.OnQuery(metrics.ObserveQuery)
+	if err = metrics.WatchDB(m.DB.DB, "myapp"); err != nil {
+		fatal("Watch database stats error", err)
+	}
+	m.DB.SetMaxOpenConns(*dbMaxOpenConns)
+	c.Health.Timeout = *readyTimeout
+	c.Health.Add("database", c.CheckDatabase)
+	c.Health.Add("migrations", c.CheckMigrations)
+	c.Health.Add("pool", c.CheckPool(*readyPoolSaturation))
+	// the background workers stop on shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var workers sync.WaitGroup
+	if *searchBackend == "memory" {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			counts, err := search.Reindex(ctx, c.SearchBackend)
+			if err != nil {
+				slog.Error("Build search index error", "error", err)
+				return
+			}
+			slog.Info("Search index built", "articles", counts[search.Articles], "comments", counts[search.Comments])
+		}()
+	}
+	m.OnArticleStatus(func(e m.ArticleStatusEvent) {
+		slog.Info("Article status changed", "article_id", e.ArticleId, "from", e.From, "to", e.To)
+	})
+	if *publishInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			publishScheduled(ctx, *publishInterval)
+		}()
+	}
+
+	if tlsReloader != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			reloadTLS(ctx, tlsReloader, *tlsReloadInterval)
+		}()
+	}
+
+	var proxies []string
+	if *trustedProxies != "" {
+		proxies = strings.Split(*trustedProxies, ",")
+		for i := range proxies {
+			proxies[i] = strings.TrimSpace(proxies[i])
+		}
+	}
+	r, err := newRouter(logger, jwt, sessionSecret, proxies, rateStore, rateRules, c.Validate(*strictBodies, *maxBodyBytes))
+	if err != nil {
+		fatal("Set trusted proxies error", err)
+	}
+	// Let's start the server
+	srv := &http.Server{
+		Addr:              ":" + *servePort,
+		Handler:           methodOverride(r, *maxBodyBytes),
+		ReadTimeout:       *readTimeout,
+		ReadHeaderTimeout: *readHeaderTimeout,
+		WriteTimeout:      *writeTimeout,
+		IdleTimeout:       *idleTimeout,
+		MaxHeaderBytes:    *maxHeaderBytes,
+	}
+	served := make(chan error, 1)
+	go func() {
+		if tlsReloader != nil {
+			srv.TLSConfig = tlsReloader.Config()
+			slog.Info("Listening", "port", *servePort, "tls", true, "client_auth", *tlsClientCA != "")
+			served <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		slog.Info("Listening", "port", *servePort)
+		served <- srv.ListenAndServe()
+	}()
+	select {
+	case err = <-served:
+		stop()
+		shutdownTracing(context.Background())
+		fatal("Serve error", err)
+	case <-ctx.Done():
+		stop()
+	}
+
+	slog.Info("Shutting down", "delay", *shutdownDelay, "timeout", *shutdownTimeout)
+	c.Health.Drain()
+	time.Sleep(*shutdownDelay)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err = srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Drain connections error", "error", err)
+		srv.Close()
+	}
+	if !waitGroup(shutdownCtx, &workers) {
+		slog.Error("Stop workers error", "error", shutdownCtx.Err())
+	}
+	if closer, ok := c.SearchBackend.(io.Closer); ok {
+		if err = closer.Close(); err != nil {
+			slog.Error("Close search backend error", "error", err)
+		}
+	}
+	if err = m.DB.Close(); err != nil {
+		slog.Error("Close database error", "error", err)
+	}
+	if err = shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Flush traces error", "error", err)
+	}
+	slog.Info("Shut down")
+}
+
+// newRouter create the router of the app with its middlewares and routes, the client IPs are only taken
+// from X-Forwarded-For when the peer is one of the trusted proxies.
+func newRouter(logger *slog.Logger, jwt *auth.JWTVerifier, sessionSecret []byte, trustedProxies []string, rateStore ratelimit.Store, rateRules map[string]ratelimit.Limit, validate gin.HandlerFunc) (*gin.Engine, error) {
+	// Here we are instantiating the router, with the logs of the requests and the recovery from the panics
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(c.Trace(), c.Logger(logger), c.Recovery(), c.Metrics())
 	// Switch to "release" mode in production
 	// gin.SetMode(gin.ReleaseMode)
+	r.SetFuncMap(c.TemplateFuncs)
 	r.LoadHTMLGlob("views/*")
 	// Create a static assets router
 	// r.Static("/assets", "./public/assets")
 	r.StaticFile("/favicon.ico", "./public/favicon.ico")
+	// The metrics for Prometheus to scrape
+	r.GET("/metrics", c.MetricsIndex)
+	// The OpenAPI document of the routes, every route should be in it, see main_test.go
+	r.GET("/openapi.json", c.OpenAPIShow)
+	r.GET("/docs", c.DocsShow)
+	r.Static("/docs/assets", "./public/swagger-ui")
+	// The liveness and readiness probes
+	r.GET("/healthz", c.HealthzIndex)
+	r.GET("/readyz", c.ReadyzIndex)
+	r.Use(c.Sessions(sessionSecret))
+	// The login form is the only write route open to anyone, so it's registered before the authentication
+	r.GET("/login", c.SessionsNew)
+	r.POST("/login", c.SessionsCreate)
+	r.POST("/logout", c.SessionsDestroy)
+	// Read routes are public, the others require an API key, a JWT or a login session
+	r.Use(c.Authenticate(jwt))
+	// The writes are recorded in the audit log with the authenticated client
+	r.Use(c.Audit())
+	r.Use(c.RateLimit(rateStore, rateRules))
+	// The requests not matching the OpenAPI document are rejected with the violations
+	r.Use(validate)
 	// Then we bind some route to some handler(controller action)
 	// for the articles
 	r.GET("/", c.HomeHandler)
 	r.GET("/articles", c.ArticlesIndex)
+	r.GET("/articles/export", c.ArticlesExport)
+	r.GET("/articles/new", c.ArticlesNew)
+	r.GET("/articles/:id/edit", c.ArticlesEdit)
 	r.POST("/articles", c.ArticlesCreate)
 	r.GET("/articles/:id", c.ArticlesShow)
 	r.DELETE("/articles/:id", c.ArticlesDestroy)
 	r.PUT("/articles/:id", c.ArticlesUpdate)
+	r.POST("/articles/:id/publish", c.ArticlesPublish)
+	r.POST("/articles/:id/unpublish", c.ArticlesUnpublish)
+	// for the revisions of the articles
+	r.GET("/articles/:id/revisions", c.ArticlesRevisions)
+	r.GET("/articles/:id/revisions/:rev/diff", c.ArticlesRevisionDiff)
+	r.POST("/articles/:id/revisions/:rev/restore", c.ArticlesRevisionRestore)
+	// for the tags
+	r.GET("/tags", c.TagsIndex)
 	// for the comments
 	r.GET("/articles/:id/comments", c.CommentsIndex)
+	r.GET("/articles/:id/comments/export", c.CommentsExport)
+	r.GET("/articles/:id/comments/new", c.CommentsNew)
+	r.GET("/comments/:id/edit", c.CommentsEdit)
 	r.POST("/comments", c.CommentsCreate)
 	r.GET("/comments/:id", c.CommentsShow)
 	r.DELETE("/comments/:id", c.CommentsDestroy)
 	r.PUT("/comments/:id", c.CommentsUpdate)
-	// Let's start the server
-	r.Run(":" + *servePort)
+	// for the moderation of the comments
+	r.GET("/moderation/comments", c.ModerationComments)
+	r.POST("/comments/:id/approve", c.CommentsApprove)
+	r.POST("/comments/:id/reject", c.CommentsReject)
+	// for the search
+	r.GET("/search", c.SearchIndex)
+	// for the audit log
+	r.GET("/audit", c.AuditIndex)
+	// for the bulk imports
+	r.POST("/imports", c.ImportsCreate)
+	return r, nil
+}
+
+// publishScheduled publish the scheduled articles when their time has come, checking every interval until ctx is done.
+func publishScheduled(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ids, err := m.PublishDueArticles(ctx, time.Now())
+		if err != nil {
+			slog.Error("Publish scheduled articles error", "error", err)
+		}
+		if len(ids) != 0 {
+			slog.Info("Published the scheduled articles", "ids", ids)
+		}
+	}
+}
+
+// reloadTLS reload the TLS files on SIGHUP, and when they change if interval isn't 0, until ctx is done.
+func reloadTLS(ctx context.Context, r *tlsconfig.Reloader, interval time.Duration) {
+	if interval > 0 {
+		go r.Watch(ctx, interval)
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		if err := r.Reload(); err != nil {
+			slog.Error("Reload TLS certificate error", "error", err)
+			continue
+		}
+		slog.Info("Reloaded TLS certificate on SIGHUP")
+	}
+}
+
+// waitGroup wait for the group to be done, it tells false if ctx is done first.
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// tlsEnv are the environment variables of the TLS flags.
+var tlsEnv = map[string]string{
+	"tls-cert":            "TLS_CERT",
+	"tls-key":             "TLS_KEY",
+	"tls-client-ca":       "TLS_CLIENT_CA",
+	"tls-client-auth":     "TLS_CLIENT_AUTH",
+	"tls-reload-interval": "TLS_RELOAD_INTERVAL",
+}
+
+// flagsFromEnv set the flags not given on the command line from their environment variables, by the flag names.
+func flagsFromEnv(fs *flag.FlagSet, env map[string]string) error {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for name, key := range env {
+		v, ok := os.LookupEnv(key)
+		if !ok || given[name] {
+			continue
+		}
+		if err := fs.Set(name, v); err != nil {
+			return fmt.Errorf("Parsing %s error: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// fatal log the error that stops the server from starting, then exit.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// methodOverride let the HTML forms send PUT and DELETE by a "_method" field as Rails does,
+// it has to be done before the routing of gin. The form is read here, so it's limited to maxBody bytes here too.
+func methodOverride(h http.Handler, maxBody int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+			if maxBody > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+			}
+			var tooLarge *http.MaxBytesError
+			if err := r.ParseForm(); errors.As(err, &tooLarge) {
+				msg := fmt.Sprintf("The body should be at most %d bytes", maxBody)
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				json.NewEncoder(w).Encode(c.BuildResp("413", msg, nil))
+				return
+			}
+			switch m := strings.ToUpper(r.PostFormValue("_method")); m {
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				r.Method = m
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// loadSessionSecret read the secret to sign the session cookies from a file,
+// a random one is used if no file given, then the sessions don't survive a restart.
+func loadSessionSecret(path string) ([]byte, error) {
+	if path == "" {
+		slog.Warn("No -session-key given, the login sessions will be lost on restart")
+		secret := make([]byte, 32)
+		_, err := rand.Read(secret)
+		return secret, err
+	}
+	secret, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	secret = []byte(strings.TrimSpace(string(secret)))
+	if len(secret) < 32 {
+		return nil, errors.New("the session secret should be at least 32 bytes")
+	}
+	return secret, nil
 }
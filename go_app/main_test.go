@@ -0,0 +1,44 @@
+//go:build dbtest
+
+// The tests of the main package need the database the models connect to on init,
+// they're only built with the dbtest tag, e.g. by make test-db.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	c "./controllers"
+	"./src/ratelimit"
+)
+
+// staticRoutes are the routes of the static files, which aren't in the OpenAPI document.
+var staticRoutes = map[string]bool{
+	"GET /favicon.ico":            true,
+	"HEAD /favicon.ico":           true,
+	"GET /docs/assets/*filepath":  true,
+	"HEAD /docs/assets/*filepath": true,
+}
+
+// TestRoutesDocumented fail for each route of the router missing from the OpenAPI document of controllers/openapi.go.
+func TestRoutesDocumented(t *testing.T) {
+	r, err := newRouter(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, make([]byte, 32), nil, ratelimit.NewMemoryStore(), nil, c.Validate(false, 0))
+	if err != nil {
+		t.Fatalf("Create router error: %v", err)
+	}
+	spec := c.APISpec()
+	for _, route := range r.Routes() {
+		if staticRoutes[route.Method+" "+route.Path] {
+			continue
+		}
+		if !spec.Has(route.Method, route.Path) {
+			t.Errorf("%s %s is missing from the OpenAPI document", route.Method, route.Path)
+		}
+	}
+	if _, err := json.Marshal(spec); err != nil {
+		t.Errorf("Encode the OpenAPI document error: %v", err)
+	}
+}
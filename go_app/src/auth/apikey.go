@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"../logging"
+	m "../models"
+)
+
+// apiKeyPrefixLen is the length of the leading part of a key stored in clear,
+// to tell the keys apart when listing them.
+const apiKeyPrefixLen = 8
+
+// lastUsedEvery is how stale last_used_at can get, so a busy key isn't written on every request.
+const lastUsedEvery = time.Minute
+
+// GenerateAPIKey create a new API key record named name acting as the user of userId (0 for none),
+// the key itself is only returned here, what's stored is its SHA-256 digest.
+func GenerateAPIKey(ctx context.Context, name string, userId int64) (key string, ak *m.ApiKey, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	key = base64.RawURLEncoding.EncodeToString(b)
+	ak = &m.ApiKey{Name: name, KeyDigest: DigestAPIKey(key), KeyPrefix: key[:apiKeyPrefixLen], UserId: userId}
+	if ak.Id, err = ak.Create(ctx); err != nil {
+		return "", nil, err
+	}
+	return key, ak, nil
+}
+
+// DigestAPIKey is the hex encoded SHA-256 digest of a key. The keys are random and long enough,
+// so a fast digest without salt is fine and lets the key be looked up by its digest.
+func DigestAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAPIKey find the API key record matching key and record its usage at most every lastUsedEvery.
+func VerifyAPIKey(ctx context.Context, key string) (*m.ApiKey, error) {
+	aks, err := m.FindApiKeysBy(ctx, "key_digest", DigestAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if len(aks) == 0 {
+		return nil, errors.New("Invalid API key")
+	}
+	ak := &aks[0]
+	if ak.RevokedAt != nil {
+		return nil, errors.New("API key revoked")
+	}
+	now := time.Now()
+	if ak.LastUsedAt == nil || now.Sub(*ak.LastUsedAt) > lastUsedEvery {
+		ak.LastUsedAt = &now
+		if err := m.UpdateApiKey(ctx, ak.Id, map[string]interface{}{"last_used_at": now}); err != nil {
+			logging.FromContext(ctx).Error("Recording the API key usage error", "api_key_id", ak.Id, "error", err)
+		}
+	}
+	return ak, nil
+}
+
+// RevokeAPIKey revoke the API key with the id, it can't be used any more.
+func RevokeAPIKey(ctx context.Context, id int64) error {
+	ak, err := m.FindApiKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ak.RevokedAt != nil {
+		return errors.New("API key already revoked")
+	}
+	return ak.Update(ctx, map[string]interface{}{"revoked_at": time.Now()})
+}
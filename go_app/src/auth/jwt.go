@@ -0,0 +1,147 @@
+// Package auth verifies the credentials of the API clients: static API keys and JWT bearer tokens.
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// Claims are the registered claims of a JWT used by the app, plus the raw claims set.
+type Claims struct {
+	Subject   string                 `json:"sub"`
+	Issuer    string                 `json:"iss"`
+	ExpiresAt int64                  `json:"exp"`
+	NotBefore int64                  `json:"nbf"`
+	Raw       map[string]interface{} `json:"-"`
+}
+
+// JWTVerifier verifies HS256 or RS256 signed tokens with a key loaded from a local file.
+// The algorithm is decided by the key, so a token can't pick a weaker one by its header.
+type JWTVerifier struct {
+	alg    string
+	secret []byte
+	pub    *rsa.PublicKey
+	// Leeway is the clock skew tolerated when checking exp and nbf.
+	Leeway time.Duration
+}
+
+// LoadJWTKeyFile load the key to verify tokens: a PEM encoded RSA public key
+// (or certificate) for RS256, anything else is taken as the HS256 secret.
+func LoadJWTKeyFile(path string) (*JWTVerifier, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewJWTVerifier(b)
+}
+
+// NewJWTVerifier is the same as LoadJWTKeyFile but with the key content.
+func NewJWTVerifier(key []byte) (*JWTVerifier, error) {
+	v := &JWTVerifier{Leeway: 30 * time.Second}
+	block, _ := pem.Decode(key)
+	if block == nil {
+		secret := []byte(strings.TrimSpace(string(key)))
+		if len(secret) < 32 {
+			return nil, errors.New("HS256 secret should be at least 32 bytes")
+		}
+		v.alg, v.secret = "HS256", secret
+		return v, nil
+	}
+	var pub interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			pub = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported PEM block type for JWT key: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("JWT key is not a RSA public key")
+	}
+	v.alg, v.pub = "RS256", rsaPub
+	return v, nil
+}
+
+// Verify check the signature and the time claims of a compact serialized token, the exp claim is required.
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("Malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("Malformed token header: %v", err)
+	}
+	if header.Alg != v.alg {
+		return nil, fmt.Errorf("Unexpected signing algorithm: %s", header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("Malformed token signature: %v", err)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	switch v.alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write(signed)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, errors.New("Invalid token signature")
+		}
+	case "RS256":
+		sum := sha256.Sum256(signed)
+		if err = rsa.VerifyPKCS1v15(v.pub, crypto.SHA256, sum[:], sig); err != nil {
+			return nil, errors.New("Invalid token signature")
+		}
+	}
+	claims := &Claims{}
+	if err = decodeSegment(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("Malformed token claims: %v", err)
+	}
+	if err = decodeSegment(parts[1], &claims.Raw); err != nil {
+		return nil, fmt.Errorf("Malformed token claims: %v", err)
+	}
+	// a token without exp would be valid forever
+	if claims.ExpiresAt == 0 {
+		return nil, errors.New("Token has no expiration")
+	}
+	now := time.Now()
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return nil, errors.New("Token is expired")
+	}
+	if claims.NotBefore != 0 && now.Add(v.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, errors.New("Token is not valid yet")
+	}
+	return claims, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLen is the minimum length of a user password.
+const MinPasswordLen = 8
+
+// HashPassword hash a password with bcrypt, the same as has_secure_password in Rails.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLen {
+		return "", errors.New("Password is too short")
+	}
+	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(b), err
+}
+
+// CheckPassword tell whether the password matches the digest.
+func CheckPassword(digest, password string) bool {
+	if digest == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(digest), []byte(password)) == nil
+}
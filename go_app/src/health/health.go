@@ -0,0 +1,114 @@
+// Package health runs the checks telling whether the app is ready to serve, e.g. the database is reachable,
+// for the readiness probes of the orchestrators. The app is never ready once it's draining for a shutdown.
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The statuses of the checks and the app.
+const (
+	Pass = "pass"
+	Fail = "fail"
+)
+
+// ErrDraining fails the readiness while the app is shutting down.
+var ErrDraining = errors.New("The app is shutting down")
+
+// CheckFunc checks a dependency within the deadline of ctx, it returns the details worth reporting
+// even when it fails, e.g. the stats of the database pool.
+type CheckFunc func(ctx context.Context) (detail interface{}, err error)
+
+// Result is the outcome of a check.
+type Result struct {
+	Status   string      `json:"status"`
+	Error    string      `json:"error,omitempty"`
+	Detail   interface{} `json:"detail,omitempty"`
+	Duration float64     `json:"duration_ms"`
+}
+
+// Checker runs the checks of the readiness, each one with the timeout.
+type Checker struct {
+	Timeout  time.Duration
+	names    []string
+	checks   map[string]CheckFunc
+	draining int32
+}
+
+// NewChecker create a checker running each check with the timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{Timeout: timeout, checks: map[string]CheckFunc{}}
+}
+
+// Add register a check by its name, the checks should be added at the start before any run.
+func (h *Checker) Add(name string, fn CheckFunc) {
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+		sort.Strings(h.names)
+	}
+	h.checks[name] = fn
+}
+
+// Drain fail the readiness from now on, so no new traffic is sent while the app shuts down.
+func (h *Checker) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Draining tell whether the app is shutting down.
+func (h *Checker) Draining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+// Ready run all the checks at once and tell whether they all passed, with the result of each one by its name.
+func (h *Checker) Ready(ctx context.Context) (bool, map[string]Result) {
+	results := make(map[string]Result, len(h.names)+1)
+	ready := true
+	if h.Draining() {
+		results["shutdown"] = Result{Status: Fail, Error: ErrDraining.Error()}
+		ready = false
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range h.names {
+		wg.Add(1)
+		go func(name string, fn CheckFunc) {
+			defer wg.Done()
+			r := h.run(ctx, fn)
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = r
+			ready = ready && r.Status == Pass
+		}(name, h.checks[name])
+	}
+	wg.Wait()
+	return ready, results
+}
+
+// run a check with the timeout, a check not returning in time fails.
+func (h *Checker) run(ctx context.Context, fn CheckFunc) Result {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan Result, 1)
+	go func() {
+		detail, err := fn(ctx)
+		r := Result{Status: Pass, Detail: detail}
+		if err != nil {
+			r.Status, r.Error = Fail, err.Error()
+		}
+		done <- r
+	}()
+	var r Result
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		r = Result{Status: Fail, Error: "Timed out after " + h.Timeout.String()}
+	}
+	r.Duration = float64(time.Since(start).Microseconds()) / 1000
+	return r
+}
@@ -0,0 +1,395 @@
+// Package importer loads articles and comments in bulk from NDJSON or CSV,
+// it's shared by the "myapp import" command and the POST /imports endpoint.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	m "../models"
+	"../moderation"
+	"github.com/asaskevich/govalidator"
+)
+
+// Options of an import run.
+type Options struct {
+	// Resource is what to import, "articles" or "comments".
+	Resource string
+	// Format of the input, "ndjson" or "csv".
+	Format string
+	// DryRun validates and resolves every record but writes nothing.
+	DryRun bool
+	// UpsertBy is the field used to find an existing record to update instead of inserting a new one,
+	// e.g. "title" for articles. Empty means always insert.
+	UpsertBy string
+	// ArticleKey is the article field matched against the "article_key" of a comment record,
+	// "title" by default.
+	ArticleKey string
+}
+
+// upsertFields are the fields allowed in Options.UpsertBy for each resource.
+var upsertFields = map[string][]string{
+	"articles": {"id", "title"},
+	"comments": {"id"},
+}
+
+// Counts is the number of records inserted, updated or failed.
+type Counts struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Failed   int `json:"failed"`
+}
+
+// LineError is an error of the record at a line of the input.
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// Report is the result of an import run.
+type Report struct {
+	Resource string `json:"resource"`
+	DryRun   bool   `json:"dry_run"`
+	Counts
+	// Comments counts the comments nested in article records.
+	Comments *Counts     `json:"comments,omitempty"`
+	Errors   []LineError `json:"errors,omitempty"`
+}
+
+// articleRecord is an article with its tags by their names, the tags are left as they are if missing.
+type articleRecord struct {
+	m.Article
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+// commentRecord is a comment with an external key of its article.
+type commentRecord struct {
+	m.Comment
+	ArticleKey string `json:"article_key,omitempty"`
+}
+
+// Run import the records read from r and report the counts, a record failed doesn't stop the run.
+// The returned error is for a wrong option or an unreadable input only.
+func Run(ctx context.Context, r io.Reader, opt Options) (*Report, error) {
+	if opt.Resource != "articles" && opt.Resource != "comments" {
+		return nil, fmt.Errorf("Unknown resource to import: %q", opt.Resource)
+	}
+	if opt.UpsertBy != "" && !contains(upsertFields[opt.Resource], opt.UpsertBy) {
+		return nil, fmt.Errorf("Can't upsert %s by %q, one of %v expected", opt.Resource, opt.UpsertBy, upsertFields[opt.Resource])
+	}
+	if opt.ArticleKey == "" {
+		opt.ArticleKey = "title"
+	}
+	if !contains(upsertFields["articles"], opt.ArticleKey) {
+		return nil, fmt.Errorf("Can't resolve articles by %q, one of %v expected", opt.ArticleKey, upsertFields["articles"])
+	}
+	imp := &importer{opt: opt, keys: map[string]int64{}, report: &Report{Resource: opt.Resource, DryRun: opt.DryRun}}
+	if opt.Resource == "articles" {
+		imp.report.Comments = &Counts{}
+	}
+	var err error
+	switch opt.Format {
+	case "ndjson", "json":
+		err = imp.readNDJSON(ctx, r)
+	case "csv":
+		err = imp.readCSV(ctx, r)
+	default:
+		err = fmt.Errorf("Unsupported import format: %q", opt.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return imp.report, nil
+}
+
+// FormatOf guess the input format by a file name.
+func FormatOf(filename string) string {
+	switch {
+	case strings.HasSuffix(filename, ".csv"):
+		return "csv"
+	case strings.HasSuffix(filename, ".json"), strings.HasSuffix(filename, ".ndjson"), strings.HasSuffix(filename, ".jsonl"):
+		return "ndjson"
+	}
+	return ""
+}
+
+type importer struct {
+	opt Options
+	// keys map the article keys seen in this run to their ids,
+	// so comments can refer to articles inserted by the same run, the id is 0 in a dry run.
+	keys   map[string]int64
+	report *Report
+}
+
+func (imp *importer) readNDJSON(ctx context.Context, r io.Reader) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(bytes.TrimSpace(b)) > 0 {
+			imp.record(ctx, line, func(v interface{}) error { return json.Unmarshal(b, v) })
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+func (imp *importer) readCSV(ctx context.Context, r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("Reading CSV header error: %v", err)
+	}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// a quoted field can span lines, so the line is the one the record starts at
+			line := 0
+			if pe, ok := err.(*csv.ParseError); ok {
+				line = pe.StartLine
+			}
+			imp.fail(line, err)
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		imp.record(ctx, line, func(v interface{}) error { return decodeRow(header, row, v) })
+	}
+}
+
+// record decode and import a single record, the errors are collected into the report.
+func (imp *importer) record(ctx context.Context, line int, decode func(v interface{}) error) {
+	var err error
+	if imp.opt.Resource == "articles" {
+		ar := articleRecord{}
+		if err = decode(&ar); err == nil {
+			err = imp.article(ctx, line, &ar)
+		}
+	} else {
+		cr := commentRecord{}
+		if err = decode(&cr); err == nil {
+			err = imp.comment(ctx, &cr)
+		}
+	}
+	if err != nil {
+		imp.fail(line, err)
+	}
+}
+
+func (imp *importer) fail(line int, err error) {
+	imp.report.Failed++
+	imp.report.Errors = append(imp.report.Errors, LineError{Line: line, Error: err.Error()})
+}
+
+func (imp *importer) article(ctx context.Context, line int, rec *articleRecord) error {
+	ar := &rec.Article
+	comments := ar.Comments
+	ar.Comments = nil
+	if ar.Status == "" {
+		// the imported articles were already published elsewhere, unless their status says otherwise
+		ar.Status = m.ArticlePublished
+	}
+	if ar.Status == m.ArticlePublished && ar.PublishedAt == nil {
+		t := ar.CreatedAt
+		if t.IsZero() {
+			t = time.Now()
+		}
+		ar.PublishedAt = &t
+	}
+	if _, err := govalidator.ValidateStruct(ar); err != nil {
+		return fmt.Errorf("Validate Article error: %v", err)
+	}
+	var tags []string
+	if rec.Tags != nil {
+		var err error
+		if tags, err = m.NormalizeTagNames(*rec.Tags); err != nil {
+			return err
+		}
+	}
+	for i := range comments {
+		if comments[i].Status == "" {
+			comments[i].Status = moderation.Approved
+		}
+		if _, err := govalidator.ValidateStruct(&comments[i]); err != nil {
+			return fmt.Errorf("Validate comment #%d error: %v", i+1, err)
+		}
+	}
+	existing, err := imp.findArticle(ctx, ar)
+	if err != nil {
+		return err
+	}
+	if !imp.opt.DryRun {
+		if existing != nil {
+			ar.Id = existing.Id
+			err = m.UpdateArticle(ctx, ar.Id, map[string]interface{}{"title": ar.Title, "text": ar.Text})
+		} else {
+			ar.Id, err = ar.Create(ctx)
+		}
+		if err == nil && rec.Tags != nil {
+			_, err = m.SetArticleTags(ctx, ar.Id, tags)
+		}
+		if err == nil {
+			_, err = m.SaveArticleRevision(ctx, ar.Id, 0)
+		}
+		if err != nil {
+			return err
+		}
+	} else if existing != nil {
+		ar.Id = existing.Id
+	}
+	if existing != nil {
+		imp.report.Updated++
+	} else {
+		imp.report.Inserted++
+	}
+	imp.keys[articleKey(imp.opt.ArticleKey, ar)] = ar.Id
+	for i, cm := range comments {
+		cm.ArticleId = ar.Id
+		if !imp.opt.DryRun {
+			if _, err := cm.Create(ctx); err != nil {
+				imp.report.Comments.Failed++
+				imp.report.Errors = append(imp.report.Errors, LineError{Line: line, Error: fmt.Sprintf("Create comment #%d error: %v", i+1, err)})
+				continue
+			}
+		}
+		imp.report.Comments.Inserted++
+	}
+	return nil
+}
+
+func (imp *importer) comment(ctx context.Context, cr *commentRecord) error {
+	cm := &cr.Comment
+	if cr.ArticleKey != "" {
+		id, ok := imp.keys[cr.ArticleKey]
+		if !ok {
+			ar, err := m.FindArticleBy(ctx, imp.opt.ArticleKey, cr.ArticleKey)
+			if err != nil {
+				return fmt.Errorf("Resolve article %s %q error: %v", imp.opt.ArticleKey, cr.ArticleKey, err)
+			}
+			id = ar.Id
+			imp.keys[cr.ArticleKey] = id
+		}
+		cm.ArticleId = id
+	} else if cm.ArticleId != 0 {
+		if _, err := m.FindArticle(ctx, cm.ArticleId); err != nil {
+			return fmt.Errorf("Find article %d error: %v", cm.ArticleId, err)
+		}
+	} else {
+		return errors.New("No article_id or article_key provided")
+	}
+	if cm.Status == "" {
+		// the imported comments were already published elsewhere, they skip the moderation queue
+		cm.Status = moderation.Approved
+	}
+	if _, err := govalidator.ValidateStruct(cm); err != nil {
+		return fmt.Errorf("Validate Comment error: %v", err)
+	}
+	var existing *m.Comment
+	if imp.opt.UpsertBy == "id" && cm.Id != 0 {
+		existing, _ = m.FindComment(ctx, cm.Id)
+	}
+	if !imp.opt.DryRun {
+		var err error
+		if existing != nil {
+			err = m.UpdateComment(ctx, cm.Id, map[string]interface{}{"commenter": cm.Commenter, "body": cm.Body, "article_id": cm.ArticleId})
+		} else {
+			_, err = cm.Create(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if existing != nil {
+		imp.report.Updated++
+	} else {
+		imp.report.Inserted++
+	}
+	return nil
+}
+
+// findArticle find the existing article to be updated by the upsert field, nil means none.
+func (imp *importer) findArticle(ctx context.Context, ar *m.Article) (*m.Article, error) {
+	field := imp.opt.UpsertBy
+	if field == "" || field == "id" && ar.Id == 0 {
+		return nil, nil
+	}
+	key := articleKey(field, ar)
+	if id, ok := imp.keys[key]; ok && field == imp.opt.ArticleKey {
+		// the same article appeared earlier in the input, it's not in the database yet in a dry run
+		return &m.Article{Id: id}, nil
+	}
+	var val interface{} = ar.Title
+	if field == "id" {
+		val = ar.Id
+	}
+	existing, err := m.FindArticlesBy(ctx, field, val)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 1 {
+		return nil, fmt.Errorf("More than one article found by %s %q", field, key)
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+	return &existing[0], nil
+}
+
+func articleKey(field string, ar *m.Article) string {
+	if field == "id" {
+		return strconv.FormatInt(ar.Id, 10)
+	}
+	return ar.Title
+}
+
+// decodeRow decode a CSV row into v by the column names in the header through JSON,
+// so the CSV columns are named the same as the JSON fields.
+func decodeRow(header, row []string, v interface{}) error {
+	fields := map[string]interface{}{}
+	for i, name := range header {
+		if i >= len(row) || row[i] == "" {
+			continue
+		}
+		switch name {
+		case "id", "article_id":
+			n, err := strconv.ParseInt(row[i], 10, 64)
+			if err != nil {
+				return fmt.Errorf("Parsing %s error: %v", name, err)
+			}
+			fields[name] = n
+		case "tags":
+			fields[name] = strings.Split(row[i], ",")
+		default:
+			fields[name] = row[i]
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
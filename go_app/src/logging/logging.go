@@ -0,0 +1,53 @@
+// Package logging sets up the structured logging of the app by log/slog,
+// as JSON lines for the log pipelines or as text to read in a terminal.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Formats are the formats of the logs.
+var Formats = []string{"json", "text"}
+
+// New create a logger writing to w the records of the level and above, debug, info, warn or error,
+// in the format json or text. The debug records have the source file and line.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("Unknown log level %q, it should be debug, info, warn or error", level)
+	}
+	opts := &slog.HandlerOptions{Level: l, AddSource: l <= slog.LevelDebug}
+	switch strings.ToLower(format) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("Unknown log format %q, it should be one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Setup make the logger the default one of slog, and of the log package too. The messages of the log package
+// are left by the dependencies, they're logged at the warn level.
+func Setup(l *slog.Logger) {
+	slog.SetDefault(l)
+	slog.SetLogLoggerLevel(slog.LevelWarn)
+}
+
+type loggerKey struct{}
+
+// WithLogger get a copy of ctx carrying the logger, e.g. the one of a request with its id.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext get the logger carried by ctx, the default one if none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
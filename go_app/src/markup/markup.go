@@ -0,0 +1,97 @@
+// Package markup renders the CommonMark of the articles and the comments to HTML,
+// which is sanitized by an allowlist of the elements and their attributes, so it's safe to show in the pages.
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// DefaultAllowlist is the elements kept by the sanitizer with their attributes in brackets,
+// the links get rel="nofollow" and only the http, https, mailto and relative URLs are kept.
+const DefaultAllowlist = "p,br,hr,h1,h2,h3,h4,h5,h6,em,strong,del,code[class],pre,blockquote,ul,ol[start],li,a[href title],img[src alt title]"
+
+// the raw HTML of the text is passed through, the sanitizer is what decides what's kept of it
+var md = goldmark.New(goldmark.WithRendererOptions(gmhtml.WithUnsafe()))
+
+var sanitizer = mustPolicy(DefaultAllowlist)
+
+// strict keeps no element at all, for the plain text.
+var strict = bluemonday.StrictPolicy()
+
+var (
+	allowedElement = regexp.MustCompile(`^([a-z][a-z0-9]*)(?:\[([a-z \-]*)\])?$`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+)
+
+// urlAttrs are the attributes holding URLs, their schemes are checked.
+var urlAttrs = map[string]bool{"href": true, "src": true, "cite": true}
+
+// ParseAllowlist build a sanitizer policy from an allowlist like DefaultAllowlist,
+// the elements separated by commas with their allowed attributes separated by spaces in brackets.
+func ParseAllowlist(s string) (*bluemonday.Policy, error) {
+	p := bluemonday.NewPolicy()
+	urls := false
+	for _, item := range strings.Split(s, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		parts := allowedElement.FindStringSubmatch(item)
+		if parts == nil {
+			return nil, fmt.Errorf("Invalid allowed element %q, NAME or NAME[ATTR ATTR] expected", item)
+		}
+		p.AllowElements(parts[1])
+		if attrs := strings.Fields(parts[2]); len(attrs) > 0 {
+			p.AllowAttrs(attrs...).OnElements(parts[1])
+			for _, a := range attrs {
+				urls = urls || urlAttrs[a]
+			}
+		}
+	}
+	if urls {
+		p.AllowStandardURLs()
+	}
+	return p, nil
+}
+
+func mustPolicy(s string) *bluemonday.Policy {
+	p, err := ParseAllowlist(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// SetAllowlist replace the allowlist of the sanitizer, it should be set at the start before any rendering.
+func SetAllowlist(s string) error {
+	p, err := ParseAllowlist(s)
+	if err != nil {
+		return err
+	}
+	sanitizer = p
+	return nil
+}
+
+// HTML render the CommonMark text to sanitized HTML.
+func HTML(text string) string {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(text), &buf); err != nil {
+		// the renderer only fails on writing, which a buffer doesn't
+		return html.EscapeString(text)
+	}
+	return sanitizer.Sanitize(buf.String())
+}
+
+// Plain get the text of the rendered HTML without any markup, e.g. for the previews and the notifications.
+func Plain(renderedHTML string) string {
+	s := html.UnescapeString(strict.Sanitize(renderedHTML))
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
@@ -0,0 +1,66 @@
+package markup
+
+import "testing"
+
+func TestParseAllowlist(t *testing.T) {
+	tests := []struct {
+		allowlist string
+		in        string
+		want      string
+	}{
+		{"p,em", "<p>a <em>b</em> <strong>c</strong></p>", "<p>a <em>b</em> c</p>"},
+		{" P , EM ", "<p><em>b</em></p>", "<p><em>b</em></p>"},
+		{"p,,em,", "<p><em>b</em></p>", "<p><em>b</em></p>"},
+		{"code[class]", `<code class="go" id="x">a</code>`, `<code class="go">a</code>`},
+		{"code", `<code class="go">a</code>`, `<code>a</code>`},
+		{"ol[start reversed]", `<ol start="3" reversed="">x</ol>`, `<ol start="3" reversed="">x</ol>`},
+		{"a[href]", `<a href="https://example.com/" onclick="x()">a</a>`, `<a href="https://example.com/" rel="nofollow">a</a>`},
+		{"a[href]", `<a href="/articles/1">a</a>`, `<a href="/articles/1" rel="nofollow">a</a>`},
+		{"a[href]", `<a href="javascript:alert(1)">a</a>`, `a`},
+		{"img[src alt]", `<img src="data:image/png;base64,AA" alt="x">`, `<img alt="x">`},
+		{"p", "<p>a<script>alert(1)</script></p>", "<p>a</p>"},
+	}
+	for _, tt := range tests {
+		p, err := ParseAllowlist(tt.allowlist)
+		if err != nil {
+			t.Errorf("ParseAllowlist(%q) error: %v", tt.allowlist, err)
+			continue
+		}
+		if got := p.Sanitize(tt.in); got != tt.want {
+			t.Errorf("ParseAllowlist(%q) sanitized %q to %q, want %q", tt.allowlist, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAllowlistErrors(t *testing.T) {
+	for _, s := range []string{"p,<script>", "a[href", "a[href]x", "1p", "a[on_click]"} {
+		if _, err := ParseAllowlist(s); err == nil {
+			t.Errorf("ParseAllowlist(%q) gave no error", s)
+		}
+	}
+}
+
+func TestDefaultAllowlist(t *testing.T) {
+	if _, err := ParseAllowlist(DefaultAllowlist); err != nil {
+		t.Fatalf("ParseAllowlist of the default error: %v", err)
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"*a* **b**", "<p><em>a</em> <strong>b</strong></p>\n"},
+		{"[x](https://example.com)", "<p><a href=\"https://example.com\" rel=\"nofollow\">x</a></p>\n"},
+		{"<div onclick=\"x()\">raw</div>", "raw"},
+	}
+	for _, tt := range tests {
+		if got := HTML(tt.text); got != tt.want {
+			t.Errorf("HTML(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPlain(t *testing.T) {
+	if got, want := Plain("<p>a &amp; <em>b</em></p>\n\n\n\n<p>c</p>"), "a & b\n\nc"; got != want {
+		t.Errorf("Plain = %q, want %q", got, want)
+	}
+}
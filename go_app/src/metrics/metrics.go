@@ -0,0 +1,74 @@
+// Package metrics exposes the activity of the app in the Prometheus text format:
+// the HTTP requests by route and status, the stats of the database pool and the durations of the model functions.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of all the metrics of the app.
+const namespace = "myapp"
+
+// Unmatched is the route label of the requests matching no route, so the unknown paths don't make new series.
+const Unmatched = "unmatched"
+
+// Registry holds the metrics of the app, besides the ones of the Go runtime and the process.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "http", Name: "requests_total",
+		Help: "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds",
+		Help:    "Latency of the HTTP requests by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "db", Name: "query_duration_seconds",
+		Help:    "Duration of the statements run by the model functions, by function.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"function"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, queryDuration,
+	)
+}
+
+// ObserveRequest count a HTTP request of the route, the pattern of the path like "/articles/:id", with its latency.
+func ObserveRequest(method, route string, status int, d time.Duration) {
+	if route == "" {
+		route = Unmatched
+	}
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+// ObserveQuery is a query hook of the models recording the durations of the statements by model function,
+// e.g. models.OnQuery(metrics.ObserveQuery).
+func ObserveQuery(function string, d time.Duration) {
+	queryDuration.WithLabelValues(function).Observe(d.Seconds())
+}
+
+// WatchDB export the stats of the connection pool of the database as gauges and counters, with the db label name.
+func WatchDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
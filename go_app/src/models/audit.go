@@ -0,0 +1,207 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditLog turns on the audit log of the writes on the articles and the comments by the model functions,
+// the *BySql functions aren't audited.
+var AuditLog = true
+
+// DefaultAuditActor is the actor of the writes done outside of a request, e.g. by the scheduler or a command.
+var DefaultAuditActor = "system"
+
+// auditBatchSize is how many rows are read at once for the snapshots and the exports.
+const auditBatchSize = 500
+
+var auditedTables = map[string]bool{"articles": true, "comments": true}
+
+func auditing(table string) bool {
+	return AuditLog && auditedTables[table]
+}
+
+// AuditInfo is who makes the writes and from where, recorded with them in the audit log.
+type AuditInfo struct {
+	// ActorId is the id of the user, 0 for none.
+	ActorId int64
+	// Actor describes the client, e.g. "session:3", "api_key:deploy" or "system".
+	Actor     string
+	RequestId string
+	IP        string
+}
+
+// AuditEvent is a create, an update or a destroy of a record, with the record as JSON before and after it,
+// null for a create and a destroy respectively. The audit_events table is only appended to.
+type AuditEvent struct {
+	Id        int64           `json:"id" db:"id"`
+	Resource  string          `json:"resource" db:"resource"`
+	RecordId  int64           `json:"record_id" db:"record_id"`
+	Action    string          `json:"action" db:"action"`
+	ActorId   int64           `json:"actor_id,omitempty" db:"actor_id"`
+	Actor     string          `json:"actor" db:"actor"`
+	RequestId string          `json:"request_id,omitempty" db:"request_id"`
+	IP        string          `json:"ip,omitempty" db:"ip"`
+	Before    json.RawMessage `json:"before" db:"before_state"`
+	After     json.RawMessage `json:"after" db:"after_state"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type auditKey struct{}
+
+// WithAudit get a copy of ctx recording the writes of the model functions called with it by the info,
+// e.g. the client and the id of a request. The writes without it are recorded by DefaultAuditActor.
+func WithAudit(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditKey{}, info)
+}
+
+// auditInfo get who makes the writes of ctx.
+func auditInfo(ctx context.Context) AuditInfo {
+	if info, ok := ctx.Value(auditKey{}).(AuditInfo); ok {
+		return info
+	}
+	return AuditInfo{Actor: DefaultAuditActor}
+}
+
+// snapshots are the records of a table as JSON by their ids.
+type snapshots map[int64]json.RawMessage
+
+// beforeWrite get the records before they are updated or destroyed, for the audit log.
+func beforeWrite(ctx context.Context, table string, ids ...int64) snapshots {
+	if !auditing(table) || len(ids) == 0 {
+		return nil
+	}
+	return snapshot(ctx, table, ids)
+}
+
+func snapshot(ctx context.Context, table string, ids []int64) snapshots {
+	rows := snapshots{}
+	for start := 0; start < len(ids); start += auditBatchSize {
+		end := start + auditBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var err error
+		switch table {
+		case "articles":
+			var articles []Article
+			articles, err = FindArticles(ctx, ids[start:end]...)
+			for _, ar := range articles {
+				rows[ar.Id], _ = json.Marshal(ar)
+			}
+		case "comments":
+			var comments []Comment
+			comments, err = FindComments(ctx, ids[start:end]...)
+			for _, cm := range comments {
+				rows[cm.Id], _ = json.Marshal(cm)
+			}
+		}
+		if err != nil {
+			logger(ctx).Error("Snapshot for the audit log error", "table", table, "ids", ids[start:end], "error", err)
+		}
+	}
+	return rows
+}
+
+// recordAudit append an event to the audit log for each record written, by the actor of ctx.
+// The errors are only logged, they don't fail the writes done already.
+func recordAudit(ctx context.Context, table, op string, before snapshots, ids []int64) {
+	ctx = observeQuery(ctx, "recordAudit")
+	var after snapshots
+	if op != Destroyed {
+		after = snapshot(ctx, table, ids)
+	}
+	info := auditInfo(ctx)
+	t := time.Now()
+	sql := `INSERT INTO audit_events (resource,record_id,action,actor_id,actor,request_id,ip,before_state,after_state,created_at) VALUES (:resource,:record_id,:action,NULLIF(:actor_id, 0),:actor,:request_id,:ip,:before_state,:after_state,:created_at)`
+	for _, id := range ids {
+		e := AuditEvent{
+			Resource: table, RecordId: id, Action: op,
+			ActorId: info.ActorId, Actor: info.Actor, RequestId: info.RequestId, IP: info.IP,
+			Before: before[id], After: after[id], CreatedAt: t,
+		}
+		if _, err := DB.NamedExecContext(ctx, sql, e); err != nil {
+			logger(ctx).Error("Record in the audit log error", "table", table, "id", id, "action", op, "error", err)
+		}
+	}
+}
+
+// AuditQuery selects the audit events, its zero fields match any.
+type AuditQuery struct {
+	Resource  string
+	RecordId  int64
+	ActorId   int64
+	RequestId string
+	// Since is the earliest time of the events, BeforeId is the id the events are before, for the paging.
+	Since    time.Time
+	BeforeId int64
+}
+
+// Where build the where clause of the query.
+func (q AuditQuery) Where() (string, []interface{}, error) {
+	conds, args := []string{"1 = 1"}, []interface{}{}
+	if q.Resource != "" {
+		if !auditedTables[q.Resource] {
+			return "", nil, fmt.Errorf("Unknown resource %q, it should be articles or comments", q.Resource)
+		}
+		conds, args = append(conds, "resource = ?"), append(args, q.Resource)
+	}
+	if q.RecordId != 0 {
+		conds, args = append(conds, "record_id = ?"), append(args, q.RecordId)
+	}
+	if q.ActorId != 0 {
+		conds, args = append(conds, "actor_id = ?"), append(args, q.ActorId)
+	}
+	if q.RequestId != "" {
+		conds, args = append(conds, "request_id = ?"), append(args, q.RequestId)
+	}
+	if !q.Since.IsZero() {
+		conds, args = append(conds, "created_at >= ?"), append(args, q.Since)
+	}
+	if q.BeforeId != 0 {
+		conds, args = append(conds, "id < ?"), append(args, q.BeforeId)
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+const auditEventColumns = "id, resource, record_id, action, COALESCE(actor_id, 0) AS actor_id, actor, request_id, ip, COALESCE(before_state, 'null') AS before_state, COALESCE(after_state, 'null') AS after_state, created_at"
+
+// FindAuditEventsWhere find the audit events by a where clause, which may be empty,
+// e.g. FindAuditEventsWhere("resource = ? AND record_id = ? ORDER BY id DESC LIMIT 100", "articles", 42)
+func FindAuditEventsWhere(ctx context.Context, where string, args ...interface{}) (events []AuditEvent, err error) {
+	ctx = observeQuery(ctx, "FindAuditEventsWhere")
+	sql := "SELECT " + auditEventColumns + " FROM audit_events"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	err = DB.SelectContext(ctx, &events, DB.Rebind(sql), args...)
+	return events, err
+}
+
+// AuditEventsInBatchesWhere call fn with the audit events of the where clause by batches in the order of their ids,
+// until all are done, fn returns an error or ctx is done.
+func AuditEventsInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]AuditEvent) error) error {
+	if where != "" {
+		where = "(" + where + ") AND "
+	}
+	var lastId int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		events, err := FindAuditEventsWhere(ctx, fmt.Sprintf("%sid > ? ORDER BY id ASC LIMIT %d", where, batchSize), append(args, lastId)...)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		if err = fn(events); err != nil {
+			return err
+		}
+		lastId = events[len(events)-1].Id
+	}
+}
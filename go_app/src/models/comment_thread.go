@@ -0,0 +1,110 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// OrphanMode is what happens to the replies of a comment when it's destroyed.
+type OrphanMode string
+
+const (
+	// Reparent moves the replies up to the parent of the destroyed comment, or to the top level.
+	Reparent OrphanMode = "reparent"
+	// Tombstone keeps the destroyed comment as a placeholder in the thread while it has replies.
+	Tombstone OrphanMode = "tombstone"
+)
+
+// TombstoneBody replaces the body of a destroyed comment kept as a tombstone,
+// it's shorter than a valid body so it can't be posted.
+const TombstoneBody = "[deleted]"
+
+// ParseOrphanMode parse "reparent" or "tombstone".
+func ParseOrphanMode(s string) (OrphanMode, error) {
+	switch mode := OrphanMode(s); mode {
+	case Reparent, Tombstone:
+		return mode, nil
+	}
+	return "", fmt.Errorf("Unknown orphan mode %q, it should be reparent or tombstone", s)
+}
+
+// IsTombstone tell whether the comment is the placeholder of a destroyed one.
+func (_comment *Comment) IsTombstone() bool {
+	return _comment.Body == TombstoneBody && _comment.Commenter == ""
+}
+
+// DestroyCommentInThread destroy a comment and take care of its replies by the mode,
+// a comment without replies is always deleted.
+func DestroyCommentInThread(ctx context.Context, cm *Comment, mode OrphanMode) error {
+	replies, err := FindCommentsBy(ctx, "parent_id", cm.Id)
+	if err != nil {
+		return err
+	}
+	if len(replies) != 0 {
+		switch mode {
+		case Tombstone:
+			return UpdateComment(ctx, cm.Id, map[string]interface{}{"body": TombstoneBody, "commenter": "", "user_id": nil, "spam_score": 0})
+		case Reparent:
+			// one by one rather than by SQL, so the moves are audited and hooked
+			var parentId interface{}
+			if cm.ParentId != 0 {
+				parentId = cm.ParentId
+			}
+			for _, r := range replies {
+				if err = UpdateComment(ctx, r.Id, map[string]interface{}{"parent_id": parentId}); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("Unknown orphan mode %q", mode)
+		}
+	}
+	_, err = DestroyCommentsWhere(ctx, "id = ?", cm.Id)
+	return err
+}
+
+// CommentTree nest the comments by their parents in the Replies, and return the top level ones.
+// The replies deeper than maxDepth levels are flattened into their ancestor at the last level,
+// and a comment whose parent isn't in the list is put at the top level.
+func CommentTree(comments []Comment, maxDepth int) []Comment {
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].Id < comments[j].Id })
+	byId := make(map[int64]bool, len(comments))
+	for _, cm := range comments {
+		byId[cm.Id] = true
+	}
+	children := map[int64][]Comment{}
+	roots := []Comment{}
+	for _, cm := range comments {
+		if cm.ParentId != 0 && byId[cm.ParentId] {
+			children[cm.ParentId] = append(children[cm.ParentId], cm)
+		} else {
+			roots = append(roots, cm)
+		}
+	}
+	var nest func(cms []Comment, depth int) []Comment
+	var flatten func(id int64) []Comment
+	nest = func(cms []Comment, depth int) []Comment {
+		for i := range cms {
+			if depth < maxDepth {
+				cms[i].Replies = nest(children[cms[i].Id], depth+1)
+			} else {
+				cms[i].Replies = flatten(cms[i].Id)
+			}
+		}
+		return cms
+	}
+	flatten = func(id int64) []Comment {
+		flat := []Comment{}
+		for _, cm := range children[id] {
+			flat = append(flat, cm)
+			flat = append(flat, flatten(cm.Id)...)
+		}
+		sort.Slice(flat, func(i, j int) bool { return flat[i].Id < flat[j].Id })
+		return flat
+	}
+	return nest(roots, 1)
+}
@@ -1,9 +1,10 @@
 package models
 
 import (
+	"database/sql"
 	"log"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -19,7 +20,13 @@ func init() {
 	if dsn == "" {
 		log.Fatal("Invalid DSN")
 	}
-	DB, err = sqlx.Connect(driver_name, dsn)
+	// the statements are traced and timed by a wrapper of the driver, see tracing.go
+	sql.Register("traced-"+driver_name, tracedDriver{Driver: &mysql.MySQLDriver{}, system: driver_name})
+	db, err := sql.Open("traced-"+driver_name, dsn)
+	if err == nil {
+		DB = sqlx.NewDb(db, driver_name)
+		err = DB.Ping()
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,813 @@
+
+
+// Package models includes the functions on the model ApiKey.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type ApiKey struct {
+	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
+Name string `json:"name,omitempty" db:"name" valid:"required"`
+KeyDigest string `json:"key_digest,omitempty" db:"key_digest" valid:"required"`
+KeyPrefix string `json:"key_prefix,omitempty" db:"key_prefix" valid:"required"`
+LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at" valid:"-"`
+RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at" valid:"-"`
+CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
+UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+UserId int64 `json:"user_id,omitempty" db:"user_id" valid:"-"`
+User User `json:"user,omitempty" db:"user" valid:"-"`
+}
+
+// DataStruct for the pagination
+type ApiKeyPage struct {
+	WhereString string
+	WhereParams []interface{}
+	Order       map[string]string
+	FirstId     int64
+	LastId      int64
+	PageNum     int
+	PerPage     int
+	TotalPages  int
+	TotalItems  int64
+	orderStr    string
+}
+
+// Current get the current page of ApiKeyPage object for pagination.
+func (_p *ApiKeyPage) Current(ctx context.Context) ([]ApiKey, error) {
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("current")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	api_keys, err := FindApiKeysWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(api_keys) != 0 {
+		_p.FirstId, _p.LastId = api_keys[0].Id, api_keys[len(api_keys)-1].Id
+	}
+	return api_keys, nil
+}
+
+// Previous get the previous page of ApiKeyPage object for pagination.
+func (_p *ApiKeyPage) Previous(ctx context.Context) ([]ApiKey, error) {
+	if _p.PageNum == 0 {
+		return nil, errors.New("This's the first page, no previous page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("previous")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	api_keys, err := FindApiKeysWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(api_keys) != 0 {
+		_p.FirstId, _p.LastId = api_keys[0].Id, api_keys[len(api_keys)-1].Id
+	}
+	_p.PageNum -= 1
+	return api_keys, nil
+}
+
+// Next get the next page of ApiKeyPage object for pagination.
+func (_p *ApiKeyPage) Next(ctx context.Context) ([]ApiKey, error) {
+	if _p.PageNum == _p.TotalPages-1 {
+		return nil, errors.New("This's the last page, no next page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("next")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	api_keys, err := FindApiKeysWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(api_keys) != 0 {
+		_p.FirstId, _p.LastId = api_keys[0].Id, api_keys[len(api_keys)-1].Id
+	}
+	_p.PageNum += 1
+	return api_keys, nil
+}
+
+// GetPage is a helper function for the ApiKeyPage object to return a corresponding page due to
+// the parameter passed in, i.e. one of "previous, current or next".
+func (_p *ApiKeyPage) GetPage(ctx context.Context, direction string) (ps []ApiKey, err error) {
+	switch direction {
+	case "previous":
+		ps, _ = _p.Previous(ctx)
+	case "next":
+		ps, _ = _p.Next(ctx)
+	case "current":
+		ps, _ = _p.Current(ctx)
+	default:
+		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
+	}
+	return
+}
+
+// buildOrder is for ApiKeyPage object to build a SQL ORDER BY clause.
+func (_p *ApiKeyPage) buildOrder() {
+	tempList := []string{}
+	for k, v := range _p.Order {
+		tempList = append(tempList, fmt.Sprintf("%v %v", k, v))
+	}
+	_p.orderStr = " ORDER BY " + strings.Join(tempList, ", ")
+}
+
+// buildIdRestrict is for ApiKeyPage object to build a SQL clause for ID restriction,
+// implementing a simple keyset style pagination.
+func (_p *ApiKeyPage) buildIdRestrict(direction string) (idStr string, idParams []interface{}) {
+	switch direction {
+	case "previous":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.FirstId)
+		} else {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.FirstId)
+		}
+	case "current":
+		// trick to make Where function work
+		if _p.PageNum == 0 && _p.FirstId == 0 && _p.LastId == 0 {
+			idStr += "id > ? "
+			idParams = append(idParams, 0)
+		} else {
+			if strings.ToLower(_p.Order["id"]) == "desc" {
+				idStr += "id <= ? AND id >= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			} else {
+				idStr += "id >= ? AND id <= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			}
+		}
+	case "next":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.LastId)
+		} else {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.LastId)
+		}
+	}
+	if _p.WhereString != "" {
+		idStr = " AND " + idStr
+	}
+	return
+}
+
+// buildPageCount calculate the TotalItems/TotalPages for the ApiKeyPage object.
+func (_p *ApiKeyPage) buildPageCount(ctx context.Context) error {
+	count, err := ApiKeyCountWhere(ctx, _p.WhereString, _p.WhereParams...)
+	if err != nil {
+		return err
+	}
+	_p.TotalItems = count
+	if _p.PerPage == 0 {
+		_p.PerPage = 10
+	}
+	_p.TotalPages = int(math.Ceil(float64(_p.TotalItems) / float64(_p.PerPage)))
+	return nil
+}
+
+
+// FindApiKey find a single api_key by an ID.
+func FindApiKey(ctx context.Context, id int64) (*ApiKey, error) {
+	ctx = observeQuery(ctx, "FindApiKey")
+	if id == 0 {
+		return nil, errors.New("Invalid ID: it can't be zero")
+	}
+	_api_key := ApiKey{}
+	err := DB.GetContext(ctx, &_api_key, DB.Rebind(`SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys WHERE api_keys.id = ? LIMIT 1`), id)
+	if err != nil {
+		logger(ctx).Error("FindApiKey error", "error", err)
+		return nil, err
+	}
+	return &_api_key, nil
+}
+
+// FirstApiKey find the first one api_key by ID ASC order.
+func FirstApiKey(ctx context.Context) (*ApiKey, error) {
+	ctx = observeQuery(ctx, "FirstApiKey")
+	_api_key := ApiKey{}
+	err := DB.GetContext(ctx, &_api_key, DB.Rebind(`SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys ORDER BY api_keys.id ASC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("FirstApiKey error", "error", err)
+		return nil, err
+	}
+	return &_api_key, nil
+}
+
+// FirstApiKeys find the first N api_keys by ID ASC order.
+func FirstApiKeys(ctx context.Context, n uint32) ([]ApiKey, error) {
+	ctx = observeQuery(ctx, "FirstApiKeys")
+	_api_keys := []ApiKey{}
+	sql := fmt.Sprintf("SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys ORDER BY api_keys.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_api_keys, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FirstApiKeys error", "error", err)
+		return nil, err
+	}
+	return _api_keys, nil
+}
+
+// LastApiKey find the last one api_key by ID DESC order.
+func LastApiKey(ctx context.Context) (*ApiKey, error) {
+	ctx = observeQuery(ctx, "LastApiKey")
+	_api_key := ApiKey{}
+	err := DB.GetContext(ctx, &_api_key, DB.Rebind(`SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys ORDER BY api_keys.id DESC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("LastApiKey error", "error", err)
+		return nil, err
+	}
+	return &_api_key, nil
+}
+
+// LastApiKeys find the last N api_keys by ID DESC order.
+func LastApiKeys(ctx context.Context, n uint32) ([]ApiKey, error) {
+	ctx = observeQuery(ctx, "LastApiKeys")
+	_api_keys := []ApiKey{}
+	sql := fmt.Sprintf("SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys ORDER BY api_keys.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_api_keys, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("LastApiKeys error", "error", err)
+		return nil, err
+	}
+	return _api_keys, nil
+}
+
+// FindApiKeys find one or more api_keys by the given ID(s).
+func FindApiKeys(ctx context.Context, ids ...int64) ([]ApiKey, error) {
+	ctx = observeQuery(ctx, "FindApiKeys")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("FindApiKeys error", "error", msg)
+		return nil, errors.New(msg)
+	}
+	_api_keys := []ApiKey{}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys WHERE api_keys.id IN (?%s)`, idsHolder))
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	err := DB.SelectContext(ctx, &_api_keys, sql, idsT...)
+	if err != nil {
+		logger(ctx).Error("FindApiKeys error", "error", err)
+		return nil, err
+	}
+	return _api_keys, nil
+}
+
+// FindApiKeyBy find a single api_key by a field name and a value.
+func FindApiKeyBy(ctx context.Context, field string, val interface{}) (*ApiKey, error) {
+	ctx = observeQuery(ctx, "FindApiKeyBy")
+	_api_key := ApiKey{}
+	sqlFmt := `SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys WHERE %s = ? LIMIT 1`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err := DB.GetContext(ctx, &_api_key, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindApiKeyBy error", "error", err)
+		return nil, err
+	}
+	return &_api_key, nil
+}
+
+// FindApiKeysBy find all api_keys by a field name and a value.
+func FindApiKeysBy(ctx context.Context, field string, val interface{}) (_api_keys []ApiKey, err error) {
+	ctx = observeQuery(ctx, "FindApiKeysBy")
+	sqlFmt := `SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys WHERE %s = ?`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err = DB.SelectContext(ctx, &_api_keys, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindApiKeysBy error", "error", err)
+		return nil, err
+	}
+	return _api_keys, nil
+}
+
+// AllApiKeys get all the ApiKey records.
+func AllApiKeys(ctx context.Context) (api_keys []ApiKey, err error) {
+	ctx = observeQuery(ctx, "AllApiKeys")
+	err = DB.SelectContext(ctx, &api_keys, "SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys")
+	if err != nil {
+		logger(ctx).Error("AllApiKeys error", "error", err)
+		return nil, err
+	}
+	return api_keys, nil
+}
+
+// ApiKeyCount get the count of all the ApiKey records.
+func ApiKeyCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "ApiKeyCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM api_keys")
+	if err != nil {
+		logger(ctx).Error("ApiKeyCount error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ApiKeyCountWhere get the count of all the ApiKey records with a where clause.
+func ApiKeyCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "ApiKeyCountWhere")
+	sql := "SELECT count(*) FROM api_keys"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ApiKeyCountWhere error", "error", err)
+		return 0, err
+	}
+	err = stmt.GetContext(ctx, &c, args...)
+	if err != nil {
+		logger(ctx).Error("ApiKeyCountWhere error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ApiKeyIncludesWhere get the ApiKey associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on ApiKey model.
+func ApiKeyIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_api_keys []ApiKey, err error) {
+	_api_keys, err = FindApiKeysWhere(ctx, sql, args...)
+	if err != nil {
+		logger(ctx).Error("ApiKeyIncludesWhere error", "error", err)
+		return nil, err
+	}
+	if len(assocs) == 0 {
+		logger(ctx).Warn("ApiKeyIncludesWhere without associated fields")
+		return _api_keys, err
+	}
+	if len(_api_keys) <= 0 {
+		return nil, errors.New("No results available")
+	}
+	ids := make([]interface{}, len(_api_keys))
+	for _, v := range _api_keys {
+		ids = append(ids, interface{}(v.Id))
+	}
+	return _api_keys, nil
+}
+
+// ApiKeyIds get all the IDs of ApiKey records.
+func ApiKeyIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "ApiKeyIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM api_keys")
+	if err != nil {
+		logger(ctx).Error("ApiKeyIds error", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ApiKeyIdsWhere get all the IDs of ApiKey records by where restriction.
+func ApiKeyIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := ApiKeyIntCol(ctx, "id", where, args...)
+	return ids, err
+}
+
+// ApiKeyIntCol get some int64 typed column of ApiKey by where restriction.
+func ApiKeyIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "ApiKeyIntCol")
+	sql := "SELECT " + col + " FROM api_keys"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ApiKeyIntCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ApiKeyIntCol error", "error", err)
+		return nil, err
+	}
+	return intColRecs, nil
+}
+
+// ApiKeyStrCol get some string typed column of ApiKey by where restriction.
+func ApiKeyStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "ApiKeyStrCol")
+	sql := "SELECT " + col + " FROM api_keys"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ApiKeyStrCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ApiKeyStrCol error", "error", err)
+		return nil, err
+	}
+	return strColRecs, nil
+}
+
+// FindApiKeysWhere query use a partial SQL clause that usually following after WHERE
+// with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindApiKeysWhere(ctx context.Context, where string, args ...interface{}) (api_keys []ApiKey, err error) {
+	ctx = observeQuery(ctx, "FindApiKeysWhere")
+	sql := "SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindApiKeysWhere error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &api_keys, args...)
+	if err != nil {
+		logger(ctx).Error("FindApiKeysWhere error", "error", err)
+		return nil, err
+	}
+	return api_keys, nil
+}
+
+// EachApiKey iterate over the ApiKey records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachApiKey(ctx, "id > ?", []interface{}{100}, func(api_key ApiKey) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachApiKey(ctx context.Context, where string, args []interface{}, fn func(ApiKey) error) error {
+	ctx = observeQuery(ctx, "EachApiKey")
+	sql := "SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachApiKey error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_api_key := ApiKey{}
+		if err = rows.StructScan(&_api_key); err != nil {
+			logger(ctx).Error("EachApiKey error", "error", err)
+			return err
+		}
+		if err = fn(_api_key); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ApiKeysInBatches iterate over all the ApiKey records in batches of batchSize,
+// see ApiKeysInBatchesWhere.
+func ApiKeysInBatches(ctx context.Context, batchSize int, fn func([]ApiKey) error) error {
+	return ApiKeysInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// ApiKeysInBatchesWhere iterate over the ApiKey records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func ApiKeysInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]ApiKey) error) error {
+	ctx = observeQuery(ctx, "ApiKeysInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT COALESCE(api_keys.user_id, 0) AS user_id, api_keys.id, api_keys.name, api_keys.key_digest, api_keys.key_prefix, api_keys.last_used_at, api_keys.revoked_at, api_keys.created_at, api_keys.updated_at FROM api_keys WHERE %sapi_keys.id > ? ORDER BY api_keys.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("ApiKeysInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_api_keys := []ApiKey{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_api_keys, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("ApiKeysInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_api_keys) == 0 {
+			return nil
+		}
+		lastId = _api_keys[len(_api_keys)-1].Id
+		if err = fn(_api_keys); err != nil {
+			return err
+		}
+		if len(_api_keys) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindApiKeyBySql query use a complete SQL clause
+// with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
+// will return only One record in the table "users" whose first_name is "John" and age elder than 18.
+func FindApiKeyBySql(ctx context.Context, sql string, args ...interface{}) (*ApiKey, error) {
+	ctx = observeQuery(ctx, "FindApiKeyBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindApiKeyBySql error", "error", err)
+		return nil, err
+	}
+	_api_key := &ApiKey{}
+	err = stmt.GetContext(ctx, _api_key, args...)
+	if err != nil {
+		logger(ctx).Error("FindApiKeyBySql error", "error", err)
+		return nil, err
+	}
+	return _api_key, nil
+}
+
+// FindApiKeysBySql query use a complete SQL clause
+// with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindApiKeysBySql(ctx context.Context, sql string, args ...interface{}) (api_keys []ApiKey, err error) {
+	ctx = observeQuery(ctx, "FindApiKeysBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindApiKeysBySql error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &api_keys, args...)
+	if err != nil {
+		logger(ctx).Error("FindApiKeysBySql error", "error", err)
+		return nil, err
+	}
+	return api_keys, nil
+}
+
+// CreateApiKey use a named params to create a single ApiKey record.
+// A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
+func CreateApiKey(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateApiKey")
+	if len(am) == 0 {
+		return 0, fmt.Errorf("Zero key in the attributes map!")
+	}
+	t := time.Now()
+	for _, v := range []string{"created_at", "updated_at"} {
+		if am[v] == nil {
+			am[v] = t
+		}
+	}
+	keys := allKeys(am)
+	sqlFmt := `INSERT INTO api_keys (%s) VALUES (%s)`
+	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
+	result, err := DB.NamedExecContext(ctx, sql, am)
+	if err != nil {
+		logger(ctx).Error("CreateApiKey error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("CreateApiKey error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+// Create is a method for ApiKey to create a record.
+func (_api_key *ApiKey) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "ApiKey.Create")
+	ok, err := govalidator.ValidateStruct(_api_key)
+	if !ok {
+		errMsg := "Validate ApiKey struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ApiKey struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ApiKey.Create error", "error", errMsg)
+		return 0, errors.New(errMsg)
+	}
+	t := time.Now()
+	_api_key.CreatedAt = t
+	_api_key.UpdatedAt = t
+    sql := `INSERT INTO api_keys (name,key_digest,key_prefix,last_used_at,revoked_at,created_at,updated_at,user_id) VALUES (:name,:key_digest,:key_prefix,:last_used_at,:revoked_at,:created_at,:updated_at,NULLIF(:user_id, 0))`
+    result, err := DB.NamedExecContext(ctx, sql, _api_key)
+	if err != nil {
+		logger(ctx).Error("ApiKey.Create error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("ApiKey.Create error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+
+
+// CreateUser is a method for a ApiKey object to create an associated User record.
+func (_api_key *ApiKey) CreateUser(ctx context.Context, am map[string]interface{}) error {
+	am["api_key_id"] = _api_key.Id
+	_, err := CreateUser(ctx, am)
+	return err
+}
+
+
+// Destroy is method used for a ApiKey object to be destroyed.
+func (_api_key *ApiKey) Destroy(ctx context.Context) error {
+	if _api_key.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := DestroyApiKey(ctx, _api_key.Id)
+	return err
+}
+
+// DestroyApiKey will destroy a ApiKey record specified by the id parameter.
+func DestroyApiKey(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyApiKey")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM api_keys WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DestroyApiKeys will destroy ApiKey records those specified by the ids parameters.
+func DestroyApiKeys(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyApiKeys")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("DestroyApiKeys error", "error", msg)
+		return 0, errors.New(msg)
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := fmt.Sprintf(`DELETE FROM api_keys WHERE id IN (?%s)`, idsHolder)
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DestroyApiKeysWhere delete records by a where clause restriction.
+// e.g. DestroyApiKeysWhere("name = ?", "John")
+// And this func will not call the association dependent action
+func DestroyApiKeysWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyApiKeysWhere")
+	sql := `DELETE FROM api_keys WHERE `
+	if len(where) > 0 {
+		sql = sql + where
+	} else {
+		return 0, errors.New("No WHERE conditions provided")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+
+// Save method is used for a ApiKey object to update an existed record mainly.
+// If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
+func (_api_key *ApiKey) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "ApiKey.Save")
+	ok, err := govalidator.ValidateStruct(_api_key)
+	if !ok {
+		errMsg := "Validate ApiKey struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ApiKey struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ApiKey.Save error", "error", errMsg)
+		return errors.New(errMsg)
+	}
+	if _api_key.Id == 0 {
+		_, err = _api_key.Create(ctx)
+		return err
+	}
+	_api_key.UpdatedAt = time.Now()
+	sqlFmt := `UPDATE api_keys SET %s WHERE id = %v`
+	sqlStr := fmt.Sprintf(sqlFmt, "name = :name, key_digest = :key_digest, key_prefix = :key_prefix, last_used_at = :last_used_at, revoked_at = :revoked_at, updated_at = :updated_at, user_id = NULLIF(:user_id, 0)", _api_key.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _api_key)
+    return err
+}
+
+// UpdateApiKey is used to update a record with a id and map[string]interface{} typed key-value parameters.
+func UpdateApiKey(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateApiKey")
+	if len(am) == 0 {
+		return errors.New("Zero key in the attributes map!")
+	}
+	am["updated_at"] = time.Now()
+	keys := allKeys(am)
+	sqlFmt := `UPDATE api_keys SET %s WHERE id = %v`
+	setKeysArr := []string{}
+	for _,v := range keys {
+		s := fmt.Sprintf(" %s = :%s", v, v)
+		setKeysArr = append(setKeysArr, s)
+	}
+	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
+	if err != nil {
+		logger(ctx).Error("UpdateApiKey error", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Update is a method used to update a ApiKey record with the map[string]interface{} typed key-value parameters.
+func (_api_key *ApiKey) Update(ctx context.Context, am map[string]interface{}) error {
+	if _api_key.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateApiKey(ctx, _api_key.Id, am)
+	return err
+}
+
+// UpdateAttributes method is supposed to be used to update ApiKey records as corresponding update_attributes in Ruby on Rails.
+func (_api_key *ApiKey) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
+	if _api_key.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateApiKey(ctx, _api_key.Id, am)
+	return err
+}
+
+// UpdateColumns method is supposed to be used to update ApiKey records as corresponding update_columns in Ruby on Rails.
+func (_api_key *ApiKey) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
+	if _api_key.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateApiKey(ctx, _api_key.Id, am)
+	return err
+}
+
+// UpdateApiKeysBySql is used to update ApiKey records by a SQL clause
+// using the '?' binding syntax.
+func UpdateApiKeysBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateApiKeysBySql")
+	if sql == "" {
+		return 0, errors.New("A blank SQL clause")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
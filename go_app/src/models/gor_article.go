@@ -4,9 +4,9 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"math"
 	"strings"
 	"time"
@@ -14,18 +14,23 @@ import (
 	"github.com/asaskevich/govalidator"
 )
 
-// set flags to output more detailed log
-func init() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-}
-
 type Article struct {
 	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
 Title string `json:"title,omitempty" db:"title" valid:"required,length(10|30)"`
 Text string `json:"text,omitempty" db:"text" valid:"required,length(20|4294967295)"`
 CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
 UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+UserId int64 `json:"user_id,omitempty" db:"user_id" valid:"-"`
+Slug string `json:"slug,omitempty" db:"slug" valid:"-"`
+Status string `json:"status,omitempty" db:"status" valid:"in(draft|scheduled|published|archived)"`
+PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at" valid:"-"`
+TextHtml string `json:"text_html,omitempty" db:"text_html" valid:"-"`
 Comments []Comment `json:"comments,omitempty" db:"comments" valid:"-"`
+ArticleTags []ArticleTag `json:"article_tags,omitempty" db:"article_tags" valid:"-"`
+Tags []Tag `json:"tags,omitempty" db:"tags" valid:"-"`
+ArticleSlugs []ArticleSlug `json:"article_slugs,omitempty" db:"article_slugs" valid:"-"`
+ArticleRevisions []ArticleRevision `json:"article_revisions,omitempty" db:"article_revisions" valid:"-"`
+User User `json:"user,omitempty" db:"user" valid:"-"`
 }
 
 // DataStruct for the pagination
@@ -43,11 +48,11 @@ type ArticlePage struct {
 }
 
 // Current get the current page of ArticlePage object for pagination.
-func (_p *ArticlePage) Current() ([]Article, error) {
+func (_p *ArticlePage) Current(ctx context.Context) ([]Article, error) {
 	if _, exist := _p.Order["id"]; !exist {
 		return nil, errors.New("No id order specified in Order map")
 	}
-	err := _p.buildPageCount()
+	err := _p.buildPageCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Calculate page count error: %v", err)
 	}
@@ -58,7 +63,7 @@ func (_p *ArticlePage) Current() ([]Article, error) {
 	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
 	whereParams := []interface{}{}
 	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
-	articles, err := FindArticlesWhere(whereStr, whereParams...)
+	articles, err := FindArticlesWhere(ctx, whereStr, whereParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,14 +74,14 @@ func (_p *ArticlePage) Current() ([]Article, error) {
 }
 
 // Previous get the previous page of ArticlePage object for pagination.
-func (_p *ArticlePage) Previous() ([]Article, error) {
+func (_p *ArticlePage) Previous(ctx context.Context) ([]Article, error) {
 	if _p.PageNum == 0 {
 		return nil, errors.New("This's the first page, no previous page yet")
 	}
 	if _, exist := _p.Order["id"]; !exist {
 		return nil, errors.New("No id order specified in Order map")
 	}
-	err := _p.buildPageCount()
+	err := _p.buildPageCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Calculate page count error: %v", err)
 	}
@@ -87,7 +92,7 @@ func (_p *ArticlePage) Previous() ([]Article, error) {
 	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
 	whereParams := []interface{}{}
 	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
-	articles, err := FindArticlesWhere(whereStr, whereParams...)
+	articles, err := FindArticlesWhere(ctx, whereStr, whereParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,14 +104,14 @@ func (_p *ArticlePage) Previous() ([]Article, error) {
 }
 
 // Next get the next page of ArticlePage object for pagination.
-func (_p *ArticlePage) Next() ([]Article, error) {
+func (_p *ArticlePage) Next(ctx context.Context) ([]Article, error) {
 	if _p.PageNum == _p.TotalPages-1 {
 		return nil, errors.New("This's the last page, no next page yet")
 	}
 	if _, exist := _p.Order["id"]; !exist {
 		return nil, errors.New("No id order specified in Order map")
 	}
-	err := _p.buildPageCount()
+	err := _p.buildPageCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Calculate page count error: %v", err)
 	}
@@ -117,7 +122,7 @@ func (_p *ArticlePage) Next() ([]Article, error) {
 	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
 	whereParams := []interface{}{}
 	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
-	articles, err := FindArticlesWhere(whereStr, whereParams...)
+	articles, err := FindArticlesWhere(ctx, whereStr, whereParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -130,14 +135,14 @@ func (_p *ArticlePage) Next() ([]Article, error) {
 
 // GetPage is a helper function for the ArticlePage object to return a corresponding page due to
 // the parameter passed in, i.e. one of "previous, current or next".
-func (_p *ArticlePage) GetPage(direction string) (ps []Article, err error) {
+func (_p *ArticlePage) GetPage(ctx context.Context, direction string) (ps []Article, err error) {
 	switch direction {
 	case "previous":
-		ps, _ = _p.Previous()
+		ps, _ = _p.Previous(ctx)
 	case "next":
-		ps, _ = _p.Next()
+		ps, _ = _p.Next(ctx)
 	case "current":
-		ps, _ = _p.Current()
+		ps, _ = _p.Current(ctx)
 	default:
 		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
 	}
@@ -195,8 +200,8 @@ func (_p *ArticlePage) buildIdRestrict(direction string) (idStr string, idParams
 }
 
 // buildPageCount calculate the TotalItems/TotalPages for the ArticlePage object.
-func (_p *ArticlePage) buildPageCount() error {
-	count, err := ArticleCountWhere(_p.WhereString, _p.WhereParams...)
+func (_p *ArticlePage) buildPageCount(ctx context.Context) error {
+	count, err := ArticleCountWhere(ctx, _p.WhereString, _p.WhereParams...)
 	if err != nil {
 		return err
 	}
@@ -210,160 +215,171 @@ func (_p *ArticlePage) buildPageCount() error {
 
 
 // FindArticle find a single article by an ID.
-func FindArticle(id int64) (*Article, error) {
+func FindArticle(ctx context.Context, id int64) (*Article, error) {
+	ctx = observeQuery(ctx, "FindArticle")
 	if id == 0 {
 		return nil, errors.New("Invalid ID: it can't be zero")
 	}
 	_article := Article{}
-	err := DB.Get(&_article, DB.Rebind(`SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles WHERE articles.id = ? LIMIT 1`), id)
+	err := DB.GetContext(ctx, &_article, DB.Rebind(`SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles WHERE articles.id = ? LIMIT 1`), id)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindArticle error", "error", err)
 		return nil, err
 	}
 	return &_article, nil
 }
 
 // FirstArticle find the first one article by ID ASC order.
-func FirstArticle() (*Article, error) {
+func FirstArticle(ctx context.Context) (*Article, error) {
+	ctx = observeQuery(ctx, "FirstArticle")
 	_article := Article{}
-	err := DB.Get(&_article, DB.Rebind(`SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles ORDER BY articles.id ASC LIMIT 1`))
+	err := DB.GetContext(ctx, &_article, DB.Rebind(`SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles ORDER BY articles.id ASC LIMIT 1`))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FirstArticle error", "error", err)
 		return nil, err
 	}
 	return &_article, nil
 }
 
 // FirstArticles find the first N articles by ID ASC order.
-func FirstArticles(n uint32) ([]Article, error) {
+func FirstArticles(ctx context.Context, n uint32) ([]Article, error) {
+	ctx = observeQuery(ctx, "FirstArticles")
 	_articles := []Article{}
-	sql := fmt.Sprintf("SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles ORDER BY articles.id ASC LIMIT %v", n)
-	err := DB.Select(&_articles, DB.Rebind(sql))
+	sql := fmt.Sprintf("SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles ORDER BY articles.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_articles, DB.Rebind(sql))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FirstArticles error", "error", err)
 		return nil, err
 	}
 	return _articles, nil
 }
 
 // LastArticle find the last one article by ID DESC order.
-func LastArticle() (*Article, error) {
+func LastArticle(ctx context.Context) (*Article, error) {
+	ctx = observeQuery(ctx, "LastArticle")
 	_article := Article{}
-	err := DB.Get(&_article, DB.Rebind(`SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles ORDER BY articles.id DESC LIMIT 1`))
+	err := DB.GetContext(ctx, &_article, DB.Rebind(`SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles ORDER BY articles.id DESC LIMIT 1`))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("LastArticle error", "error", err)
 		return nil, err
 	}
 	return &_article, nil
 }
 
 // LastArticles find the last N articles by ID DESC order.
-func LastArticles(n uint32) ([]Article, error) {
+func LastArticles(ctx context.Context, n uint32) ([]Article, error) {
+	ctx = observeQuery(ctx, "LastArticles")
 	_articles := []Article{}
-	sql := fmt.Sprintf("SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles ORDER BY articles.id DESC LIMIT %v", n)
-	err := DB.Select(&_articles, DB.Rebind(sql))
+	sql := fmt.Sprintf("SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles ORDER BY articles.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_articles, DB.Rebind(sql))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("LastArticles error", "error", err)
 		return nil, err
 	}
 	return _articles, nil
 }
 
 // FindArticles find one or more articles by the given ID(s).
-func FindArticles(ids ...int64) ([]Article, error) {
+func FindArticles(ctx context.Context, ids ...int64) ([]Article, error) {
+	ctx = observeQuery(ctx, "FindArticles")
 	if len(ids) == 0 {
 		msg := "At least one or more ids needed"
-		log.Println(msg)
+		logger(ctx).Warn("FindArticles error", "error", msg)
 		return nil, errors.New(msg)
 	}
 	_articles := []Article{}
 	idsHolder := strings.Repeat(",?", len(ids)-1)
-	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles WHERE articles.id IN (?%s)`, idsHolder))
+	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles WHERE articles.id IN (?%s)`, idsHolder))
 	idsT := []interface{}{}
 	for _,id := range ids {
 		idsT = append(idsT, interface{}(id))
 	}
-	err := DB.Select(&_articles, sql, idsT...)
+	err := DB.SelectContext(ctx, &_articles, sql, idsT...)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindArticles error", "error", err)
 		return nil, err
 	}
 	return _articles, nil
 }
 
 // FindArticleBy find a single article by a field name and a value.
-func FindArticleBy(field string, val interface{}) (*Article, error) {
+func FindArticleBy(ctx context.Context, field string, val interface{}) (*Article, error) {
+	ctx = observeQuery(ctx, "FindArticleBy")
 	_article := Article{}
-	sqlFmt := `SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles WHERE %s = ? LIMIT 1`
+	sqlFmt := `SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles WHERE %s = ? LIMIT 1`
 	sqlStr := fmt.Sprintf(sqlFmt, field)
-	err := DB.Get(&_article, DB.Rebind(sqlStr), val)
+	err := DB.GetContext(ctx, &_article, DB.Rebind(sqlStr), val)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindArticleBy error", "error", err)
 		return nil, err
 	}
 	return &_article, nil
 }
 
 // FindArticlesBy find all articles by a field name and a value.
-func FindArticlesBy(field string, val interface{}) (_articles []Article, err error) {
-	sqlFmt := `SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles WHERE %s = ?`
+func FindArticlesBy(ctx context.Context, field string, val interface{}) (_articles []Article, err error) {
+	ctx = observeQuery(ctx, "FindArticlesBy")
+	sqlFmt := `SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles WHERE %s = ?`
 	sqlStr := fmt.Sprintf(sqlFmt, field)
-	err = DB.Select(&_articles, DB.Rebind(sqlStr), val)
+	err = DB.SelectContext(ctx, &_articles, DB.Rebind(sqlStr), val)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindArticlesBy error", "error", err)
 		return nil, err
 	}
 	return _articles, nil
 }
 
 // AllArticles get all the Article records.
-func AllArticles() (articles []Article, err error) {
-	err = DB.Select(&articles, "SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles")
+func AllArticles(ctx context.Context) (articles []Article, err error) {
+	ctx = observeQuery(ctx, "AllArticles")
+	err = DB.SelectContext(ctx, &articles, "SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles")
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("AllArticles error", "error", err)
 		return nil, err
 	}
 	return articles, nil
 }
 
 // ArticleCount get the count of all the Article records.
-func ArticleCount() (c int64, err error) {
-	err = DB.Get(&c, "SELECT count(*) FROM articles")
+func ArticleCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM articles")
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleCount error", "error", err)
 		return 0, err
 	}
 	return c, nil
 }
 
 // ArticleCountWhere get the count of all the Article records with a where clause.
-func ArticleCountWhere(where string, args ...interface{}) (c int64, err error) {
+func ArticleCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleCountWhere")
 	sql := "SELECT count(*) FROM articles"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleCountWhere error", "error", err)
 		return 0, err
 	}
-	err = stmt.Get(&c, args...)
+	err = stmt.GetContext(ctx, &c, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleCountWhere error", "error", err)
 		return 0, err
 	}
 	return c, nil
 }
 
 // ArticleIncludesWhere get the Article associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on Article model.
-func ArticleIncludesWhere(assocs []string, sql string, args ...interface{}) (_articles []Article, err error) {
-	_articles, err = FindArticlesWhere(sql, args...)
+func ArticleIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_articles []Article, err error) {
+	_articles, err = FindArticlesWhere(ctx, sql, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleIncludesWhere error", "error", err)
 		return nil, err
 	}
 	if len(assocs) == 0 {
-		log.Println("No associated fields ard specified")
+		logger(ctx).Warn("ArticleIncludesWhere without associated fields")
 		return _articles, err
 	}
 	if len(_articles) <= 0 {
@@ -378,9 +394,9 @@ func ArticleIncludesWhere(assocs []string, sql string, args ...interface{}) (_ar
 		switch assoc {
 				case "comments":
 							where := fmt.Sprintf("article_id IN (?%s)", idsHolder)
-						_comments, err := FindCommentsWhere(where, ids...)
+						_comments, err := FindCommentsWhere(ctx, where, ids...)
 						if err != nil {
-							log.Printf("Error when query associated objects: %v\n", assoc)
+							logger(ctx).Error("ArticleIncludesWhere associated objects error", "assoc", assoc, "error", err)
 							continue
 						}
 						for _, vv := range _comments {
@@ -391,60 +407,135 @@ func ArticleIncludesWhere(assocs []string, sql string, args ...interface{}) (_ar
 								_articles[i].Comments = vvv.Comments
 						    }
 					    }
+				case "article_tags":
+							where := fmt.Sprintf("article_id IN (?%s)", idsHolder)
+						_article_tags, err := FindArticleTagsWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("ArticleIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _article_tags {
+							for i, vvv := range  _articles {
+									if vv.ArticleId == vvv.Id {
+										vvv.ArticleTags = append(vvv.ArticleTags, vv)
+									}
+								_articles[i].ArticleTags = vvv.ArticleTags
+						    }
+					    }
+				case "article_slugs":
+							where := fmt.Sprintf("article_id IN (?%s)", idsHolder)
+						_article_slugs, err := FindArticleSlugsWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("ArticleIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _article_slugs {
+							for i, vvv := range  _articles {
+									if vv.ArticleId == vvv.Id {
+										vvv.ArticleSlugs = append(vvv.ArticleSlugs, vv)
+									}
+								_articles[i].ArticleSlugs = vvv.ArticleSlugs
+						    }
+					    }
+				case "article_revisions":
+							where := fmt.Sprintf("article_id IN (?%s)", idsHolder)
+						_article_revisions, err := FindArticleRevisionsWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("ArticleIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _article_revisions {
+							for i, vvv := range  _articles {
+									if vv.ArticleId == vvv.Id {
+										vvv.ArticleRevisions = append(vvv.ArticleRevisions, vv)
+									}
+								_articles[i].ArticleRevisions = vvv.ArticleRevisions
+						    }
+					    }
+				case "tags":
+							where := fmt.Sprintf("article_id IN (?%s)", idsHolder)
+						_article_tags, err := FindArticleTagsWhere(ctx, where, ids...)
+						if err != nil || len(_article_tags) == 0 {
+							continue
+						}
+						tagIds := []int64{}
+						for _, vv := range _article_tags {
+							tagIds = append(tagIds, vv.TagId)
+						}
+						_tags, err := FindTags(ctx, tagIds...)
+						if err != nil {
+							logger(ctx).Error("ArticleIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _article_tags {
+							for i, vvv := range  _articles {
+									if vv.ArticleId != vvv.Id {
+										continue
+									}
+									for _, tag := range _tags {
+										if tag.Id == vv.TagId {
+											_articles[i].Tags = append(_articles[i].Tags, tag)
+										}
+									}
+						    }
+					    }
 		}
 	}
 	return _articles, nil
 }
 
 // ArticleIds get all the IDs of Article records.
-func ArticleIds() (ids []int64, err error) {
-	err = DB.Select(&ids, "SELECT id FROM articles")
+func ArticleIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM articles")
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleIds error", "error", err)
 		return nil, err
 	}
 	return ids, nil
 }
 
 // ArticleIdsWhere get all the IDs of Article records by where restriction.
-func ArticleIdsWhere(where string, args ...interface{}) ([]int64, error) {
-	ids, err := ArticleIntCol("id", where, args...)
+func ArticleIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := ArticleIntCol(ctx, "id", where, args...)
 	return ids, err
 }
 
 // ArticleIntCol get some int64 typed column of Article by where restriction.
-func ArticleIntCol(col, where string, args ...interface{}) (intColRecs []int64, err error) {
+func ArticleIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleIntCol")
 	sql := "SELECT " + col + " FROM articles"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleIntCol error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&intColRecs, args...)
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleIntCol error", "error", err)
 		return nil, err
 	}
 	return intColRecs, nil
 }
 
 // ArticleStrCol get some string typed column of Article by where restriction.
-func ArticleStrCol(col, where string, args ...interface{}) (strColRecs []string, err error) {
+func ArticleStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "ArticleStrCol")
 	sql := "SELECT " + col + " FROM articles"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleStrCol error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&strColRecs, args...)
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("ArticleStrCol error", "error", err)
 		return nil, err
 	}
 	return strColRecs, nil
@@ -453,37 +544,118 @@ func ArticleStrCol(col, where string, args ...interface{}) (strColRecs []string,
 // FindArticlesWhere query use a partial SQL clause that usually following after WHERE
 // with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
 // will return those records in the table "users" whose first_name is "John" and age elder than 18.
-func FindArticlesWhere(where string, args ...interface{}) (articles []Article, err error) {
-	sql := "SELECT COALESCE(articles.text, '') AS text, articles.id, articles.title, articles.created_at, articles.updated_at FROM articles"
+func FindArticlesWhere(ctx context.Context, where string, args ...interface{}) (articles []Article, err error) {
+	ctx = observeQuery(ctx, "FindArticlesWhere")
+	sql := "SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindArticlesWhere error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&articles, args...)
+	err = stmt.SelectContext(ctx, &articles, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindArticlesWhere error", "error", err)
 		return nil, err
 	}
 	return articles, nil
 }
 
+// EachArticle iterate over the Article records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachArticle(ctx, "id > ?", []interface{}{100}, func(article Article) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachArticle(ctx context.Context, where string, args []interface{}, fn func(Article) error) error {
+	ctx = observeQuery(ctx, "EachArticle")
+	sql := "SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachArticle error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_article := Article{}
+		if err = rows.StructScan(&_article); err != nil {
+			logger(ctx).Error("EachArticle error", "error", err)
+			return err
+		}
+		if err = fn(_article); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ArticlesInBatches iterate over all the Article records in batches of batchSize,
+// see ArticlesInBatchesWhere.
+func ArticlesInBatches(ctx context.Context, batchSize int, fn func([]Article) error) error {
+	return ArticlesInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// ArticlesInBatchesWhere iterate over the Article records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func ArticlesInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]Article) error) error {
+	ctx = observeQuery(ctx, "ArticlesInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT COALESCE(articles.text, '') AS text, COALESCE(articles.user_id, 0) AS user_id, articles.id, articles.title, articles.created_at, articles.updated_at, articles.slug, articles.status, articles.published_at, COALESCE(articles.text_html, '') AS text_html FROM articles WHERE %sarticles.id > ? ORDER BY articles.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("ArticlesInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_articles := []Article{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_articles, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("ArticlesInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_articles) == 0 {
+			return nil
+		}
+		lastId = _articles[len(_articles)-1].Id
+		if err = fn(_articles); err != nil {
+			return err
+		}
+		if len(_articles) < batchSize {
+			return nil
+		}
+	}
+}
+
 // FindArticleBySql query use a complete SQL clause
 // with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
 // will return only One record in the table "users" whose first_name is "John" and age elder than 18.
-func FindArticleBySql(sql string, args ...interface{}) (*Article, error) {
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+func FindArticleBySql(ctx context.Context, sql string, args ...interface{}) (*Article, error) {
+	ctx = observeQuery(ctx, "FindArticleBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindArticleBySql error", "error", err)
 		return nil, err
 	}
 	_article := &Article{}
-	err = stmt.Get(_article, args...)
+	err = stmt.GetContext(ctx, _article, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindArticleBySql error", "error", err)
 		return nil, err
 	}
 	return _article, nil
@@ -492,15 +664,16 @@ func FindArticleBySql(sql string, args ...interface{}) (*Article, error) {
 // FindArticlesBySql query use a complete SQL clause
 // with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
 // will return those records in the table "users" whose first_name is "John" and age elder than 18.
-func FindArticlesBySql(sql string, args ...interface{}) (articles []Article, err error) {
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+func FindArticlesBySql(ctx context.Context, sql string, args ...interface{}) (articles []Article, err error) {
+	ctx = observeQuery(ctx, "FindArticlesBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindArticlesBySql error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&articles, args...)
+	err = stmt.SelectContext(ctx, &articles, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindArticlesBySql error", "error", err)
 		return nil, err
 	}
 	return articles, nil
@@ -508,7 +681,8 @@ func FindArticlesBySql(sql string, args ...interface{}) (articles []Article, err
 
 // CreateArticle use a named params to create a single Article record.
 // A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
-func CreateArticle(am map[string]interface{}) (int64, error) {
+func CreateArticle(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateArticle")
 	if len(am) == 0 {
 		return 0, fmt.Errorf("Zero key in the attributes map!")
 	}
@@ -518,62 +692,86 @@ func CreateArticle(am map[string]interface{}) (int64, error) {
 			am[v] = t
 		}
 	}
+	if title, ok := am["title"].(string); ok && am["slug"] == nil {
+		slug, err := UniqueArticleSlug(ctx, title, 0)
+		if err != nil {
+			return 0, err
+		}
+		am["slug"] = slug
+	}
 	keys := allKeys(am)
 	sqlFmt := `INSERT INTO articles (%s) VALUES (%s)`
 	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
-	result, err := DB.NamedExec(sql, am)
+	result, err := DB.NamedExecContext(ctx, sql, am)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CreateArticle error", "error", err)
 		return 0, err
 	}
 	lastId, err := result.LastInsertId()
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CreateArticle error", "error", err)
 		return 0, err
 	}
+	afterWrite(ctx, "articles", Created, nil, lastId)
 	return lastId, nil
 }
 
 // Create is a method for Article to create a record.
-func (_article *Article) Create() (int64, error) {
+func (_article *Article) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "Article.Create")
 	ok, err := govalidator.ValidateStruct(_article)
 	if !ok {
 		errMsg := "Validate Article struct error: Unknown error"
 		if err != nil {
 			errMsg = "Validate Article struct error: " + err.Error()
 		}
-		log.Println(errMsg)
+		logger(ctx).Warn("Article.Create error", "error", errMsg)
 		return 0, errors.New(errMsg)
 	}
 	t := time.Now()
 	_article.CreatedAt = t
 	_article.UpdatedAt = t
-    sql := `INSERT INTO articles (title,text,created_at,updated_at) VALUES (:title,:text,:created_at,:updated_at)`
-    result, err := DB.NamedExec(sql, _article)
+	base := _article.Slug
+	if base == "" {
+		base = _article.Title
+	}
+	if _article.Slug, err = UniqueArticleSlug(ctx, base, 0); err != nil {
+		return 0, err
+	}
+    sql := `INSERT INTO articles (title,text,created_at,updated_at,user_id,slug,status,published_at) VALUES (:title,:text,:created_at,:updated_at,NULLIF(:user_id, 0),:slug,COALESCE(NULLIF(:status, ''), 'draft'),:published_at)`
+    result, err := DB.NamedExecContext(ctx, sql, _article)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("Article.Create error", "error", err)
 		return 0, err
 	}
 	lastId, err := result.LastInsertId()
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("Article.Create error", "error", err)
 		return 0, err
 	}
+	afterWrite(ctx, "articles", Created, nil, lastId)
 	return lastId, nil
 }
 
+// CreateUser is a method for a Article object to create an associated User record.
+func (_article *Article) CreateUser(ctx context.Context, am map[string]interface{}) error {
+	am["article_id"] = _article.Id
+	_, err := CreateUser(ctx, am)
+	return err
+}
+
 // CommentsCreate is used for Article to create the associated objects Comments
-func (_article *Article) CommentsCreate(am map[string]interface{}) error {
+func (_article *Article) CommentsCreate(ctx context.Context, am map[string]interface{}) error {
 			am["article_id"] = _article.Id
-		_, err := CreateComment(am)
+		_, err := CreateComment(ctx, am)
 	return err
 }
 
 // GetComments is used for Article to get associated objects Comments
 // Say you have a Article object named article, when you call article.GetComments(),
 // the object will get the associated Comments attributes evaluated in the struct.
-func (_article *Article) GetComments() error {
-	_comments, err := ArticleGetComments(_article.Id)
+func (_article *Article) GetComments(ctx context.Context) error {
+	_comments, err := ArticleGetComments(ctx, _article.Id)
 	if err == nil {
 		_article.Comments = _comments
     }
@@ -581,57 +779,152 @@ func (_article *Article) GetComments() error {
 }
 
 // ArticleGetComments a helper fuction used to get associated objects for ArticleIncludesWhere().
-func ArticleGetComments(id int64) ([]Comment, error) {
-			_comments, err := FindCommentsBy("article_id", id)
+func ArticleGetComments(ctx context.Context, id int64) ([]Comment, error) {
+			_comments, err := FindCommentsBy(ctx, "article_id", id)
 	return _comments, err
 }
 
+// ArticleTagsCreate is used for Article to create the associated objects ArticleTags
+func (_article *Article) ArticleTagsCreate(ctx context.Context, am map[string]interface{}) error {
+			am["article_id"] = _article.Id
+		_, err := CreateArticleTag(ctx, am)
+	return err
+}
+
+// GetArticleTags is used for Article to get associated objects ArticleTags
+// Say you have a Article object named article, when you call article.GetArticleTags(),
+// the object will get the associated ArticleTags attributes evaluated in the struct.
+func (_article *Article) GetArticleTags(ctx context.Context) error {
+	_article_tags, err := ArticleGetArticleTags(ctx, _article.Id)
+	if err == nil {
+		_article.ArticleTags = _article_tags
+    }
+    return err
+}
+
+// ArticleGetArticleTags a helper fuction used to get associated objects for ArticleIncludesWhere().
+func ArticleGetArticleTags(ctx context.Context, id int64) ([]ArticleTag, error) {
+			_article_tags, err := FindArticleTagsBy(ctx, "article_id", id)
+	return _article_tags, err
+}
+
+// ArticleSlugsCreate is used for Article to create the associated objects ArticleSlugs
+func (_article *Article) ArticleSlugsCreate(ctx context.Context, am map[string]interface{}) error {
+			am["article_id"] = _article.Id
+		_, err := CreateArticleSlug(ctx, am)
+	return err
+}
+
+// GetArticleSlugs is used for Article to get associated objects ArticleSlugs
+// Say you have a Article object named article, when you call article.GetArticleSlugs(),
+// the object will get the associated ArticleSlugs attributes evaluated in the struct.
+func (_article *Article) GetArticleSlugs(ctx context.Context) error {
+	_article_slugs, err := ArticleGetArticleSlugs(ctx, _article.Id)
+	if err == nil {
+		_article.ArticleSlugs = _article_slugs
+    }
+    return err
+}
+
+// ArticleGetArticleSlugs a helper fuction used to get associated objects for ArticleIncludesWhere().
+func ArticleGetArticleSlugs(ctx context.Context, id int64) ([]ArticleSlug, error) {
+			_article_slugs, err := FindArticleSlugsBy(ctx, "article_id", id)
+	return _article_slugs, err
+}
+
+// ArticleRevisionsCreate is used for Article to create the associated objects ArticleRevisions
+func (_article *Article) ArticleRevisionsCreate(ctx context.Context, am map[string]interface{}) error {
+			am["article_id"] = _article.Id
+		_, err := CreateArticleRevision(ctx, am)
+	return err
+}
+
+// GetArticleRevisions is used for Article to get associated objects ArticleRevisions
+// Say you have a Article object named article, when you call article.GetArticleRevisions(),
+// the object will get the associated ArticleRevisions attributes evaluated in the struct.
+func (_article *Article) GetArticleRevisions(ctx context.Context) error {
+	_article_revisions, err := ArticleGetArticleRevisions(ctx, _article.Id)
+	if err == nil {
+		_article.ArticleRevisions = _article_revisions
+    }
+    return err
+}
+
+// ArticleGetArticleRevisions a helper fuction used to get associated objects for ArticleIncludesWhere().
+func ArticleGetArticleRevisions(ctx context.Context, id int64) ([]ArticleRevision, error) {
+			_article_revisions, err := FindArticleRevisionsBy(ctx, "article_id", id)
+	return _article_revisions, err
+}
+
+// GetTags is used for Article to get associated objects Tags through ArticleTags
+// Say you have a Article object named article, when you call article.GetTags(),
+// the object will get the associated Tags attributes evaluated in the struct.
+func (_article *Article) GetTags(ctx context.Context) error {
+	_tags, err := ArticleGetTags(ctx, _article.Id)
+	if err == nil {
+		_article.Tags = _tags
+    }
+    return err
+}
+
+// ArticleGetTags a helper fuction used to get associated objects for ArticleIncludesWhere().
+func ArticleGetTags(ctx context.Context, id int64) ([]Tag, error) {
+			_tags, err := FindTagsWhere(ctx, "id IN (SELECT tag_id FROM article_tags WHERE article_id = ?) ORDER BY name", id)
+	return _tags, err
+}
+
 
 
 
 // Destroy is method used for a Article object to be destroyed.
-func (_article *Article) Destroy() error {
+func (_article *Article) Destroy(ctx context.Context) error {
 	if _article.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := DestroyArticle(_article.Id)
+	err := DestroyArticle(ctx, _article.Id)
 	return err
 }
 
 // DestroyArticle will destroy a Article record specified by the id parameter.
-func DestroyArticle(id int64) error {
+func DestroyArticle(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyArticle")
+	before := beforeWrite(ctx, "articles", id)
 	// Destroy association objects at first
 	// Not care if exec properly temporarily
-	destroyArticleAssociations(id)
-	stmt, err := DB.Preparex(DB.Rebind(`DELETE FROM articles WHERE id = ?`))
-	_, err = stmt.Exec(id)
+	destroyArticleAssociations(ctx, id)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM articles WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
 	if err != nil {
 		return err
 	}
+	afterWrite(ctx, "articles", Destroyed, before, id)
 	return nil
 }
 
 // DestroyArticles will destroy Article records those specified by the ids parameters.
-func DestroyArticles(ids ...int64) (int64, error) {
+func DestroyArticles(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticles")
 	if len(ids) == 0 {
 		msg := "At least one or more ids needed"
-		log.Println(msg)
+		logger(ctx).Warn("DestroyArticles error", "error", msg)
 		return 0, errors.New(msg)
 	}
 	// Destroy association objects at first
 	// Not care if exec properly temporarily
-	destroyArticleAssociations(ids...)
+	destroyArticleAssociations(ctx, ids...)
 	idsHolder := strings.Repeat(",?", len(ids)-1)
 	sql := fmt.Sprintf(`DELETE FROM articles WHERE id IN (?%s)`, idsHolder)
 	idsT := []interface{}{}
 	for _,id := range ids {
 		idsT = append(idsT, interface{}(id))
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
-	result, err := stmt.Exec(idsT...)
+	before := beforeWrite(ctx, "articles", ids...)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
 	if err != nil {
 		return 0, err
 	}
+	afterWrite(ctx, "articles", Destroyed, before, ids...)
 	cnt, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
@@ -642,24 +935,27 @@ func DestroyArticles(ids ...int64) (int64, error) {
 // DestroyArticlesWhere delete records by a where clause restriction.
 // e.g. DestroyArticlesWhere("name = ?", "John")
 // And this func will not call the association dependent action
-func DestroyArticlesWhere(where string, args ...interface{}) (int64, error) {
+func DestroyArticlesWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticlesWhere")
 	sql := `DELETE FROM articles WHERE `
 	if len(where) > 0 {
 		sql = sql + where
 	} else {
 		return 0, errors.New("No WHERE conditions provided")
 	}
-	ids, x_err := ArticleIdsWhere(where, args...)
+	ids, x_err := ArticleIdsWhere(ctx, where, args...)
 	if x_err != nil {
-		log.Printf("Delete associated objects error: %v\n", x_err)
+		logger(ctx).Error("DestroyArticlesWhere associated objects error", "error", x_err)
 	} else {
-		destroyArticleAssociations(ids...)
+		destroyArticleAssociations(ctx, ids...)
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
-	result, err := stmt.Exec(args...)
+	before := beforeWrite(ctx, "articles", ids...)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return 0, err
 	}
+	afterWrite(ctx, "articles", Destroyed, before, ids...)
 	cnt, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
@@ -669,7 +965,7 @@ func DestroyArticlesWhere(where string, args ...interface{}) (int64, error) {
 
 // destroyArticleAssociations is a private function used to destroy a Article record's associated objects.
 // The func not return err temporarily.
-func destroyArticleAssociations(ids ...int64) {
+func destroyArticleAssociations(ctx context.Context, ids ...int64) {
 	idsHolder := ""
 	if len(ids) > 1 {
 		idsHolder = strings.Repeat(",?", len(ids)-1)
@@ -682,41 +978,75 @@ func destroyArticleAssociations(ids ...int64) {
 	// make sure no declared-and-not-used exception
 	_, _, _ = idsHolder, idsT, err
 								where := fmt.Sprintf("article_id IN (?%s)", idsHolder)
-							_, err = DestroyCommentsWhere(where, idsT...)
+							_, err = DestroyCommentsWhere(ctx, where, idsT...)
+							if err != nil {
+								logger(ctx).Error("destroyArticleAssociations associated objects error", "assoc", "Comments", "error", err)
+							}
+								where = fmt.Sprintf("article_id IN (?%s)", idsHolder)
+							_, err = DestroyArticleTagsWhere(ctx, where, idsT...)
+							if err != nil {
+								logger(ctx).Error("destroyArticleAssociations associated objects error", "assoc", "ArticleTags", "error", err)
+							}
+								where = fmt.Sprintf("article_id IN (?%s)", idsHolder)
+							_, err = DestroyArticleSlugsWhere(ctx, where, idsT...)
+							if err != nil {
+								logger(ctx).Error("destroyArticleAssociations associated objects error", "assoc", "ArticleSlugs", "error", err)
+							}
+								where = fmt.Sprintf("article_id IN (?%s)", idsHolder)
+							_, err = DestroyArticleRevisionsWhere(ctx, where, idsT...)
 							if err != nil {
-								log.Printf("Destroy associated object %s error: %v\n", "Comments", err)
+								logger(ctx).Error("destroyArticleAssociations associated objects error", "assoc", "ArticleRevisions", "error", err)
 							}
 }
 
 // Save method is used for a Article object to update an existed record mainly.
 // If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
-func (_article *Article) Save() error {
+func (_article *Article) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "Article.Save")
 	ok, err := govalidator.ValidateStruct(_article)
 	if !ok {
 		errMsg := "Validate Article struct error: Unknown error"
 		if err != nil {
 			errMsg = "Validate Article struct error: " + err.Error()
 		}
-		log.Println(errMsg)
+		logger(ctx).Warn("Article.Save error", "error", errMsg)
 		return errors.New(errMsg)
 	}
 	if _article.Id == 0 {
-		_, err = _article.Create()
+		_, err = _article.Create(ctx)
 		return err
 	}
 	_article.UpdatedAt = time.Now()
+	if _article.Slug, err = articleSlugFor(ctx, _article.Id, _article.Title); err != nil {
+		return err
+	}
 	sqlFmt := `UPDATE articles SET %s WHERE id = %v`
-	sqlStr := fmt.Sprintf(sqlFmt, "title = :title, text = :text, updated_at = :updated_at", _article.Id)
-    _, err = DB.NamedExec(sqlStr, _article)
+	sqlStr := fmt.Sprintf(sqlFmt, "text_html = CASE WHEN text = :text THEN text_html END, title = :title, text = :text, updated_at = :updated_at, user_id = NULLIF(:user_id, 0), slug = :slug, status = COALESCE(NULLIF(:status, ''), status), published_at = :published_at", _article.Id)
+	before := beforeWrite(ctx, "articles", _article.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _article)
+    if err == nil {
+		afterWrite(ctx, "articles", Updated, before, _article.Id)
+	}
     return err
 }
 
 // UpdateArticle is used to update a record with a id and map[string]interface{} typed key-value parameters.
-func UpdateArticle(id int64, am map[string]interface{}) error {
+func UpdateArticle(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateArticle")
 	if len(am) == 0 {
 		return errors.New("Zero key in the attributes map!")
 	}
 	am["updated_at"] = time.Now()
+	if title, ok := am["title"].(string); ok && am["slug"] == nil {
+		slug, err := articleSlugFor(ctx, id, title)
+		if err != nil {
+			return err
+		}
+		am["slug"] = slug
+	}
+	if _, ok := am["text"]; ok {
+		am["text_html"] = nil
+	}
 	keys := allKeys(am)
 	sqlFmt := `UPDATE articles SET %s WHERE id = %v`
 	setKeysArr := []string{}
@@ -725,49 +1055,52 @@ func UpdateArticle(id int64, am map[string]interface{}) error {
 		setKeysArr = append(setKeysArr, s)
 	}
 	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
-	_, err := DB.NamedExec(sqlStr, am)
+	before := beforeWrite(ctx, "articles", id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("UpdateArticle error", "error", err)
 		return err
 	}
+	afterWrite(ctx, "articles", Updated, before, id)
 	return nil
 }
 
 // Update is a method used to update a Article record with the map[string]interface{} typed key-value parameters.
-func (_article *Article) Update(am map[string]interface{}) error {
+func (_article *Article) Update(ctx context.Context, am map[string]interface{}) error {
 	if _article.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := UpdateArticle(_article.Id, am)
+	err := UpdateArticle(ctx, _article.Id, am)
 	return err
 }
 
 // UpdateAttributes method is supposed to be used to update Article records as corresponding update_attributes in Ruby on Rails.
-func (_article *Article) UpdateAttributes(am map[string]interface{}) error {
+func (_article *Article) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
 	if _article.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := UpdateArticle(_article.Id, am)
+	err := UpdateArticle(ctx, _article.Id, am)
 	return err
 }
 
 // UpdateColumns method is supposed to be used to update Article records as corresponding update_columns in Ruby on Rails.
-func (_article *Article) UpdateColumns(am map[string]interface{}) error {
+func (_article *Article) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
 	if _article.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := UpdateArticle(_article.Id, am)
+	err := UpdateArticle(ctx, _article.Id, am)
 	return err
 }
 
 // UpdateArticlesBySql is used to update Article records by a SQL clause
 // using the '?' binding syntax.
-func UpdateArticlesBySql(sql string, args ...interface{}) (int64, error) {
+func UpdateArticlesBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateArticlesBySql")
 	if sql == "" {
 		return 0, errors.New("A blank SQL clause")
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
-	result, err := stmt.Exec(args...)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,820 @@
+
+
+// Package models includes the functions on the model ArticleRevision.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type ArticleRevision struct {
+	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
+ArticleId int64 `json:"article_id,omitempty" db:"article_id" valid:"required"`
+Rev int64 `json:"rev,omitempty" db:"rev" valid:"required"`
+Title string `json:"title,omitempty" db:"title" valid:"required"`
+Text string `json:"text,omitempty" db:"text" valid:"-"`
+CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
+UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+UserId int64 `json:"user_id,omitempty" db:"user_id" valid:"-"`
+Article Article `json:"article,omitempty" db:"article" valid:"-"`
+User User `json:"user,omitempty" db:"user" valid:"-"`
+}
+
+// DataStruct for the pagination
+type ArticleRevisionPage struct {
+	WhereString string
+	WhereParams []interface{}
+	Order       map[string]string
+	FirstId     int64
+	LastId      int64
+	PageNum     int
+	PerPage     int
+	TotalPages  int
+	TotalItems  int64
+	orderStr    string
+}
+
+// Current get the current page of ArticleRevisionPage object for pagination.
+func (_p *ArticleRevisionPage) Current(ctx context.Context) ([]ArticleRevision, error) {
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("current")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_revisions, err := FindArticleRevisionsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_revisions) != 0 {
+		_p.FirstId, _p.LastId = article_revisions[0].Id, article_revisions[len(article_revisions)-1].Id
+	}
+	return article_revisions, nil
+}
+
+// Previous get the previous page of ArticleRevisionPage object for pagination.
+func (_p *ArticleRevisionPage) Previous(ctx context.Context) ([]ArticleRevision, error) {
+	if _p.PageNum == 0 {
+		return nil, errors.New("This's the first page, no previous page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("previous")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_revisions, err := FindArticleRevisionsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_revisions) != 0 {
+		_p.FirstId, _p.LastId = article_revisions[0].Id, article_revisions[len(article_revisions)-1].Id
+	}
+	_p.PageNum -= 1
+	return article_revisions, nil
+}
+
+// Next get the next page of ArticleRevisionPage object for pagination.
+func (_p *ArticleRevisionPage) Next(ctx context.Context) ([]ArticleRevision, error) {
+	if _p.PageNum == _p.TotalPages-1 {
+		return nil, errors.New("This's the last page, no next page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("next")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_revisions, err := FindArticleRevisionsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_revisions) != 0 {
+		_p.FirstId, _p.LastId = article_revisions[0].Id, article_revisions[len(article_revisions)-1].Id
+	}
+	_p.PageNum += 1
+	return article_revisions, nil
+}
+
+// GetPage is a helper function for the ArticleRevisionPage object to return a corresponding page due to
+// the parameter passed in, i.e. one of "previous, current or next".
+func (_p *ArticleRevisionPage) GetPage(ctx context.Context, direction string) (ps []ArticleRevision, err error) {
+	switch direction {
+	case "previous":
+		ps, _ = _p.Previous(ctx)
+	case "next":
+		ps, _ = _p.Next(ctx)
+	case "current":
+		ps, _ = _p.Current(ctx)
+	default:
+		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
+	}
+	return
+}
+
+// buildOrder is for ArticleRevisionPage object to build a SQL ORDER BY clause.
+func (_p *ArticleRevisionPage) buildOrder() {
+	tempList := []string{}
+	for k, v := range _p.Order {
+		tempList = append(tempList, fmt.Sprintf("%v %v", k, v))
+	}
+	_p.orderStr = " ORDER BY " + strings.Join(tempList, ", ")
+}
+
+// buildIdRestrict is for ArticleRevisionPage object to build a SQL clause for ID restriction,
+// implementing a simple keyset style pagination.
+func (_p *ArticleRevisionPage) buildIdRestrict(direction string) (idStr string, idParams []interface{}) {
+	switch direction {
+	case "previous":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.FirstId)
+		} else {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.FirstId)
+		}
+	case "current":
+		// trick to make Where function work
+		if _p.PageNum == 0 && _p.FirstId == 0 && _p.LastId == 0 {
+			idStr += "id > ? "
+			idParams = append(idParams, 0)
+		} else {
+			if strings.ToLower(_p.Order["id"]) == "desc" {
+				idStr += "id <= ? AND id >= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			} else {
+				idStr += "id >= ? AND id <= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			}
+		}
+	case "next":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.LastId)
+		} else {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.LastId)
+		}
+	}
+	if _p.WhereString != "" {
+		idStr = " AND " + idStr
+	}
+	return
+}
+
+// buildPageCount calculate the TotalItems/TotalPages for the ArticleRevisionPage object.
+func (_p *ArticleRevisionPage) buildPageCount(ctx context.Context) error {
+	count, err := ArticleRevisionCountWhere(ctx, _p.WhereString, _p.WhereParams...)
+	if err != nil {
+		return err
+	}
+	_p.TotalItems = count
+	if _p.PerPage == 0 {
+		_p.PerPage = 10
+	}
+	_p.TotalPages = int(math.Ceil(float64(_p.TotalItems) / float64(_p.PerPage)))
+	return nil
+}
+
+
+// FindArticleRevision find a single article_revision by an ID.
+func FindArticleRevision(ctx context.Context, id int64) (*ArticleRevision, error) {
+	ctx = observeQuery(ctx, "FindArticleRevision")
+	if id == 0 {
+		return nil, errors.New("Invalid ID: it can't be zero")
+	}
+	_article_revision := ArticleRevision{}
+	err := DB.GetContext(ctx, &_article_revision, DB.Rebind(`SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions WHERE article_revisions.id = ? LIMIT 1`), id)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevision error", "error", err)
+		return nil, err
+	}
+	return &_article_revision, nil
+}
+
+// FirstArticleRevision find the first one article_revision by ID ASC order.
+func FirstArticleRevision(ctx context.Context) (*ArticleRevision, error) {
+	ctx = observeQuery(ctx, "FirstArticleRevision")
+	_article_revision := ArticleRevision{}
+	err := DB.GetContext(ctx, &_article_revision, DB.Rebind(`SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions ORDER BY article_revisions.id ASC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("FirstArticleRevision error", "error", err)
+		return nil, err
+	}
+	return &_article_revision, nil
+}
+
+// FirstArticleRevisions find the first N article_revisions by ID ASC order.
+func FirstArticleRevisions(ctx context.Context, n uint32) ([]ArticleRevision, error) {
+	ctx = observeQuery(ctx, "FirstArticleRevisions")
+	_article_revisions := []ArticleRevision{}
+	sql := fmt.Sprintf("SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions ORDER BY article_revisions.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_article_revisions, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FirstArticleRevisions error", "error", err)
+		return nil, err
+	}
+	return _article_revisions, nil
+}
+
+// LastArticleRevision find the last one article_revision by ID DESC order.
+func LastArticleRevision(ctx context.Context) (*ArticleRevision, error) {
+	ctx = observeQuery(ctx, "LastArticleRevision")
+	_article_revision := ArticleRevision{}
+	err := DB.GetContext(ctx, &_article_revision, DB.Rebind(`SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions ORDER BY article_revisions.id DESC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("LastArticleRevision error", "error", err)
+		return nil, err
+	}
+	return &_article_revision, nil
+}
+
+// LastArticleRevisions find the last N article_revisions by ID DESC order.
+func LastArticleRevisions(ctx context.Context, n uint32) ([]ArticleRevision, error) {
+	ctx = observeQuery(ctx, "LastArticleRevisions")
+	_article_revisions := []ArticleRevision{}
+	sql := fmt.Sprintf("SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions ORDER BY article_revisions.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_article_revisions, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("LastArticleRevisions error", "error", err)
+		return nil, err
+	}
+	return _article_revisions, nil
+}
+
+// FindArticleRevisions find one or more article_revisions by the given ID(s).
+func FindArticleRevisions(ctx context.Context, ids ...int64) ([]ArticleRevision, error) {
+	ctx = observeQuery(ctx, "FindArticleRevisions")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("FindArticleRevisions error", "error", msg)
+		return nil, errors.New(msg)
+	}
+	_article_revisions := []ArticleRevision{}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions WHERE article_revisions.id IN (?%s)`, idsHolder))
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	err := DB.SelectContext(ctx, &_article_revisions, sql, idsT...)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisions error", "error", err)
+		return nil, err
+	}
+	return _article_revisions, nil
+}
+
+// FindArticleRevisionBy find a single article_revision by a field name and a value.
+func FindArticleRevisionBy(ctx context.Context, field string, val interface{}) (*ArticleRevision, error) {
+	ctx = observeQuery(ctx, "FindArticleRevisionBy")
+	_article_revision := ArticleRevision{}
+	sqlFmt := `SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions WHERE %s = ? LIMIT 1`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err := DB.GetContext(ctx, &_article_revision, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionBy error", "error", err)
+		return nil, err
+	}
+	return &_article_revision, nil
+}
+
+// FindArticleRevisionsBy find all article_revisions by a field name and a value.
+func FindArticleRevisionsBy(ctx context.Context, field string, val interface{}) (_article_revisions []ArticleRevision, err error) {
+	ctx = observeQuery(ctx, "FindArticleRevisionsBy")
+	sqlFmt := `SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions WHERE %s = ?`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err = DB.SelectContext(ctx, &_article_revisions, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionsBy error", "error", err)
+		return nil, err
+	}
+	return _article_revisions, nil
+}
+
+// AllArticleRevisions get all the ArticleRevision records.
+func AllArticleRevisions(ctx context.Context) (article_revisions []ArticleRevision, err error) {
+	ctx = observeQuery(ctx, "AllArticleRevisions")
+	err = DB.SelectContext(ctx, &article_revisions, "SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions")
+	if err != nil {
+		logger(ctx).Error("AllArticleRevisions error", "error", err)
+		return nil, err
+	}
+	return article_revisions, nil
+}
+
+// ArticleRevisionCount get the count of all the ArticleRevision records.
+func ArticleRevisionCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleRevisionCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM article_revisions")
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionCount error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ArticleRevisionCountWhere get the count of all the ArticleRevision records with a where clause.
+func ArticleRevisionCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleRevisionCountWhere")
+	sql := "SELECT count(*) FROM article_revisions"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionCountWhere error", "error", err)
+		return 0, err
+	}
+	err = stmt.GetContext(ctx, &c, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionCountWhere error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ArticleRevisionIncludesWhere get the ArticleRevision associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on ArticleRevision model.
+func ArticleRevisionIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_article_revisions []ArticleRevision, err error) {
+	_article_revisions, err = FindArticleRevisionsWhere(ctx, sql, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionIncludesWhere error", "error", err)
+		return nil, err
+	}
+	if len(assocs) == 0 {
+		logger(ctx).Warn("ArticleRevisionIncludesWhere without associated fields")
+		return _article_revisions, err
+	}
+	if len(_article_revisions) <= 0 {
+		return nil, errors.New("No results available")
+	}
+	ids := make([]interface{}, len(_article_revisions))
+	for _, v := range _article_revisions {
+		ids = append(ids, interface{}(v.Id))
+	}
+	return _article_revisions, nil
+}
+
+// ArticleRevisionIds get all the IDs of ArticleRevision records.
+func ArticleRevisionIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleRevisionIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM article_revisions")
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionIds error", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ArticleRevisionIdsWhere get all the IDs of ArticleRevision records by where restriction.
+func ArticleRevisionIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := ArticleRevisionIntCol(ctx, "id", where, args...)
+	return ids, err
+}
+
+// ArticleRevisionIntCol get some int64 typed column of ArticleRevision by where restriction.
+func ArticleRevisionIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleRevisionIntCol")
+	sql := "SELECT " + col + " FROM article_revisions"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionIntCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionIntCol error", "error", err)
+		return nil, err
+	}
+	return intColRecs, nil
+}
+
+// ArticleRevisionStrCol get some string typed column of ArticleRevision by where restriction.
+func ArticleRevisionStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "ArticleRevisionStrCol")
+	sql := "SELECT " + col + " FROM article_revisions"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionStrCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionStrCol error", "error", err)
+		return nil, err
+	}
+	return strColRecs, nil
+}
+
+// FindArticleRevisionsWhere query use a partial SQL clause that usually following after WHERE
+// with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleRevisionsWhere(ctx context.Context, where string, args ...interface{}) (article_revisions []ArticleRevision, err error) {
+	ctx = observeQuery(ctx, "FindArticleRevisionsWhere")
+	sql := "SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionsWhere error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &article_revisions, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionsWhere error", "error", err)
+		return nil, err
+	}
+	return article_revisions, nil
+}
+
+// EachArticleRevision iterate over the ArticleRevision records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachArticleRevision(ctx, "id > ?", []interface{}{100}, func(article_revision ArticleRevision) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachArticleRevision(ctx context.Context, where string, args []interface{}, fn func(ArticleRevision) error) error {
+	ctx = observeQuery(ctx, "EachArticleRevision")
+	sql := "SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachArticleRevision error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_article_revision := ArticleRevision{}
+		if err = rows.StructScan(&_article_revision); err != nil {
+			logger(ctx).Error("EachArticleRevision error", "error", err)
+			return err
+		}
+		if err = fn(_article_revision); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ArticleRevisionsInBatches iterate over all the ArticleRevision records in batches of batchSize,
+// see ArticleRevisionsInBatchesWhere.
+func ArticleRevisionsInBatches(ctx context.Context, batchSize int, fn func([]ArticleRevision) error) error {
+	return ArticleRevisionsInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// ArticleRevisionsInBatchesWhere iterate over the ArticleRevision records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func ArticleRevisionsInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]ArticleRevision) error) error {
+	ctx = observeQuery(ctx, "ArticleRevisionsInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT COALESCE(article_revisions.text, '') AS text, COALESCE(article_revisions.user_id, 0) AS user_id, article_revisions.id, article_revisions.article_id, article_revisions.rev, article_revisions.title, article_revisions.created_at, article_revisions.updated_at FROM article_revisions WHERE %sarticle_revisions.id > ? ORDER BY article_revisions.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("ArticleRevisionsInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_article_revisions := []ArticleRevision{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_article_revisions, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("ArticleRevisionsInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_article_revisions) == 0 {
+			return nil
+		}
+		lastId = _article_revisions[len(_article_revisions)-1].Id
+		if err = fn(_article_revisions); err != nil {
+			return err
+		}
+		if len(_article_revisions) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindArticleRevisionBySql query use a complete SQL clause
+// with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
+// will return only One record in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleRevisionBySql(ctx context.Context, sql string, args ...interface{}) (*ArticleRevision, error) {
+	ctx = observeQuery(ctx, "FindArticleRevisionBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionBySql error", "error", err)
+		return nil, err
+	}
+	_article_revision := &ArticleRevision{}
+	err = stmt.GetContext(ctx, _article_revision, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionBySql error", "error", err)
+		return nil, err
+	}
+	return _article_revision, nil
+}
+
+// FindArticleRevisionsBySql query use a complete SQL clause
+// with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleRevisionsBySql(ctx context.Context, sql string, args ...interface{}) (article_revisions []ArticleRevision, err error) {
+	ctx = observeQuery(ctx, "FindArticleRevisionsBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionsBySql error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &article_revisions, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleRevisionsBySql error", "error", err)
+		return nil, err
+	}
+	return article_revisions, nil
+}
+
+// CreateArticleRevision use a named params to create a single ArticleRevision record.
+// A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
+func CreateArticleRevision(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateArticleRevision")
+	if len(am) == 0 {
+		return 0, fmt.Errorf("Zero key in the attributes map!")
+	}
+	t := time.Now()
+	for _, v := range []string{"created_at", "updated_at"} {
+		if am[v] == nil {
+			am[v] = t
+		}
+	}
+	keys := allKeys(am)
+	sqlFmt := `INSERT INTO article_revisions (%s) VALUES (%s)`
+	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
+	result, err := DB.NamedExecContext(ctx, sql, am)
+	if err != nil {
+		logger(ctx).Error("CreateArticleRevision error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("CreateArticleRevision error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+// Create is a method for ArticleRevision to create a record.
+func (_article_revision *ArticleRevision) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "ArticleRevision.Create")
+	ok, err := govalidator.ValidateStruct(_article_revision)
+	if !ok {
+		errMsg := "Validate ArticleRevision struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ArticleRevision struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ArticleRevision.Create error", "error", errMsg)
+		return 0, errors.New(errMsg)
+	}
+	t := time.Now()
+	_article_revision.CreatedAt = t
+	_article_revision.UpdatedAt = t
+    sql := `INSERT INTO article_revisions (article_id,rev,title,text,created_at,updated_at,user_id) VALUES (:article_id,:rev,:title,:text,:created_at,:updated_at,NULLIF(:user_id, 0))`
+    result, err := DB.NamedExecContext(ctx, sql, _article_revision)
+	if err != nil {
+		logger(ctx).Error("ArticleRevision.Create error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("ArticleRevision.Create error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+
+
+// CreateArticle is a method for a ArticleRevision object to create an associated Article record.
+func (_article_revision *ArticleRevision) CreateArticle(ctx context.Context, am map[string]interface{}) error {
+	am["article_revision_id"] = _article_revision.Id
+	_, err := CreateArticle(ctx, am)
+	return err
+}
+
+// CreateUser is a method for a ArticleRevision object to create an associated User record.
+func (_article_revision *ArticleRevision) CreateUser(ctx context.Context, am map[string]interface{}) error {
+	am["article_revision_id"] = _article_revision.Id
+	_, err := CreateUser(ctx, am)
+	return err
+}
+
+
+// Destroy is method used for a ArticleRevision object to be destroyed.
+func (_article_revision *ArticleRevision) Destroy(ctx context.Context) error {
+	if _article_revision.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := DestroyArticleRevision(ctx, _article_revision.Id)
+	return err
+}
+
+// DestroyArticleRevision will destroy a ArticleRevision record specified by the id parameter.
+func DestroyArticleRevision(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyArticleRevision")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM article_revisions WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DestroyArticleRevisions will destroy ArticleRevision records those specified by the ids parameters.
+func DestroyArticleRevisions(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticleRevisions")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("DestroyArticleRevisions error", "error", msg)
+		return 0, errors.New(msg)
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := fmt.Sprintf(`DELETE FROM article_revisions WHERE id IN (?%s)`, idsHolder)
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DestroyArticleRevisionsWhere delete records by a where clause restriction.
+// e.g. DestroyArticleRevisionsWhere("name = ?", "John")
+// And this func will not call the association dependent action
+func DestroyArticleRevisionsWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticleRevisionsWhere")
+	sql := `DELETE FROM article_revisions WHERE `
+	if len(where) > 0 {
+		sql = sql + where
+	} else {
+		return 0, errors.New("No WHERE conditions provided")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+
+// Save method is used for a ArticleRevision object to update an existed record mainly.
+// If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
+func (_article_revision *ArticleRevision) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "ArticleRevision.Save")
+	ok, err := govalidator.ValidateStruct(_article_revision)
+	if !ok {
+		errMsg := "Validate ArticleRevision struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ArticleRevision struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ArticleRevision.Save error", "error", errMsg)
+		return errors.New(errMsg)
+	}
+	if _article_revision.Id == 0 {
+		_, err = _article_revision.Create(ctx)
+		return err
+	}
+	_article_revision.UpdatedAt = time.Now()
+	sqlFmt := `UPDATE article_revisions SET %s WHERE id = %v`
+	sqlStr := fmt.Sprintf(sqlFmt, "article_id = :article_id, rev = :rev, title = :title, text = :text, updated_at = :updated_at, user_id = NULLIF(:user_id, 0)", _article_revision.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _article_revision)
+    return err
+}
+
+// UpdateArticleRevision is used to update a record with a id and map[string]interface{} typed key-value parameters.
+func UpdateArticleRevision(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateArticleRevision")
+	if len(am) == 0 {
+		return errors.New("Zero key in the attributes map!")
+	}
+	am["updated_at"] = time.Now()
+	keys := allKeys(am)
+	sqlFmt := `UPDATE article_revisions SET %s WHERE id = %v`
+	setKeysArr := []string{}
+	for _,v := range keys {
+		s := fmt.Sprintf(" %s = :%s", v, v)
+		setKeysArr = append(setKeysArr, s)
+	}
+	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
+	if err != nil {
+		logger(ctx).Error("UpdateArticleRevision error", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Update is a method used to update a ArticleRevision record with the map[string]interface{} typed key-value parameters.
+func (_article_revision *ArticleRevision) Update(ctx context.Context, am map[string]interface{}) error {
+	if _article_revision.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleRevision(ctx, _article_revision.Id, am)
+	return err
+}
+
+// UpdateAttributes method is supposed to be used to update ArticleRevision records as corresponding update_attributes in Ruby on Rails.
+func (_article_revision *ArticleRevision) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
+	if _article_revision.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleRevision(ctx, _article_revision.Id, am)
+	return err
+}
+
+// UpdateColumns method is supposed to be used to update ArticleRevision records as corresponding update_columns in Ruby on Rails.
+func (_article_revision *ArticleRevision) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
+	if _article_revision.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleRevision(ctx, _article_revision.Id, am)
+	return err
+}
+
+// UpdateArticleRevisionsBySql is used to update ArticleRevision records by a SQL clause
+// using the '?' binding syntax.
+func UpdateArticleRevisionsBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateArticleRevisionsBySql")
+	if sql == "" {
+		return 0, errors.New("A blank SQL clause")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
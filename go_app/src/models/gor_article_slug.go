@@ -0,0 +1,809 @@
+
+
+// Package models includes the functions on the model ArticleSlug.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type ArticleSlug struct {
+	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
+ArticleId int64 `json:"article_id,omitempty" db:"article_id" valid:"required"`
+Slug string `json:"slug,omitempty" db:"slug" valid:"required"`
+CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
+UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+Article Article `json:"article,omitempty" db:"article" valid:"-"`
+}
+
+// DataStruct for the pagination
+type ArticleSlugPage struct {
+	WhereString string
+	WhereParams []interface{}
+	Order       map[string]string
+	FirstId     int64
+	LastId      int64
+	PageNum     int
+	PerPage     int
+	TotalPages  int
+	TotalItems  int64
+	orderStr    string
+}
+
+// Current get the current page of ArticleSlugPage object for pagination.
+func (_p *ArticleSlugPage) Current(ctx context.Context) ([]ArticleSlug, error) {
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("current")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_slugs, err := FindArticleSlugsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_slugs) != 0 {
+		_p.FirstId, _p.LastId = article_slugs[0].Id, article_slugs[len(article_slugs)-1].Id
+	}
+	return article_slugs, nil
+}
+
+// Previous get the previous page of ArticleSlugPage object for pagination.
+func (_p *ArticleSlugPage) Previous(ctx context.Context) ([]ArticleSlug, error) {
+	if _p.PageNum == 0 {
+		return nil, errors.New("This's the first page, no previous page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("previous")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_slugs, err := FindArticleSlugsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_slugs) != 0 {
+		_p.FirstId, _p.LastId = article_slugs[0].Id, article_slugs[len(article_slugs)-1].Id
+	}
+	_p.PageNum -= 1
+	return article_slugs, nil
+}
+
+// Next get the next page of ArticleSlugPage object for pagination.
+func (_p *ArticleSlugPage) Next(ctx context.Context) ([]ArticleSlug, error) {
+	if _p.PageNum == _p.TotalPages-1 {
+		return nil, errors.New("This's the last page, no next page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("next")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_slugs, err := FindArticleSlugsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_slugs) != 0 {
+		_p.FirstId, _p.LastId = article_slugs[0].Id, article_slugs[len(article_slugs)-1].Id
+	}
+	_p.PageNum += 1
+	return article_slugs, nil
+}
+
+// GetPage is a helper function for the ArticleSlugPage object to return a corresponding page due to
+// the parameter passed in, i.e. one of "previous, current or next".
+func (_p *ArticleSlugPage) GetPage(ctx context.Context, direction string) (ps []ArticleSlug, err error) {
+	switch direction {
+	case "previous":
+		ps, _ = _p.Previous(ctx)
+	case "next":
+		ps, _ = _p.Next(ctx)
+	case "current":
+		ps, _ = _p.Current(ctx)
+	default:
+		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
+	}
+	return
+}
+
+// buildOrder is for ArticleSlugPage object to build a SQL ORDER BY clause.
+func (_p *ArticleSlugPage) buildOrder() {
+	tempList := []string{}
+	for k, v := range _p.Order {
+		tempList = append(tempList, fmt.Sprintf("%v %v", k, v))
+	}
+	_p.orderStr = " ORDER BY " + strings.Join(tempList, ", ")
+}
+
+// buildIdRestrict is for ArticleSlugPage object to build a SQL clause for ID restriction,
+// implementing a simple keyset style pagination.
+func (_p *ArticleSlugPage) buildIdRestrict(direction string) (idStr string, idParams []interface{}) {
+	switch direction {
+	case "previous":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.FirstId)
+		} else {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.FirstId)
+		}
+	case "current":
+		// trick to make Where function work
+		if _p.PageNum == 0 && _p.FirstId == 0 && _p.LastId == 0 {
+			idStr += "id > ? "
+			idParams = append(idParams, 0)
+		} else {
+			if strings.ToLower(_p.Order["id"]) == "desc" {
+				idStr += "id <= ? AND id >= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			} else {
+				idStr += "id >= ? AND id <= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			}
+		}
+	case "next":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.LastId)
+		} else {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.LastId)
+		}
+	}
+	if _p.WhereString != "" {
+		idStr = " AND " + idStr
+	}
+	return
+}
+
+// buildPageCount calculate the TotalItems/TotalPages for the ArticleSlugPage object.
+func (_p *ArticleSlugPage) buildPageCount(ctx context.Context) error {
+	count, err := ArticleSlugCountWhere(ctx, _p.WhereString, _p.WhereParams...)
+	if err != nil {
+		return err
+	}
+	_p.TotalItems = count
+	if _p.PerPage == 0 {
+		_p.PerPage = 10
+	}
+	_p.TotalPages = int(math.Ceil(float64(_p.TotalItems) / float64(_p.PerPage)))
+	return nil
+}
+
+
+// FindArticleSlug find a single article_slug by an ID.
+func FindArticleSlug(ctx context.Context, id int64) (*ArticleSlug, error) {
+	ctx = observeQuery(ctx, "FindArticleSlug")
+	if id == 0 {
+		return nil, errors.New("Invalid ID: it can't be zero")
+	}
+	_article_slug := ArticleSlug{}
+	err := DB.GetContext(ctx, &_article_slug, DB.Rebind(`SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs WHERE article_slugs.id = ? LIMIT 1`), id)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlug error", "error", err)
+		return nil, err
+	}
+	return &_article_slug, nil
+}
+
+// FirstArticleSlug find the first one article_slug by ID ASC order.
+func FirstArticleSlug(ctx context.Context) (*ArticleSlug, error) {
+	ctx = observeQuery(ctx, "FirstArticleSlug")
+	_article_slug := ArticleSlug{}
+	err := DB.GetContext(ctx, &_article_slug, DB.Rebind(`SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs ORDER BY article_slugs.id ASC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("FirstArticleSlug error", "error", err)
+		return nil, err
+	}
+	return &_article_slug, nil
+}
+
+// FirstArticleSlugs find the first N article_slugs by ID ASC order.
+func FirstArticleSlugs(ctx context.Context, n uint32) ([]ArticleSlug, error) {
+	ctx = observeQuery(ctx, "FirstArticleSlugs")
+	_article_slugs := []ArticleSlug{}
+	sql := fmt.Sprintf("SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs ORDER BY article_slugs.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_article_slugs, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FirstArticleSlugs error", "error", err)
+		return nil, err
+	}
+	return _article_slugs, nil
+}
+
+// LastArticleSlug find the last one article_slug by ID DESC order.
+func LastArticleSlug(ctx context.Context) (*ArticleSlug, error) {
+	ctx = observeQuery(ctx, "LastArticleSlug")
+	_article_slug := ArticleSlug{}
+	err := DB.GetContext(ctx, &_article_slug, DB.Rebind(`SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs ORDER BY article_slugs.id DESC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("LastArticleSlug error", "error", err)
+		return nil, err
+	}
+	return &_article_slug, nil
+}
+
+// LastArticleSlugs find the last N article_slugs by ID DESC order.
+func LastArticleSlugs(ctx context.Context, n uint32) ([]ArticleSlug, error) {
+	ctx = observeQuery(ctx, "LastArticleSlugs")
+	_article_slugs := []ArticleSlug{}
+	sql := fmt.Sprintf("SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs ORDER BY article_slugs.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_article_slugs, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("LastArticleSlugs error", "error", err)
+		return nil, err
+	}
+	return _article_slugs, nil
+}
+
+// FindArticleSlugs find one or more article_slugs by the given ID(s).
+func FindArticleSlugs(ctx context.Context, ids ...int64) ([]ArticleSlug, error) {
+	ctx = observeQuery(ctx, "FindArticleSlugs")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("FindArticleSlugs error", "error", msg)
+		return nil, errors.New(msg)
+	}
+	_article_slugs := []ArticleSlug{}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := DB.Rebind(fmt.Sprintf(`SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs WHERE article_slugs.id IN (?%s)`, idsHolder))
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	err := DB.SelectContext(ctx, &_article_slugs, sql, idsT...)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugs error", "error", err)
+		return nil, err
+	}
+	return _article_slugs, nil
+}
+
+// FindArticleSlugBy find a single article_slug by a field name and a value.
+func FindArticleSlugBy(ctx context.Context, field string, val interface{}) (*ArticleSlug, error) {
+	ctx = observeQuery(ctx, "FindArticleSlugBy")
+	_article_slug := ArticleSlug{}
+	sqlFmt := `SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs WHERE %s = ? LIMIT 1`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err := DB.GetContext(ctx, &_article_slug, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugBy error", "error", err)
+		return nil, err
+	}
+	return &_article_slug, nil
+}
+
+// FindArticleSlugsBy find all article_slugs by a field name and a value.
+func FindArticleSlugsBy(ctx context.Context, field string, val interface{}) (_article_slugs []ArticleSlug, err error) {
+	ctx = observeQuery(ctx, "FindArticleSlugsBy")
+	sqlFmt := `SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs WHERE %s = ?`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err = DB.SelectContext(ctx, &_article_slugs, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugsBy error", "error", err)
+		return nil, err
+	}
+	return _article_slugs, nil
+}
+
+// AllArticleSlugs get all the ArticleSlug records.
+func AllArticleSlugs(ctx context.Context) (article_slugs []ArticleSlug, err error) {
+	ctx = observeQuery(ctx, "AllArticleSlugs")
+	err = DB.SelectContext(ctx, &article_slugs, "SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs")
+	if err != nil {
+		logger(ctx).Error("AllArticleSlugs error", "error", err)
+		return nil, err
+	}
+	return article_slugs, nil
+}
+
+// ArticleSlugCount get the count of all the ArticleSlug records.
+func ArticleSlugCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleSlugCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM article_slugs")
+	if err != nil {
+		logger(ctx).Error("ArticleSlugCount error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ArticleSlugCountWhere get the count of all the ArticleSlug records with a where clause.
+func ArticleSlugCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleSlugCountWhere")
+	sql := "SELECT count(*) FROM article_slugs"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleSlugCountWhere error", "error", err)
+		return 0, err
+	}
+	err = stmt.GetContext(ctx, &c, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleSlugCountWhere error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ArticleSlugIncludesWhere get the ArticleSlug associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on ArticleSlug model.
+func ArticleSlugIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_article_slugs []ArticleSlug, err error) {
+	_article_slugs, err = FindArticleSlugsWhere(ctx, sql, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleSlugIncludesWhere error", "error", err)
+		return nil, err
+	}
+	if len(assocs) == 0 {
+		logger(ctx).Warn("ArticleSlugIncludesWhere without associated fields")
+		return _article_slugs, err
+	}
+	if len(_article_slugs) <= 0 {
+		return nil, errors.New("No results available")
+	}
+	ids := make([]interface{}, len(_article_slugs))
+	for _, v := range _article_slugs {
+		ids = append(ids, interface{}(v.Id))
+	}
+	return _article_slugs, nil
+}
+
+// ArticleSlugIds get all the IDs of ArticleSlug records.
+func ArticleSlugIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleSlugIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM article_slugs")
+	if err != nil {
+		logger(ctx).Error("ArticleSlugIds error", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ArticleSlugIdsWhere get all the IDs of ArticleSlug records by where restriction.
+func ArticleSlugIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := ArticleSlugIntCol(ctx, "id", where, args...)
+	return ids, err
+}
+
+// ArticleSlugIntCol get some int64 typed column of ArticleSlug by where restriction.
+func ArticleSlugIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleSlugIntCol")
+	sql := "SELECT " + col + " FROM article_slugs"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleSlugIntCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleSlugIntCol error", "error", err)
+		return nil, err
+	}
+	return intColRecs, nil
+}
+
+// ArticleSlugStrCol get some string typed column of ArticleSlug by where restriction.
+func ArticleSlugStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "ArticleSlugStrCol")
+	sql := "SELECT " + col + " FROM article_slugs"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleSlugStrCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleSlugStrCol error", "error", err)
+		return nil, err
+	}
+	return strColRecs, nil
+}
+
+// FindArticleSlugsWhere query use a partial SQL clause that usually following after WHERE
+// with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleSlugsWhere(ctx context.Context, where string, args ...interface{}) (article_slugs []ArticleSlug, err error) {
+	ctx = observeQuery(ctx, "FindArticleSlugsWhere")
+	sql := "SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugsWhere error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &article_slugs, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugsWhere error", "error", err)
+		return nil, err
+	}
+	return article_slugs, nil
+}
+
+// EachArticleSlug iterate over the ArticleSlug records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachArticleSlug(ctx, "id > ?", []interface{}{100}, func(article_slug ArticleSlug) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachArticleSlug(ctx context.Context, where string, args []interface{}, fn func(ArticleSlug) error) error {
+	ctx = observeQuery(ctx, "EachArticleSlug")
+	sql := "SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachArticleSlug error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_article_slug := ArticleSlug{}
+		if err = rows.StructScan(&_article_slug); err != nil {
+			logger(ctx).Error("EachArticleSlug error", "error", err)
+			return err
+		}
+		if err = fn(_article_slug); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ArticleSlugsInBatches iterate over all the ArticleSlug records in batches of batchSize,
+// see ArticleSlugsInBatchesWhere.
+func ArticleSlugsInBatches(ctx context.Context, batchSize int, fn func([]ArticleSlug) error) error {
+	return ArticleSlugsInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// ArticleSlugsInBatchesWhere iterate over the ArticleSlug records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func ArticleSlugsInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]ArticleSlug) error) error {
+	ctx = observeQuery(ctx, "ArticleSlugsInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT article_slugs.id, article_slugs.article_id, article_slugs.slug, article_slugs.created_at, article_slugs.updated_at FROM article_slugs WHERE %sarticle_slugs.id > ? ORDER BY article_slugs.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("ArticleSlugsInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_article_slugs := []ArticleSlug{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_article_slugs, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("ArticleSlugsInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_article_slugs) == 0 {
+			return nil
+		}
+		lastId = _article_slugs[len(_article_slugs)-1].Id
+		if err = fn(_article_slugs); err != nil {
+			return err
+		}
+		if len(_article_slugs) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindArticleSlugBySql query use a complete SQL clause
+// with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
+// will return only One record in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleSlugBySql(ctx context.Context, sql string, args ...interface{}) (*ArticleSlug, error) {
+	ctx = observeQuery(ctx, "FindArticleSlugBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugBySql error", "error", err)
+		return nil, err
+	}
+	_article_slug := &ArticleSlug{}
+	err = stmt.GetContext(ctx, _article_slug, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugBySql error", "error", err)
+		return nil, err
+	}
+	return _article_slug, nil
+}
+
+// FindArticleSlugsBySql query use a complete SQL clause
+// with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleSlugsBySql(ctx context.Context, sql string, args ...interface{}) (article_slugs []ArticleSlug, err error) {
+	ctx = observeQuery(ctx, "FindArticleSlugsBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugsBySql error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &article_slugs, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleSlugsBySql error", "error", err)
+		return nil, err
+	}
+	return article_slugs, nil
+}
+
+// CreateArticleSlug use a named params to create a single ArticleSlug record.
+// A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
+func CreateArticleSlug(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateArticleSlug")
+	if len(am) == 0 {
+		return 0, fmt.Errorf("Zero key in the attributes map!")
+	}
+	t := time.Now()
+	for _, v := range []string{"created_at", "updated_at"} {
+		if am[v] == nil {
+			am[v] = t
+		}
+	}
+	keys := allKeys(am)
+	sqlFmt := `INSERT INTO article_slugs (%s) VALUES (%s)`
+	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
+	result, err := DB.NamedExecContext(ctx, sql, am)
+	if err != nil {
+		logger(ctx).Error("CreateArticleSlug error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("CreateArticleSlug error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+// Create is a method for ArticleSlug to create a record.
+func (_article_slug *ArticleSlug) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "ArticleSlug.Create")
+	ok, err := govalidator.ValidateStruct(_article_slug)
+	if !ok {
+		errMsg := "Validate ArticleSlug struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ArticleSlug struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ArticleSlug.Create error", "error", errMsg)
+		return 0, errors.New(errMsg)
+	}
+	t := time.Now()
+	_article_slug.CreatedAt = t
+	_article_slug.UpdatedAt = t
+    sql := `INSERT INTO article_slugs (article_id,slug,created_at,updated_at) VALUES (:article_id,:slug,:created_at,:updated_at)`
+    result, err := DB.NamedExecContext(ctx, sql, _article_slug)
+	if err != nil {
+		logger(ctx).Error("ArticleSlug.Create error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("ArticleSlug.Create error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+
+
+// CreateArticle is a method for a ArticleSlug object to create an associated Article record.
+func (_article_slug *ArticleSlug) CreateArticle(ctx context.Context, am map[string]interface{}) error {
+	am["article_slug_id"] = _article_slug.Id
+	_, err := CreateArticle(ctx, am)
+	return err
+}
+
+
+// Destroy is method used for a ArticleSlug object to be destroyed.
+func (_article_slug *ArticleSlug) Destroy(ctx context.Context) error {
+	if _article_slug.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := DestroyArticleSlug(ctx, _article_slug.Id)
+	return err
+}
+
+// DestroyArticleSlug will destroy a ArticleSlug record specified by the id parameter.
+func DestroyArticleSlug(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyArticleSlug")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM article_slugs WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DestroyArticleSlugs will destroy ArticleSlug records those specified by the ids parameters.
+func DestroyArticleSlugs(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticleSlugs")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("DestroyArticleSlugs error", "error", msg)
+		return 0, errors.New(msg)
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := fmt.Sprintf(`DELETE FROM article_slugs WHERE id IN (?%s)`, idsHolder)
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DestroyArticleSlugsWhere delete records by a where clause restriction.
+// e.g. DestroyArticleSlugsWhere("name = ?", "John")
+// And this func will not call the association dependent action
+func DestroyArticleSlugsWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticleSlugsWhere")
+	sql := `DELETE FROM article_slugs WHERE `
+	if len(where) > 0 {
+		sql = sql + where
+	} else {
+		return 0, errors.New("No WHERE conditions provided")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+
+// Save method is used for a ArticleSlug object to update an existed record mainly.
+// If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
+func (_article_slug *ArticleSlug) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "ArticleSlug.Save")
+	ok, err := govalidator.ValidateStruct(_article_slug)
+	if !ok {
+		errMsg := "Validate ArticleSlug struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ArticleSlug struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ArticleSlug.Save error", "error", errMsg)
+		return errors.New(errMsg)
+	}
+	if _article_slug.Id == 0 {
+		_, err = _article_slug.Create(ctx)
+		return err
+	}
+	_article_slug.UpdatedAt = time.Now()
+	sqlFmt := `UPDATE article_slugs SET %s WHERE id = %v`
+	sqlStr := fmt.Sprintf(sqlFmt, "article_id = :article_id, slug = :slug, updated_at = :updated_at", _article_slug.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _article_slug)
+    return err
+}
+
+// UpdateArticleSlug is used to update a record with a id and map[string]interface{} typed key-value parameters.
+func UpdateArticleSlug(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateArticleSlug")
+	if len(am) == 0 {
+		return errors.New("Zero key in the attributes map!")
+	}
+	am["updated_at"] = time.Now()
+	keys := allKeys(am)
+	sqlFmt := `UPDATE article_slugs SET %s WHERE id = %v`
+	setKeysArr := []string{}
+	for _,v := range keys {
+		s := fmt.Sprintf(" %s = :%s", v, v)
+		setKeysArr = append(setKeysArr, s)
+	}
+	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
+	if err != nil {
+		logger(ctx).Error("UpdateArticleSlug error", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Update is a method used to update a ArticleSlug record with the map[string]interface{} typed key-value parameters.
+func (_article_slug *ArticleSlug) Update(ctx context.Context, am map[string]interface{}) error {
+	if _article_slug.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleSlug(ctx, _article_slug.Id, am)
+	return err
+}
+
+// UpdateAttributes method is supposed to be used to update ArticleSlug records as corresponding update_attributes in Ruby on Rails.
+func (_article_slug *ArticleSlug) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
+	if _article_slug.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleSlug(ctx, _article_slug.Id, am)
+	return err
+}
+
+// UpdateColumns method is supposed to be used to update ArticleSlug records as corresponding update_columns in Ruby on Rails.
+func (_article_slug *ArticleSlug) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
+	if _article_slug.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleSlug(ctx, _article_slug.Id, am)
+	return err
+}
+
+// UpdateArticleSlugsBySql is used to update ArticleSlug records by a SQL clause
+// using the '?' binding syntax.
+func UpdateArticleSlugsBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateArticleSlugsBySql")
+	if sql == "" {
+		return 0, errors.New("A blank SQL clause")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
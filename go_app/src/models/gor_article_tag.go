@@ -0,0 +1,817 @@
+
+
+// Package models includes the functions on the model ArticleTag.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type ArticleTag struct {
+	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
+ArticleId int64 `json:"article_id,omitempty" db:"article_id" valid:"required"`
+TagId int64 `json:"tag_id,omitempty" db:"tag_id" valid:"required"`
+CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
+UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+Article Article `json:"article,omitempty" db:"article" valid:"-"`
+Tag Tag `json:"tag,omitempty" db:"tag" valid:"-"`
+}
+
+// DataStruct for the pagination
+type ArticleTagPage struct {
+	WhereString string
+	WhereParams []interface{}
+	Order       map[string]string
+	FirstId     int64
+	LastId      int64
+	PageNum     int
+	PerPage     int
+	TotalPages  int
+	TotalItems  int64
+	orderStr    string
+}
+
+// Current get the current page of ArticleTagPage object for pagination.
+func (_p *ArticleTagPage) Current(ctx context.Context) ([]ArticleTag, error) {
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("current")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_tags, err := FindArticleTagsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_tags) != 0 {
+		_p.FirstId, _p.LastId = article_tags[0].Id, article_tags[len(article_tags)-1].Id
+	}
+	return article_tags, nil
+}
+
+// Previous get the previous page of ArticleTagPage object for pagination.
+func (_p *ArticleTagPage) Previous(ctx context.Context) ([]ArticleTag, error) {
+	if _p.PageNum == 0 {
+		return nil, errors.New("This's the first page, no previous page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("previous")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_tags, err := FindArticleTagsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_tags) != 0 {
+		_p.FirstId, _p.LastId = article_tags[0].Id, article_tags[len(article_tags)-1].Id
+	}
+	_p.PageNum -= 1
+	return article_tags, nil
+}
+
+// Next get the next page of ArticleTagPage object for pagination.
+func (_p *ArticleTagPage) Next(ctx context.Context) ([]ArticleTag, error) {
+	if _p.PageNum == _p.TotalPages-1 {
+		return nil, errors.New("This's the last page, no next page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("next")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	article_tags, err := FindArticleTagsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(article_tags) != 0 {
+		_p.FirstId, _p.LastId = article_tags[0].Id, article_tags[len(article_tags)-1].Id
+	}
+	_p.PageNum += 1
+	return article_tags, nil
+}
+
+// GetPage is a helper function for the ArticleTagPage object to return a corresponding page due to
+// the parameter passed in, i.e. one of "previous, current or next".
+func (_p *ArticleTagPage) GetPage(ctx context.Context, direction string) (ps []ArticleTag, err error) {
+	switch direction {
+	case "previous":
+		ps, _ = _p.Previous(ctx)
+	case "next":
+		ps, _ = _p.Next(ctx)
+	case "current":
+		ps, _ = _p.Current(ctx)
+	default:
+		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
+	}
+	return
+}
+
+// buildOrder is for ArticleTagPage object to build a SQL ORDER BY clause.
+func (_p *ArticleTagPage) buildOrder() {
+	tempList := []string{}
+	for k, v := range _p.Order {
+		tempList = append(tempList, fmt.Sprintf("%v %v", k, v))
+	}
+	_p.orderStr = " ORDER BY " + strings.Join(tempList, ", ")
+}
+
+// buildIdRestrict is for ArticleTagPage object to build a SQL clause for ID restriction,
+// implementing a simple keyset style pagination.
+func (_p *ArticleTagPage) buildIdRestrict(direction string) (idStr string, idParams []interface{}) {
+	switch direction {
+	case "previous":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.FirstId)
+		} else {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.FirstId)
+		}
+	case "current":
+		// trick to make Where function work
+		if _p.PageNum == 0 && _p.FirstId == 0 && _p.LastId == 0 {
+			idStr += "id > ? "
+			idParams = append(idParams, 0)
+		} else {
+			if strings.ToLower(_p.Order["id"]) == "desc" {
+				idStr += "id <= ? AND id >= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			} else {
+				idStr += "id >= ? AND id <= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			}
+		}
+	case "next":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.LastId)
+		} else {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.LastId)
+		}
+	}
+	if _p.WhereString != "" {
+		idStr = " AND " + idStr
+	}
+	return
+}
+
+// buildPageCount calculate the TotalItems/TotalPages for the ArticleTagPage object.
+func (_p *ArticleTagPage) buildPageCount(ctx context.Context) error {
+	count, err := ArticleTagCountWhere(ctx, _p.WhereString, _p.WhereParams...)
+	if err != nil {
+		return err
+	}
+	_p.TotalItems = count
+	if _p.PerPage == 0 {
+		_p.PerPage = 10
+	}
+	_p.TotalPages = int(math.Ceil(float64(_p.TotalItems) / float64(_p.PerPage)))
+	return nil
+}
+
+
+// FindArticleTag find a single article_tag by an ID.
+func FindArticleTag(ctx context.Context, id int64) (*ArticleTag, error) {
+	ctx = observeQuery(ctx, "FindArticleTag")
+	if id == 0 {
+		return nil, errors.New("Invalid ID: it can't be zero")
+	}
+	_article_tag := ArticleTag{}
+	err := DB.GetContext(ctx, &_article_tag, DB.Rebind(`SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags WHERE article_tags.id = ? LIMIT 1`), id)
+	if err != nil {
+		logger(ctx).Error("FindArticleTag error", "error", err)
+		return nil, err
+	}
+	return &_article_tag, nil
+}
+
+// FirstArticleTag find the first one article_tag by ID ASC order.
+func FirstArticleTag(ctx context.Context) (*ArticleTag, error) {
+	ctx = observeQuery(ctx, "FirstArticleTag")
+	_article_tag := ArticleTag{}
+	err := DB.GetContext(ctx, &_article_tag, DB.Rebind(`SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags ORDER BY article_tags.id ASC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("FirstArticleTag error", "error", err)
+		return nil, err
+	}
+	return &_article_tag, nil
+}
+
+// FirstArticleTags find the first N article_tags by ID ASC order.
+func FirstArticleTags(ctx context.Context, n uint32) ([]ArticleTag, error) {
+	ctx = observeQuery(ctx, "FirstArticleTags")
+	_article_tags := []ArticleTag{}
+	sql := fmt.Sprintf("SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags ORDER BY article_tags.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_article_tags, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FirstArticleTags error", "error", err)
+		return nil, err
+	}
+	return _article_tags, nil
+}
+
+// LastArticleTag find the last one article_tag by ID DESC order.
+func LastArticleTag(ctx context.Context) (*ArticleTag, error) {
+	ctx = observeQuery(ctx, "LastArticleTag")
+	_article_tag := ArticleTag{}
+	err := DB.GetContext(ctx, &_article_tag, DB.Rebind(`SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags ORDER BY article_tags.id DESC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("LastArticleTag error", "error", err)
+		return nil, err
+	}
+	return &_article_tag, nil
+}
+
+// LastArticleTags find the last N article_tags by ID DESC order.
+func LastArticleTags(ctx context.Context, n uint32) ([]ArticleTag, error) {
+	ctx = observeQuery(ctx, "LastArticleTags")
+	_article_tags := []ArticleTag{}
+	sql := fmt.Sprintf("SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags ORDER BY article_tags.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_article_tags, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("LastArticleTags error", "error", err)
+		return nil, err
+	}
+	return _article_tags, nil
+}
+
+// FindArticleTags find one or more article_tags by the given ID(s).
+func FindArticleTags(ctx context.Context, ids ...int64) ([]ArticleTag, error) {
+	ctx = observeQuery(ctx, "FindArticleTags")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("FindArticleTags error", "error", msg)
+		return nil, errors.New(msg)
+	}
+	_article_tags := []ArticleTag{}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := DB.Rebind(fmt.Sprintf(`SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags WHERE article_tags.id IN (?%s)`, idsHolder))
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	err := DB.SelectContext(ctx, &_article_tags, sql, idsT...)
+	if err != nil {
+		logger(ctx).Error("FindArticleTags error", "error", err)
+		return nil, err
+	}
+	return _article_tags, nil
+}
+
+// FindArticleTagBy find a single article_tag by a field name and a value.
+func FindArticleTagBy(ctx context.Context, field string, val interface{}) (*ArticleTag, error) {
+	ctx = observeQuery(ctx, "FindArticleTagBy")
+	_article_tag := ArticleTag{}
+	sqlFmt := `SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags WHERE %s = ? LIMIT 1`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err := DB.GetContext(ctx, &_article_tag, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindArticleTagBy error", "error", err)
+		return nil, err
+	}
+	return &_article_tag, nil
+}
+
+// FindArticleTagsBy find all article_tags by a field name and a value.
+func FindArticleTagsBy(ctx context.Context, field string, val interface{}) (_article_tags []ArticleTag, err error) {
+	ctx = observeQuery(ctx, "FindArticleTagsBy")
+	sqlFmt := `SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags WHERE %s = ?`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err = DB.SelectContext(ctx, &_article_tags, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindArticleTagsBy error", "error", err)
+		return nil, err
+	}
+	return _article_tags, nil
+}
+
+// AllArticleTags get all the ArticleTag records.
+func AllArticleTags(ctx context.Context) (article_tags []ArticleTag, err error) {
+	ctx = observeQuery(ctx, "AllArticleTags")
+	err = DB.SelectContext(ctx, &article_tags, "SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags")
+	if err != nil {
+		logger(ctx).Error("AllArticleTags error", "error", err)
+		return nil, err
+	}
+	return article_tags, nil
+}
+
+// ArticleTagCount get the count of all the ArticleTag records.
+func ArticleTagCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleTagCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM article_tags")
+	if err != nil {
+		logger(ctx).Error("ArticleTagCount error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ArticleTagCountWhere get the count of all the ArticleTag records with a where clause.
+func ArticleTagCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "ArticleTagCountWhere")
+	sql := "SELECT count(*) FROM article_tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleTagCountWhere error", "error", err)
+		return 0, err
+	}
+	err = stmt.GetContext(ctx, &c, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleTagCountWhere error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// ArticleTagIncludesWhere get the ArticleTag associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on ArticleTag model.
+func ArticleTagIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_article_tags []ArticleTag, err error) {
+	_article_tags, err = FindArticleTagsWhere(ctx, sql, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleTagIncludesWhere error", "error", err)
+		return nil, err
+	}
+	if len(assocs) == 0 {
+		logger(ctx).Warn("ArticleTagIncludesWhere without associated fields")
+		return _article_tags, err
+	}
+	if len(_article_tags) <= 0 {
+		return nil, errors.New("No results available")
+	}
+	ids := make([]interface{}, len(_article_tags))
+	for _, v := range _article_tags {
+		ids = append(ids, interface{}(v.Id))
+	}
+	return _article_tags, nil
+}
+
+// ArticleTagIds get all the IDs of ArticleTag records.
+func ArticleTagIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleTagIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM article_tags")
+	if err != nil {
+		logger(ctx).Error("ArticleTagIds error", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ArticleTagIdsWhere get all the IDs of ArticleTag records by where restriction.
+func ArticleTagIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := ArticleTagIntCol(ctx, "id", where, args...)
+	return ids, err
+}
+
+// ArticleTagIntCol get some int64 typed column of ArticleTag by where restriction.
+func ArticleTagIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "ArticleTagIntCol")
+	sql := "SELECT " + col + " FROM article_tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleTagIntCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleTagIntCol error", "error", err)
+		return nil, err
+	}
+	return intColRecs, nil
+}
+
+// ArticleTagStrCol get some string typed column of ArticleTag by where restriction.
+func ArticleTagStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "ArticleTagStrCol")
+	sql := "SELECT " + col + " FROM article_tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("ArticleTagStrCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("ArticleTagStrCol error", "error", err)
+		return nil, err
+	}
+	return strColRecs, nil
+}
+
+// FindArticleTagsWhere query use a partial SQL clause that usually following after WHERE
+// with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleTagsWhere(ctx context.Context, where string, args ...interface{}) (article_tags []ArticleTag, err error) {
+	ctx = observeQuery(ctx, "FindArticleTagsWhere")
+	sql := "SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleTagsWhere error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &article_tags, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleTagsWhere error", "error", err)
+		return nil, err
+	}
+	return article_tags, nil
+}
+
+// EachArticleTag iterate over the ArticleTag records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachArticleTag(ctx, "id > ?", []interface{}{100}, func(article_tag ArticleTag) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachArticleTag(ctx context.Context, where string, args []interface{}, fn func(ArticleTag) error) error {
+	ctx = observeQuery(ctx, "EachArticleTag")
+	sql := "SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachArticleTag error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_article_tag := ArticleTag{}
+		if err = rows.StructScan(&_article_tag); err != nil {
+			logger(ctx).Error("EachArticleTag error", "error", err)
+			return err
+		}
+		if err = fn(_article_tag); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ArticleTagsInBatches iterate over all the ArticleTag records in batches of batchSize,
+// see ArticleTagsInBatchesWhere.
+func ArticleTagsInBatches(ctx context.Context, batchSize int, fn func([]ArticleTag) error) error {
+	return ArticleTagsInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// ArticleTagsInBatchesWhere iterate over the ArticleTag records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func ArticleTagsInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]ArticleTag) error) error {
+	ctx = observeQuery(ctx, "ArticleTagsInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT article_tags.id, article_tags.article_id, article_tags.tag_id, article_tags.created_at, article_tags.updated_at FROM article_tags WHERE %sarticle_tags.id > ? ORDER BY article_tags.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("ArticleTagsInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_article_tags := []ArticleTag{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_article_tags, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("ArticleTagsInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_article_tags) == 0 {
+			return nil
+		}
+		lastId = _article_tags[len(_article_tags)-1].Id
+		if err = fn(_article_tags); err != nil {
+			return err
+		}
+		if len(_article_tags) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindArticleTagBySql query use a complete SQL clause
+// with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
+// will return only One record in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleTagBySql(ctx context.Context, sql string, args ...interface{}) (*ArticleTag, error) {
+	ctx = observeQuery(ctx, "FindArticleTagBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleTagBySql error", "error", err)
+		return nil, err
+	}
+	_article_tag := &ArticleTag{}
+	err = stmt.GetContext(ctx, _article_tag, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleTagBySql error", "error", err)
+		return nil, err
+	}
+	return _article_tag, nil
+}
+
+// FindArticleTagsBySql query use a complete SQL clause
+// with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindArticleTagsBySql(ctx context.Context, sql string, args ...interface{}) (article_tags []ArticleTag, err error) {
+	ctx = observeQuery(ctx, "FindArticleTagsBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindArticleTagsBySql error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &article_tags, args...)
+	if err != nil {
+		logger(ctx).Error("FindArticleTagsBySql error", "error", err)
+		return nil, err
+	}
+	return article_tags, nil
+}
+
+// CreateArticleTag use a named params to create a single ArticleTag record.
+// A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
+func CreateArticleTag(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateArticleTag")
+	if len(am) == 0 {
+		return 0, fmt.Errorf("Zero key in the attributes map!")
+	}
+	t := time.Now()
+	for _, v := range []string{"created_at", "updated_at"} {
+		if am[v] == nil {
+			am[v] = t
+		}
+	}
+	keys := allKeys(am)
+	sqlFmt := `INSERT INTO article_tags (%s) VALUES (%s)`
+	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
+	result, err := DB.NamedExecContext(ctx, sql, am)
+	if err != nil {
+		logger(ctx).Error("CreateArticleTag error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("CreateArticleTag error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+// Create is a method for ArticleTag to create a record.
+func (_article_tag *ArticleTag) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "ArticleTag.Create")
+	ok, err := govalidator.ValidateStruct(_article_tag)
+	if !ok {
+		errMsg := "Validate ArticleTag struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ArticleTag struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ArticleTag.Create error", "error", errMsg)
+		return 0, errors.New(errMsg)
+	}
+	t := time.Now()
+	_article_tag.CreatedAt = t
+	_article_tag.UpdatedAt = t
+    sql := `INSERT INTO article_tags (article_id,tag_id,created_at,updated_at) VALUES (:article_id,:tag_id,:created_at,:updated_at)`
+    result, err := DB.NamedExecContext(ctx, sql, _article_tag)
+	if err != nil {
+		logger(ctx).Error("ArticleTag.Create error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("ArticleTag.Create error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+
+
+// CreateArticle is a method for a ArticleTag object to create an associated Article record.
+func (_article_tag *ArticleTag) CreateArticle(ctx context.Context, am map[string]interface{}) error {
+	am["article_tag_id"] = _article_tag.Id
+	_, err := CreateArticle(ctx, am)
+	return err
+}
+
+// CreateTag is a method for a ArticleTag object to create an associated Tag record.
+func (_article_tag *ArticleTag) CreateTag(ctx context.Context, am map[string]interface{}) error {
+	am["article_tag_id"] = _article_tag.Id
+	_, err := CreateTag(ctx, am)
+	return err
+}
+
+
+// Destroy is method used for a ArticleTag object to be destroyed.
+func (_article_tag *ArticleTag) Destroy(ctx context.Context) error {
+	if _article_tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := DestroyArticleTag(ctx, _article_tag.Id)
+	return err
+}
+
+// DestroyArticleTag will destroy a ArticleTag record specified by the id parameter.
+func DestroyArticleTag(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyArticleTag")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM article_tags WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DestroyArticleTags will destroy ArticleTag records those specified by the ids parameters.
+func DestroyArticleTags(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticleTags")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("DestroyArticleTags error", "error", msg)
+		return 0, errors.New(msg)
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := fmt.Sprintf(`DELETE FROM article_tags WHERE id IN (?%s)`, idsHolder)
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DestroyArticleTagsWhere delete records by a where clause restriction.
+// e.g. DestroyArticleTagsWhere("name = ?", "John")
+// And this func will not call the association dependent action
+func DestroyArticleTagsWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyArticleTagsWhere")
+	sql := `DELETE FROM article_tags WHERE `
+	if len(where) > 0 {
+		sql = sql + where
+	} else {
+		return 0, errors.New("No WHERE conditions provided")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+
+// Save method is used for a ArticleTag object to update an existed record mainly.
+// If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
+func (_article_tag *ArticleTag) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "ArticleTag.Save")
+	ok, err := govalidator.ValidateStruct(_article_tag)
+	if !ok {
+		errMsg := "Validate ArticleTag struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate ArticleTag struct error: " + err.Error()
+		}
+		logger(ctx).Warn("ArticleTag.Save error", "error", errMsg)
+		return errors.New(errMsg)
+	}
+	if _article_tag.Id == 0 {
+		_, err = _article_tag.Create(ctx)
+		return err
+	}
+	_article_tag.UpdatedAt = time.Now()
+	sqlFmt := `UPDATE article_tags SET %s WHERE id = %v`
+	sqlStr := fmt.Sprintf(sqlFmt, "article_id = :article_id, tag_id = :tag_id, updated_at = :updated_at", _article_tag.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _article_tag)
+    return err
+}
+
+// UpdateArticleTag is used to update a record with a id and map[string]interface{} typed key-value parameters.
+func UpdateArticleTag(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateArticleTag")
+	if len(am) == 0 {
+		return errors.New("Zero key in the attributes map!")
+	}
+	am["updated_at"] = time.Now()
+	keys := allKeys(am)
+	sqlFmt := `UPDATE article_tags SET %s WHERE id = %v`
+	setKeysArr := []string{}
+	for _,v := range keys {
+		s := fmt.Sprintf(" %s = :%s", v, v)
+		setKeysArr = append(setKeysArr, s)
+	}
+	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
+	if err != nil {
+		logger(ctx).Error("UpdateArticleTag error", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Update is a method used to update a ArticleTag record with the map[string]interface{} typed key-value parameters.
+func (_article_tag *ArticleTag) Update(ctx context.Context, am map[string]interface{}) error {
+	if _article_tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleTag(ctx, _article_tag.Id, am)
+	return err
+}
+
+// UpdateAttributes method is supposed to be used to update ArticleTag records as corresponding update_attributes in Ruby on Rails.
+func (_article_tag *ArticleTag) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
+	if _article_tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleTag(ctx, _article_tag.Id, am)
+	return err
+}
+
+// UpdateColumns method is supposed to be used to update ArticleTag records as corresponding update_columns in Ruby on Rails.
+func (_article_tag *ArticleTag) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
+	if _article_tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateArticleTag(ctx, _article_tag.Id, am)
+	return err
+}
+
+// UpdateArticleTagsBySql is used to update ArticleTag records by a SQL clause
+// using the '?' binding syntax.
+func UpdateArticleTagsBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateArticleTagsBySql")
+	if sql == "" {
+		return 0, errors.New("A blank SQL clause")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
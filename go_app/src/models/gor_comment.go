@@ -4,9 +4,9 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"math"
 	"strings"
 	"time"
@@ -14,11 +14,6 @@ import (
 	"github.com/asaskevich/govalidator"
 )
 
-// set flags to output more detailed log
-func init() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-}
-
 type Comment struct {
 	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
 Commenter string `json:"commenter,omitempty" db:"commenter" valid:"required"`
@@ -26,7 +21,14 @@ Body string `json:"body,omitempty" db:"body" valid:"required,length(20|429496729
 ArticleId int64 `json:"article_id,omitempty" db:"article_id" valid:"-"`
 CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
 UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+UserId int64 `json:"user_id,omitempty" db:"user_id" valid:"-"`
+Status string `json:"status,omitempty" db:"status" valid:"in(pending|approved|rejected|spam)"`
+SpamScore int64 `json:"spam_score,omitempty" db:"spam_score" valid:"-"`
+ParentId int64 `json:"parent_id,omitempty" db:"parent_id" valid:"-"`
+BodyHtml string `json:"body_html,omitempty" db:"body_html" valid:"-"`
 Article Article `json:"article,omitempty" db:"article" valid:"-"`
+User User `json:"user,omitempty" db:"user" valid:"-"`
+Replies []Comment `json:"replies,omitempty" db:"replies" valid:"-"`
 }
 
 // DataStruct for the pagination
@@ -44,11 +46,11 @@ type CommentPage struct {
 }
 
 // Current get the current page of CommentPage object for pagination.
-func (_p *CommentPage) Current() ([]Comment, error) {
+func (_p *CommentPage) Current(ctx context.Context) ([]Comment, error) {
 	if _, exist := _p.Order["id"]; !exist {
 		return nil, errors.New("No id order specified in Order map")
 	}
-	err := _p.buildPageCount()
+	err := _p.buildPageCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Calculate page count error: %v", err)
 	}
@@ -59,7 +61,7 @@ func (_p *CommentPage) Current() ([]Comment, error) {
 	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
 	whereParams := []interface{}{}
 	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
-	comments, err := FindCommentsWhere(whereStr, whereParams...)
+	comments, err := FindCommentsWhere(ctx, whereStr, whereParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -70,14 +72,14 @@ func (_p *CommentPage) Current() ([]Comment, error) {
 }
 
 // Previous get the previous page of CommentPage object for pagination.
-func (_p *CommentPage) Previous() ([]Comment, error) {
+func (_p *CommentPage) Previous(ctx context.Context) ([]Comment, error) {
 	if _p.PageNum == 0 {
 		return nil, errors.New("This's the first page, no previous page yet")
 	}
 	if _, exist := _p.Order["id"]; !exist {
 		return nil, errors.New("No id order specified in Order map")
 	}
-	err := _p.buildPageCount()
+	err := _p.buildPageCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Calculate page count error: %v", err)
 	}
@@ -88,7 +90,7 @@ func (_p *CommentPage) Previous() ([]Comment, error) {
 	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
 	whereParams := []interface{}{}
 	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
-	comments, err := FindCommentsWhere(whereStr, whereParams...)
+	comments, err := FindCommentsWhere(ctx, whereStr, whereParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,14 +102,14 @@ func (_p *CommentPage) Previous() ([]Comment, error) {
 }
 
 // Next get the next page of CommentPage object for pagination.
-func (_p *CommentPage) Next() ([]Comment, error) {
+func (_p *CommentPage) Next(ctx context.Context) ([]Comment, error) {
 	if _p.PageNum == _p.TotalPages-1 {
 		return nil, errors.New("This's the last page, no next page yet")
 	}
 	if _, exist := _p.Order["id"]; !exist {
 		return nil, errors.New("No id order specified in Order map")
 	}
-	err := _p.buildPageCount()
+	err := _p.buildPageCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Calculate page count error: %v", err)
 	}
@@ -118,7 +120,7 @@ func (_p *CommentPage) Next() ([]Comment, error) {
 	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
 	whereParams := []interface{}{}
 	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
-	comments, err := FindCommentsWhere(whereStr, whereParams...)
+	comments, err := FindCommentsWhere(ctx, whereStr, whereParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -131,14 +133,14 @@ func (_p *CommentPage) Next() ([]Comment, error) {
 
 // GetPage is a helper function for the CommentPage object to return a corresponding page due to
 // the parameter passed in, i.e. one of "previous, current or next".
-func (_p *CommentPage) GetPage(direction string) (ps []Comment, err error) {
+func (_p *CommentPage) GetPage(ctx context.Context, direction string) (ps []Comment, err error) {
 	switch direction {
 	case "previous":
-		ps, _ = _p.Previous()
+		ps, _ = _p.Previous(ctx)
 	case "next":
-		ps, _ = _p.Next()
+		ps, _ = _p.Next(ctx)
 	case "current":
-		ps, _ = _p.Current()
+		ps, _ = _p.Current(ctx)
 	default:
 		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
 	}
@@ -196,8 +198,8 @@ func (_p *CommentPage) buildIdRestrict(direction string) (idStr string, idParams
 }
 
 // buildPageCount calculate the TotalItems/TotalPages for the CommentPage object.
-func (_p *CommentPage) buildPageCount() error {
-	count, err := CommentCountWhere(_p.WhereString, _p.WhereParams...)
+func (_p *CommentPage) buildPageCount(ctx context.Context) error {
+	count, err := CommentCountWhere(ctx, _p.WhereString, _p.WhereParams...)
 	if err != nil {
 		return err
 	}
@@ -211,160 +213,171 @@ func (_p *CommentPage) buildPageCount() error {
 
 
 // FindComment find a single comment by an ID.
-func FindComment(id int64) (*Comment, error) {
+func FindComment(ctx context.Context, id int64) (*Comment, error) {
+	ctx = observeQuery(ctx, "FindComment")
 	if id == 0 {
 		return nil, errors.New("Invalid ID: it can't be zero")
 	}
 	_comment := Comment{}
-	err := DB.Get(&_comment, DB.Rebind(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments WHERE comments.id = ? LIMIT 1`), id)
+	err := DB.GetContext(ctx, &_comment, DB.Rebind(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments WHERE comments.id = ? LIMIT 1`), id)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindComment error", "error", err)
 		return nil, err
 	}
 	return &_comment, nil
 }
 
 // FirstComment find the first one comment by ID ASC order.
-func FirstComment() (*Comment, error) {
+func FirstComment(ctx context.Context) (*Comment, error) {
+	ctx = observeQuery(ctx, "FirstComment")
 	_comment := Comment{}
-	err := DB.Get(&_comment, DB.Rebind(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments ORDER BY comments.id ASC LIMIT 1`))
+	err := DB.GetContext(ctx, &_comment, DB.Rebind(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments ORDER BY comments.id ASC LIMIT 1`))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FirstComment error", "error", err)
 		return nil, err
 	}
 	return &_comment, nil
 }
 
 // FirstComments find the first N comments by ID ASC order.
-func FirstComments(n uint32) ([]Comment, error) {
+func FirstComments(ctx context.Context, n uint32) ([]Comment, error) {
+	ctx = observeQuery(ctx, "FirstComments")
 	_comments := []Comment{}
-	sql := fmt.Sprintf("SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments ORDER BY comments.id ASC LIMIT %v", n)
-	err := DB.Select(&_comments, DB.Rebind(sql))
+	sql := fmt.Sprintf("SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments ORDER BY comments.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_comments, DB.Rebind(sql))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FirstComments error", "error", err)
 		return nil, err
 	}
 	return _comments, nil
 }
 
 // LastComment find the last one comment by ID DESC order.
-func LastComment() (*Comment, error) {
+func LastComment(ctx context.Context) (*Comment, error) {
+	ctx = observeQuery(ctx, "LastComment")
 	_comment := Comment{}
-	err := DB.Get(&_comment, DB.Rebind(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments ORDER BY comments.id DESC LIMIT 1`))
+	err := DB.GetContext(ctx, &_comment, DB.Rebind(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments ORDER BY comments.id DESC LIMIT 1`))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("LastComment error", "error", err)
 		return nil, err
 	}
 	return &_comment, nil
 }
 
 // LastComments find the last N comments by ID DESC order.
-func LastComments(n uint32) ([]Comment, error) {
+func LastComments(ctx context.Context, n uint32) ([]Comment, error) {
+	ctx = observeQuery(ctx, "LastComments")
 	_comments := []Comment{}
-	sql := fmt.Sprintf("SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments ORDER BY comments.id DESC LIMIT %v", n)
-	err := DB.Select(&_comments, DB.Rebind(sql))
+	sql := fmt.Sprintf("SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments ORDER BY comments.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_comments, DB.Rebind(sql))
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("LastComments error", "error", err)
 		return nil, err
 	}
 	return _comments, nil
 }
 
 // FindComments find one or more comments by the given ID(s).
-func FindComments(ids ...int64) ([]Comment, error) {
+func FindComments(ctx context.Context, ids ...int64) ([]Comment, error) {
+	ctx = observeQuery(ctx, "FindComments")
 	if len(ids) == 0 {
 		msg := "At least one or more ids needed"
-		log.Println(msg)
+		logger(ctx).Warn("FindComments error", "error", msg)
 		return nil, errors.New(msg)
 	}
 	_comments := []Comment{}
 	idsHolder := strings.Repeat(",?", len(ids)-1)
-	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments WHERE comments.id IN (?%s)`, idsHolder))
+	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments WHERE comments.id IN (?%s)`, idsHolder))
 	idsT := []interface{}{}
 	for _,id := range ids {
 		idsT = append(idsT, interface{}(id))
 	}
-	err := DB.Select(&_comments, sql, idsT...)
+	err := DB.SelectContext(ctx, &_comments, sql, idsT...)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindComments error", "error", err)
 		return nil, err
 	}
 	return _comments, nil
 }
 
 // FindCommentBy find a single comment by a field name and a value.
-func FindCommentBy(field string, val interface{}) (*Comment, error) {
+func FindCommentBy(ctx context.Context, field string, val interface{}) (*Comment, error) {
+	ctx = observeQuery(ctx, "FindCommentBy")
 	_comment := Comment{}
-	sqlFmt := `SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments WHERE %s = ? LIMIT 1`
+	sqlFmt := `SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments WHERE %s = ? LIMIT 1`
 	sqlStr := fmt.Sprintf(sqlFmt, field)
-	err := DB.Get(&_comment, DB.Rebind(sqlStr), val)
+	err := DB.GetContext(ctx, &_comment, DB.Rebind(sqlStr), val)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindCommentBy error", "error", err)
 		return nil, err
 	}
 	return &_comment, nil
 }
 
 // FindCommentsBy find all comments by a field name and a value.
-func FindCommentsBy(field string, val interface{}) (_comments []Comment, err error) {
-	sqlFmt := `SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments WHERE %s = ?`
+func FindCommentsBy(ctx context.Context, field string, val interface{}) (_comments []Comment, err error) {
+	ctx = observeQuery(ctx, "FindCommentsBy")
+	sqlFmt := `SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments WHERE %s = ?`
 	sqlStr := fmt.Sprintf(sqlFmt, field)
-	err = DB.Select(&_comments, DB.Rebind(sqlStr), val)
+	err = DB.SelectContext(ctx, &_comments, DB.Rebind(sqlStr), val)
 	if err != nil {
-		log.Printf("Error: %v\n", err)
+		logger(ctx).Error("FindCommentsBy error", "error", err)
 		return nil, err
 	}
 	return _comments, nil
 }
 
 // AllComments get all the Comment records.
-func AllComments() (comments []Comment, err error) {
-	err = DB.Select(&comments, "SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments")
+func AllComments(ctx context.Context) (comments []Comment, err error) {
+	ctx = observeQuery(ctx, "AllComments")
+	err = DB.SelectContext(ctx, &comments, "SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments")
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("AllComments error", "error", err)
 		return nil, err
 	}
 	return comments, nil
 }
 
 // CommentCount get the count of all the Comment records.
-func CommentCount() (c int64, err error) {
-	err = DB.Get(&c, "SELECT count(*) FROM comments")
+func CommentCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "CommentCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM comments")
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentCount error", "error", err)
 		return 0, err
 	}
 	return c, nil
 }
 
 // CommentCountWhere get the count of all the Comment records with a where clause.
-func CommentCountWhere(where string, args ...interface{}) (c int64, err error) {
+func CommentCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "CommentCountWhere")
 	sql := "SELECT count(*) FROM comments"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentCountWhere error", "error", err)
 		return 0, err
 	}
-	err = stmt.Get(&c, args...)
+	err = stmt.GetContext(ctx, &c, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentCountWhere error", "error", err)
 		return 0, err
 	}
 	return c, nil
 }
 
 // CommentIncludesWhere get the Comment associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on Comment model.
-func CommentIncludesWhere(assocs []string, sql string, args ...interface{}) (_comments []Comment, err error) {
-	_comments, err = FindCommentsWhere(sql, args...)
+func CommentIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_comments []Comment, err error) {
+	_comments, err = FindCommentsWhere(ctx, sql, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentIncludesWhere error", "error", err)
 		return nil, err
 	}
 	if len(assocs) == 0 {
-		log.Println("No associated fields ard specified")
+		logger(ctx).Warn("CommentIncludesWhere without associated fields")
 		return _comments, err
 	}
 	if len(_comments) <= 0 {
@@ -374,58 +387,81 @@ func CommentIncludesWhere(assocs []string, sql string, args ...interface{}) (_co
 	for _, v := range _comments {
 		ids = append(ids, interface{}(v.Id))
 	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	for _, assoc := range assocs {
+		switch assoc {
+				case "replies":
+							where := fmt.Sprintf("parent_id IN (?%s)", idsHolder)
+						_replies, err := FindCommentsWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("CommentIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _replies {
+							for i, vvv := range  _comments {
+									if vv.ParentId == vvv.Id {
+										vvv.Replies = append(vvv.Replies, vv)
+									}
+								_comments[i].Replies = vvv.Replies
+						    }
+					    }
+		}
+	}
 	return _comments, nil
 }
 
 // CommentIds get all the IDs of Comment records.
-func CommentIds() (ids []int64, err error) {
-	err = DB.Select(&ids, "SELECT id FROM comments")
+func CommentIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "CommentIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM comments")
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentIds error", "error", err)
 		return nil, err
 	}
 	return ids, nil
 }
 
 // CommentIdsWhere get all the IDs of Comment records by where restriction.
-func CommentIdsWhere(where string, args ...interface{}) ([]int64, error) {
-	ids, err := CommentIntCol("id", where, args...)
+func CommentIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := CommentIntCol(ctx, "id", where, args...)
 	return ids, err
 }
 
 // CommentIntCol get some int64 typed column of Comment by where restriction.
-func CommentIntCol(col, where string, args ...interface{}) (intColRecs []int64, err error) {
+func CommentIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "CommentIntCol")
 	sql := "SELECT " + col + " FROM comments"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentIntCol error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&intColRecs, args...)
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentIntCol error", "error", err)
 		return nil, err
 	}
 	return intColRecs, nil
 }
 
 // CommentStrCol get some string typed column of Comment by where restriction.
-func CommentStrCol(col, where string, args ...interface{}) (strColRecs []string, err error) {
+func CommentStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "CommentStrCol")
 	sql := "SELECT " + col + " FROM comments"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentStrCol error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&strColRecs, args...)
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CommentStrCol error", "error", err)
 		return nil, err
 	}
 	return strColRecs, nil
@@ -434,37 +470,118 @@ func CommentStrCol(col, where string, args ...interface{}) (strColRecs []string,
 // FindCommentsWhere query use a partial SQL clause that usually following after WHERE
 // with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
 // will return those records in the table "users" whose first_name is "John" and age elder than 18.
-func FindCommentsWhere(where string, args ...interface{}) (comments []Comment, err error) {
-	sql := "SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, comments.id, comments.commenter, comments.created_at, comments.updated_at FROM comments"
+func FindCommentsWhere(ctx context.Context, where string, args ...interface{}) (comments []Comment, err error) {
+	ctx = observeQuery(ctx, "FindCommentsWhere")
+	sql := "SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments"
 	if len(where) > 0 {
 		sql = sql + " WHERE " + where
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindCommentsWhere error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&comments, args...)
+	err = stmt.SelectContext(ctx, &comments, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindCommentsWhere error", "error", err)
 		return nil, err
 	}
 	return comments, nil
 }
 
+// EachComment iterate over the Comment records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachComment(ctx, "id > ?", []interface{}{100}, func(comment Comment) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachComment(ctx context.Context, where string, args []interface{}, fn func(Comment) error) error {
+	ctx = observeQuery(ctx, "EachComment")
+	sql := "SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachComment error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_comment := Comment{}
+		if err = rows.StructScan(&_comment); err != nil {
+			logger(ctx).Error("EachComment error", "error", err)
+			return err
+		}
+		if err = fn(_comment); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CommentsInBatches iterate over all the Comment records in batches of batchSize,
+// see CommentsInBatchesWhere.
+func CommentsInBatches(ctx context.Context, batchSize int, fn func([]Comment) error) error {
+	return CommentsInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// CommentsInBatchesWhere iterate over the Comment records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func CommentsInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]Comment) error) error {
+	ctx = observeQuery(ctx, "CommentsInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT COALESCE(comments.body, '') AS body, COALESCE(comments.article_id, 0) AS article_id, COALESCE(comments.user_id, 0) AS user_id, COALESCE(comments.parent_id, 0) AS parent_id, comments.id, comments.commenter, comments.created_at, comments.updated_at, comments.status, comments.spam_score, COALESCE(comments.body_html, '') AS body_html FROM comments WHERE %scomments.id > ? ORDER BY comments.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("CommentsInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_comments := []Comment{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_comments, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("CommentsInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_comments) == 0 {
+			return nil
+		}
+		lastId = _comments[len(_comments)-1].Id
+		if err = fn(_comments); err != nil {
+			return err
+		}
+		if len(_comments) < batchSize {
+			return nil
+		}
+	}
+}
+
 // FindCommentBySql query use a complete SQL clause
 // with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
 // will return only One record in the table "users" whose first_name is "John" and age elder than 18.
-func FindCommentBySql(sql string, args ...interface{}) (*Comment, error) {
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+func FindCommentBySql(ctx context.Context, sql string, args ...interface{}) (*Comment, error) {
+	ctx = observeQuery(ctx, "FindCommentBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindCommentBySql error", "error", err)
 		return nil, err
 	}
 	_comment := &Comment{}
-	err = stmt.Get(_comment, args...)
+	err = stmt.GetContext(ctx, _comment, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindCommentBySql error", "error", err)
 		return nil, err
 	}
 	return _comment, nil
@@ -473,15 +590,16 @@ func FindCommentBySql(sql string, args ...interface{}) (*Comment, error) {
 // FindCommentsBySql query use a complete SQL clause
 // with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
 // will return those records in the table "users" whose first_name is "John" and age elder than 18.
-func FindCommentsBySql(sql string, args ...interface{}) (comments []Comment, err error) {
-	stmt, err := DB.Preparex(DB.Rebind(sql))
+func FindCommentsBySql(ctx context.Context, sql string, args ...interface{}) (comments []Comment, err error) {
+	ctx = observeQuery(ctx, "FindCommentsBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindCommentsBySql error", "error", err)
 		return nil, err
 	}
-	err = stmt.Select(&comments, args...)
+	err = stmt.SelectContext(ctx, &comments, args...)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("FindCommentsBySql error", "error", err)
 		return nil, err
 	}
 	return comments, nil
@@ -489,7 +607,8 @@ func FindCommentsBySql(sql string, args ...interface{}) (comments []Comment, err
 
 // CreateComment use a named params to create a single Comment record.
 // A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
-func CreateComment(am map[string]interface{}) (int64, error) {
+func CreateComment(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateComment")
 	if len(am) == 0 {
 		return 0, fmt.Errorf("Zero key in the attributes map!")
 	}
@@ -502,80 +621,121 @@ func CreateComment(am map[string]interface{}) (int64, error) {
 	keys := allKeys(am)
 	sqlFmt := `INSERT INTO comments (%s) VALUES (%s)`
 	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
-	result, err := DB.NamedExec(sql, am)
+	result, err := DB.NamedExecContext(ctx, sql, am)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CreateComment error", "error", err)
 		return 0, err
 	}
 	lastId, err := result.LastInsertId()
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("CreateComment error", "error", err)
 		return 0, err
 	}
+	afterWrite(ctx, "comments", Created, nil, lastId)
 	return lastId, nil
 }
 
 // Create is a method for Comment to create a record.
-func (_comment *Comment) Create() (int64, error) {
+func (_comment *Comment) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "Comment.Create")
 	ok, err := govalidator.ValidateStruct(_comment)
 	if !ok {
 		errMsg := "Validate Comment struct error: Unknown error"
 		if err != nil {
 			errMsg = "Validate Comment struct error: " + err.Error()
 		}
-		log.Println(errMsg)
+		logger(ctx).Warn("Comment.Create error", "error", errMsg)
 		return 0, errors.New(errMsg)
 	}
 	t := time.Now()
 	_comment.CreatedAt = t
 	_comment.UpdatedAt = t
-    sql := `INSERT INTO comments (commenter,body,article_id,created_at,updated_at) VALUES (:commenter,:body,:article_id,:created_at,:updated_at)`
-    result, err := DB.NamedExec(sql, _comment)
+    sql := `INSERT INTO comments (commenter,body,article_id,created_at,updated_at,user_id,status,spam_score,parent_id) VALUES (:commenter,:body,:article_id,:created_at,:updated_at,NULLIF(:user_id, 0),COALESCE(NULLIF(:status, ''), 'pending'),:spam_score,NULLIF(:parent_id, 0))`
+    result, err := DB.NamedExecContext(ctx, sql, _comment)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("Comment.Create error", "error", err)
 		return 0, err
 	}
 	lastId, err := result.LastInsertId()
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("Comment.Create error", "error", err)
 		return 0, err
 	}
+	afterWrite(ctx, "comments", Created, nil, lastId)
 	return lastId, nil
 }
 
 
 
 // CreateArticle is a method for a Comment object to create an associated Article record.
-func (_comment *Comment) CreateArticle(am map[string]interface{}) error {
+func (_comment *Comment) CreateArticle(ctx context.Context, am map[string]interface{}) error {
 	am["comment_id"] = _comment.Id
-	_, err := CreateArticle(am)
+	_, err := CreateArticle(ctx, am)
 	return err
 }
 
+// CreateUser is a method for a Comment object to create an associated User record.
+func (_comment *Comment) CreateUser(ctx context.Context, am map[string]interface{}) error {
+	am["comment_id"] = _comment.Id
+	_, err := CreateUser(ctx, am)
+	return err
+}
+
+// RepliesCreate is used for Comment to create the associated objects Replies
+func (_comment *Comment) RepliesCreate(ctx context.Context, am map[string]interface{}) error {
+			am["parent_id"] = _comment.Id
+		_, err := CreateComment(ctx, am)
+	return err
+}
+
+// GetReplies is used for Comment to get associated objects Replies
+// Say you have a Comment object named comment, when you call comment.GetReplies(),
+// the object will get the associated Replies attributes evaluated in the struct.
+func (_comment *Comment) GetReplies(ctx context.Context) error {
+	_replies, err := CommentGetReplies(ctx, _comment.Id)
+	if err == nil {
+		_comment.Replies = _replies
+    }
+    return err
+}
+
+// CommentGetReplies a helper fuction used to get associated objects for CommentIncludesWhere().
+func CommentGetReplies(ctx context.Context, id int64) ([]Comment, error) {
+			_replies, err := FindCommentsBy(ctx, "parent_id", id)
+	return _replies, err
+}
+
+
+
+
 // Destroy is method used for a Comment object to be destroyed.
-func (_comment *Comment) Destroy() error {
+func (_comment *Comment) Destroy(ctx context.Context) error {
 	if _comment.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := DestroyComment(_comment.Id)
+	err := DestroyComment(ctx, _comment.Id)
 	return err
 }
 
 // DestroyComment will destroy a Comment record specified by the id parameter.
-func DestroyComment(id int64) error {
-	stmt, err := DB.Preparex(DB.Rebind(`DELETE FROM comments WHERE id = ?`))
-	_, err = stmt.Exec(id)
+func DestroyComment(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyComment")
+	before := beforeWrite(ctx, "comments", id)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM comments WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
 	if err != nil {
 		return err
 	}
+	afterWrite(ctx, "comments", Destroyed, before, id)
 	return nil
 }
 
 // DestroyComments will destroy Comment records those specified by the ids parameters.
-func DestroyComments(ids ...int64) (int64, error) {
+func DestroyComments(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyComments")
 	if len(ids) == 0 {
 		msg := "At least one or more ids needed"
-		log.Println(msg)
+		logger(ctx).Warn("DestroyComments error", "error", msg)
 		return 0, errors.New(msg)
 	}
 	idsHolder := strings.Repeat(",?", len(ids)-1)
@@ -584,11 +744,13 @@ func DestroyComments(ids ...int64) (int64, error) {
 	for _,id := range ids {
 		idsT = append(idsT, interface{}(id))
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
-	result, err := stmt.Exec(idsT...)
+	before := beforeWrite(ctx, "comments", ids...)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
 	if err != nil {
 		return 0, err
 	}
+	afterWrite(ctx, "comments", Destroyed, before, ids...)
 	cnt, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
@@ -599,18 +761,25 @@ func DestroyComments(ids ...int64) (int64, error) {
 // DestroyCommentsWhere delete records by a where clause restriction.
 // e.g. DestroyCommentsWhere("name = ?", "John")
 // And this func will not call the association dependent action
-func DestroyCommentsWhere(where string, args ...interface{}) (int64, error) {
+func DestroyCommentsWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyCommentsWhere")
 	sql := `DELETE FROM comments WHERE `
 	if len(where) > 0 {
 		sql = sql + where
 	} else {
 		return 0, errors.New("No WHERE conditions provided")
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
-	result, err := stmt.Exec(args...)
+	var ids []int64
+	if hooked("comments") {
+		ids, _ = CommentIdsWhere(ctx, where, args...)
+	}
+	before := beforeWrite(ctx, "comments", ids...)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return 0, err
 	}
+	afterWrite(ctx, "comments", Destroyed, before, ids...)
 	cnt, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
@@ -621,33 +790,42 @@ func DestroyCommentsWhere(where string, args ...interface{}) (int64, error) {
 
 // Save method is used for a Comment object to update an existed record mainly.
 // If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
-func (_comment *Comment) Save() error {
+func (_comment *Comment) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "Comment.Save")
 	ok, err := govalidator.ValidateStruct(_comment)
 	if !ok {
 		errMsg := "Validate Comment struct error: Unknown error"
 		if err != nil {
 			errMsg = "Validate Comment struct error: " + err.Error()
 		}
-		log.Println(errMsg)
+		logger(ctx).Warn("Comment.Save error", "error", errMsg)
 		return errors.New(errMsg)
 	}
 	if _comment.Id == 0 {
-		_, err = _comment.Create()
+		_, err = _comment.Create(ctx)
 		return err
 	}
 	_comment.UpdatedAt = time.Now()
 	sqlFmt := `UPDATE comments SET %s WHERE id = %v`
-	sqlStr := fmt.Sprintf(sqlFmt, "commenter = :commenter, body = :body, article_id = :article_id, updated_at = :updated_at", _comment.Id)
-    _, err = DB.NamedExec(sqlStr, _comment)
+	sqlStr := fmt.Sprintf(sqlFmt, "body_html = CASE WHEN body = :body THEN body_html END, commenter = :commenter, body = :body, article_id = :article_id, updated_at = :updated_at, user_id = NULLIF(:user_id, 0), status = COALESCE(NULLIF(:status, ''), status), spam_score = :spam_score, parent_id = NULLIF(:parent_id, 0)", _comment.Id)
+	before := beforeWrite(ctx, "comments", _comment.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _comment)
+    if err == nil {
+		afterWrite(ctx, "comments", Updated, before, _comment.Id)
+	}
     return err
 }
 
 // UpdateComment is used to update a record with a id and map[string]interface{} typed key-value parameters.
-func UpdateComment(id int64, am map[string]interface{}) error {
+func UpdateComment(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateComment")
 	if len(am) == 0 {
 		return errors.New("Zero key in the attributes map!")
 	}
 	am["updated_at"] = time.Now()
+	if _, ok := am["body"]; ok {
+		am["body_html"] = nil
+	}
 	keys := allKeys(am)
 	sqlFmt := `UPDATE comments SET %s WHERE id = %v`
 	setKeysArr := []string{}
@@ -656,49 +834,52 @@ func UpdateComment(id int64, am map[string]interface{}) error {
 		setKeysArr = append(setKeysArr, s)
 	}
 	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
-	_, err := DB.NamedExec(sqlStr, am)
+	before := beforeWrite(ctx, "comments", id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
 	if err != nil {
-		log.Println(err)
+		logger(ctx).Error("UpdateComment error", "error", err)
 		return err
 	}
+	afterWrite(ctx, "comments", Updated, before, id)
 	return nil
 }
 
 // Update is a method used to update a Comment record with the map[string]interface{} typed key-value parameters.
-func (_comment *Comment) Update(am map[string]interface{}) error {
+func (_comment *Comment) Update(ctx context.Context, am map[string]interface{}) error {
 	if _comment.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := UpdateComment(_comment.Id, am)
+	err := UpdateComment(ctx, _comment.Id, am)
 	return err
 }
 
 // UpdateAttributes method is supposed to be used to update Comment records as corresponding update_attributes in Ruby on Rails.
-func (_comment *Comment) UpdateAttributes(am map[string]interface{}) error {
+func (_comment *Comment) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
 	if _comment.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := UpdateComment(_comment.Id, am)
+	err := UpdateComment(ctx, _comment.Id, am)
 	return err
 }
 
 // UpdateColumns method is supposed to be used to update Comment records as corresponding update_columns in Ruby on Rails.
-func (_comment *Comment) UpdateColumns(am map[string]interface{}) error {
+func (_comment *Comment) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
 	if _comment.Id == 0 {
 		return errors.New("Invalid Id field: it can't be a zero value")
 	}
-	err := UpdateComment(_comment.Id, am)
+	err := UpdateComment(ctx, _comment.Id, am)
 	return err
 }
 
 // UpdateCommentsBySql is used to update Comment records by a SQL clause
 // using the '?' binding syntax.
-func UpdateCommentsBySql(sql string, args ...interface{}) (int64, error) {
+func UpdateCommentsBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateCommentsBySql")
 	if sql == "" {
 		return 0, errors.New("A blank SQL clause")
 	}
-	stmt, err := DB.Preparex(DB.Rebind(sql))
-	result, err := stmt.Exec(args...)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return 0, err
 	}
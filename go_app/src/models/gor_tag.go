@@ -0,0 +1,898 @@
+
+
+// Package models includes the functions on the model Tag.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type Tag struct {
+	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
+Name string `json:"name,omitempty" db:"name" valid:"required,length(1|64)"`
+CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
+UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+ArticleTags []ArticleTag `json:"article_tags,omitempty" db:"article_tags" valid:"-"`
+Articles []Article `json:"articles,omitempty" db:"articles" valid:"-"`
+}
+
+// DataStruct for the pagination
+type TagPage struct {
+	WhereString string
+	WhereParams []interface{}
+	Order       map[string]string
+	FirstId     int64
+	LastId      int64
+	PageNum     int
+	PerPage     int
+	TotalPages  int
+	TotalItems  int64
+	orderStr    string
+}
+
+// Current get the current page of TagPage object for pagination.
+func (_p *TagPage) Current(ctx context.Context) ([]Tag, error) {
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("current")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	tags, err := FindTagsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != 0 {
+		_p.FirstId, _p.LastId = tags[0].Id, tags[len(tags)-1].Id
+	}
+	return tags, nil
+}
+
+// Previous get the previous page of TagPage object for pagination.
+func (_p *TagPage) Previous(ctx context.Context) ([]Tag, error) {
+	if _p.PageNum == 0 {
+		return nil, errors.New("This's the first page, no previous page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("previous")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	tags, err := FindTagsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != 0 {
+		_p.FirstId, _p.LastId = tags[0].Id, tags[len(tags)-1].Id
+	}
+	_p.PageNum -= 1
+	return tags, nil
+}
+
+// Next get the next page of TagPage object for pagination.
+func (_p *TagPage) Next(ctx context.Context) ([]Tag, error) {
+	if _p.PageNum == _p.TotalPages-1 {
+		return nil, errors.New("This's the last page, no next page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("next")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	tags, err := FindTagsWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != 0 {
+		_p.FirstId, _p.LastId = tags[0].Id, tags[len(tags)-1].Id
+	}
+	_p.PageNum += 1
+	return tags, nil
+}
+
+// GetPage is a helper function for the TagPage object to return a corresponding page due to
+// the parameter passed in, i.e. one of "previous, current or next".
+func (_p *TagPage) GetPage(ctx context.Context, direction string) (ps []Tag, err error) {
+	switch direction {
+	case "previous":
+		ps, _ = _p.Previous(ctx)
+	case "next":
+		ps, _ = _p.Next(ctx)
+	case "current":
+		ps, _ = _p.Current(ctx)
+	default:
+		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
+	}
+	return
+}
+
+// buildOrder is for TagPage object to build a SQL ORDER BY clause.
+func (_p *TagPage) buildOrder() {
+	tempList := []string{}
+	for k, v := range _p.Order {
+		tempList = append(tempList, fmt.Sprintf("%v %v", k, v))
+	}
+	_p.orderStr = " ORDER BY " + strings.Join(tempList, ", ")
+}
+
+// buildIdRestrict is for TagPage object to build a SQL clause for ID restriction,
+// implementing a simple keyset style pagination.
+func (_p *TagPage) buildIdRestrict(direction string) (idStr string, idParams []interface{}) {
+	switch direction {
+	case "previous":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.FirstId)
+		} else {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.FirstId)
+		}
+	case "current":
+		// trick to make Where function work
+		if _p.PageNum == 0 && _p.FirstId == 0 && _p.LastId == 0 {
+			idStr += "id > ? "
+			idParams = append(idParams, 0)
+		} else {
+			if strings.ToLower(_p.Order["id"]) == "desc" {
+				idStr += "id <= ? AND id >= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			} else {
+				idStr += "id >= ? AND id <= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			}
+		}
+	case "next":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.LastId)
+		} else {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.LastId)
+		}
+	}
+	if _p.WhereString != "" {
+		idStr = " AND " + idStr
+	}
+	return
+}
+
+// buildPageCount calculate the TotalItems/TotalPages for the TagPage object.
+func (_p *TagPage) buildPageCount(ctx context.Context) error {
+	count, err := TagCountWhere(ctx, _p.WhereString, _p.WhereParams...)
+	if err != nil {
+		return err
+	}
+	_p.TotalItems = count
+	if _p.PerPage == 0 {
+		_p.PerPage = 10
+	}
+	_p.TotalPages = int(math.Ceil(float64(_p.TotalItems) / float64(_p.PerPage)))
+	return nil
+}
+
+
+// FindTag find a single tag by an ID.
+func FindTag(ctx context.Context, id int64) (*Tag, error) {
+	ctx = observeQuery(ctx, "FindTag")
+	if id == 0 {
+		return nil, errors.New("Invalid ID: it can't be zero")
+	}
+	_tag := Tag{}
+	err := DB.GetContext(ctx, &_tag, DB.Rebind(`SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags WHERE tags.id = ? LIMIT 1`), id)
+	if err != nil {
+		logger(ctx).Error("FindTag error", "error", err)
+		return nil, err
+	}
+	return &_tag, nil
+}
+
+// FirstTag find the first one tag by ID ASC order.
+func FirstTag(ctx context.Context) (*Tag, error) {
+	ctx = observeQuery(ctx, "FirstTag")
+	_tag := Tag{}
+	err := DB.GetContext(ctx, &_tag, DB.Rebind(`SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags ORDER BY tags.id ASC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("FirstTag error", "error", err)
+		return nil, err
+	}
+	return &_tag, nil
+}
+
+// FirstTags find the first N tags by ID ASC order.
+func FirstTags(ctx context.Context, n uint32) ([]Tag, error) {
+	ctx = observeQuery(ctx, "FirstTags")
+	_tags := []Tag{}
+	sql := fmt.Sprintf("SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags ORDER BY tags.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_tags, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FirstTags error", "error", err)
+		return nil, err
+	}
+	return _tags, nil
+}
+
+// LastTag find the last one tag by ID DESC order.
+func LastTag(ctx context.Context) (*Tag, error) {
+	ctx = observeQuery(ctx, "LastTag")
+	_tag := Tag{}
+	err := DB.GetContext(ctx, &_tag, DB.Rebind(`SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags ORDER BY tags.id DESC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("LastTag error", "error", err)
+		return nil, err
+	}
+	return &_tag, nil
+}
+
+// LastTags find the last N tags by ID DESC order.
+func LastTags(ctx context.Context, n uint32) ([]Tag, error) {
+	ctx = observeQuery(ctx, "LastTags")
+	_tags := []Tag{}
+	sql := fmt.Sprintf("SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags ORDER BY tags.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_tags, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("LastTags error", "error", err)
+		return nil, err
+	}
+	return _tags, nil
+}
+
+// FindTags find one or more tags by the given ID(s).
+func FindTags(ctx context.Context, ids ...int64) ([]Tag, error) {
+	ctx = observeQuery(ctx, "FindTags")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("FindTags error", "error", msg)
+		return nil, errors.New(msg)
+	}
+	_tags := []Tag{}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := DB.Rebind(fmt.Sprintf(`SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags WHERE tags.id IN (?%s)`, idsHolder))
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	err := DB.SelectContext(ctx, &_tags, sql, idsT...)
+	if err != nil {
+		logger(ctx).Error("FindTags error", "error", err)
+		return nil, err
+	}
+	return _tags, nil
+}
+
+// FindTagBy find a single tag by a field name and a value.
+func FindTagBy(ctx context.Context, field string, val interface{}) (*Tag, error) {
+	ctx = observeQuery(ctx, "FindTagBy")
+	_tag := Tag{}
+	sqlFmt := `SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags WHERE %s = ? LIMIT 1`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err := DB.GetContext(ctx, &_tag, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindTagBy error", "error", err)
+		return nil, err
+	}
+	return &_tag, nil
+}
+
+// FindTagsBy find all tags by a field name and a value.
+func FindTagsBy(ctx context.Context, field string, val interface{}) (_tags []Tag, err error) {
+	ctx = observeQuery(ctx, "FindTagsBy")
+	sqlFmt := `SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags WHERE %s = ?`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err = DB.SelectContext(ctx, &_tags, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindTagsBy error", "error", err)
+		return nil, err
+	}
+	return _tags, nil
+}
+
+// AllTags get all the Tag records.
+func AllTags(ctx context.Context) (tags []Tag, err error) {
+	ctx = observeQuery(ctx, "AllTags")
+	err = DB.SelectContext(ctx, &tags, "SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags")
+	if err != nil {
+		logger(ctx).Error("AllTags error", "error", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// TagCount get the count of all the Tag records.
+func TagCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "TagCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM tags")
+	if err != nil {
+		logger(ctx).Error("TagCount error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// TagCountWhere get the count of all the Tag records with a where clause.
+func TagCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "TagCountWhere")
+	sql := "SELECT count(*) FROM tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("TagCountWhere error", "error", err)
+		return 0, err
+	}
+	err = stmt.GetContext(ctx, &c, args...)
+	if err != nil {
+		logger(ctx).Error("TagCountWhere error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// TagIncludesWhere get the Tag associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on Tag model.
+func TagIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_tags []Tag, err error) {
+	_tags, err = FindTagsWhere(ctx, sql, args...)
+	if err != nil {
+		logger(ctx).Error("TagIncludesWhere error", "error", err)
+		return nil, err
+	}
+	if len(assocs) == 0 {
+		logger(ctx).Warn("TagIncludesWhere without associated fields")
+		return _tags, err
+	}
+	if len(_tags) <= 0 {
+		return nil, errors.New("No results available")
+	}
+	ids := make([]interface{}, len(_tags))
+	for _, v := range _tags {
+		ids = append(ids, interface{}(v.Id))
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	for _, assoc := range assocs {
+		switch assoc {
+				case "article_tags":
+							where := fmt.Sprintf("tag_id IN (?%s)", idsHolder)
+						_article_tags, err := FindArticleTagsWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("TagIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _article_tags {
+							for i, vvv := range  _tags {
+									if vv.TagId == vvv.Id {
+										vvv.ArticleTags = append(vvv.ArticleTags, vv)
+									}
+								_tags[i].ArticleTags = vvv.ArticleTags
+						    }
+					    }
+		}
+	}
+	return _tags, nil
+}
+
+// TagIds get all the IDs of Tag records.
+func TagIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "TagIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM tags")
+	if err != nil {
+		logger(ctx).Error("TagIds error", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// TagIdsWhere get all the IDs of Tag records by where restriction.
+func TagIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := TagIntCol(ctx, "id", where, args...)
+	return ids, err
+}
+
+// TagIntCol get some int64 typed column of Tag by where restriction.
+func TagIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "TagIntCol")
+	sql := "SELECT " + col + " FROM tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("TagIntCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("TagIntCol error", "error", err)
+		return nil, err
+	}
+	return intColRecs, nil
+}
+
+// TagStrCol get some string typed column of Tag by where restriction.
+func TagStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "TagStrCol")
+	sql := "SELECT " + col + " FROM tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("TagStrCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("TagStrCol error", "error", err)
+		return nil, err
+	}
+	return strColRecs, nil
+}
+
+// FindTagsWhere query use a partial SQL clause that usually following after WHERE
+// with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindTagsWhere(ctx context.Context, where string, args ...interface{}) (tags []Tag, err error) {
+	ctx = observeQuery(ctx, "FindTagsWhere")
+	sql := "SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindTagsWhere error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &tags, args...)
+	if err != nil {
+		logger(ctx).Error("FindTagsWhere error", "error", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// EachTag iterate over the Tag records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachTag(ctx, "id > ?", []interface{}{100}, func(tag Tag) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachTag(ctx context.Context, where string, args []interface{}, fn func(Tag) error) error {
+	ctx = observeQuery(ctx, "EachTag")
+	sql := "SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachTag error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_tag := Tag{}
+		if err = rows.StructScan(&_tag); err != nil {
+			logger(ctx).Error("EachTag error", "error", err)
+			return err
+		}
+		if err = fn(_tag); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// TagsInBatches iterate over all the Tag records in batches of batchSize,
+// see TagsInBatchesWhere.
+func TagsInBatches(ctx context.Context, batchSize int, fn func([]Tag) error) error {
+	return TagsInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// TagsInBatchesWhere iterate over the Tag records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func TagsInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]Tag) error) error {
+	ctx = observeQuery(ctx, "TagsInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT tags.id, tags.name, tags.created_at, tags.updated_at FROM tags WHERE %stags.id > ? ORDER BY tags.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("TagsInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_tags := []Tag{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_tags, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("TagsInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_tags) == 0 {
+			return nil
+		}
+		lastId = _tags[len(_tags)-1].Id
+		if err = fn(_tags); err != nil {
+			return err
+		}
+		if len(_tags) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindTagBySql query use a complete SQL clause
+// with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
+// will return only One record in the table "users" whose first_name is "John" and age elder than 18.
+func FindTagBySql(ctx context.Context, sql string, args ...interface{}) (*Tag, error) {
+	ctx = observeQuery(ctx, "FindTagBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindTagBySql error", "error", err)
+		return nil, err
+	}
+	_tag := &Tag{}
+	err = stmt.GetContext(ctx, _tag, args...)
+	if err != nil {
+		logger(ctx).Error("FindTagBySql error", "error", err)
+		return nil, err
+	}
+	return _tag, nil
+}
+
+// FindTagsBySql query use a complete SQL clause
+// with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindTagsBySql(ctx context.Context, sql string, args ...interface{}) (tags []Tag, err error) {
+	ctx = observeQuery(ctx, "FindTagsBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindTagsBySql error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &tags, args...)
+	if err != nil {
+		logger(ctx).Error("FindTagsBySql error", "error", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// CreateTag use a named params to create a single Tag record.
+// A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
+func CreateTag(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateTag")
+	if len(am) == 0 {
+		return 0, fmt.Errorf("Zero key in the attributes map!")
+	}
+	t := time.Now()
+	for _, v := range []string{"created_at", "updated_at"} {
+		if am[v] == nil {
+			am[v] = t
+		}
+	}
+	keys := allKeys(am)
+	sqlFmt := `INSERT INTO tags (%s) VALUES (%s)`
+	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
+	result, err := DB.NamedExecContext(ctx, sql, am)
+	if err != nil {
+		logger(ctx).Error("CreateTag error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("CreateTag error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+// Create is a method for Tag to create a record.
+func (_tag *Tag) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "Tag.Create")
+	ok, err := govalidator.ValidateStruct(_tag)
+	if !ok {
+		errMsg := "Validate Tag struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate Tag struct error: " + err.Error()
+		}
+		logger(ctx).Warn("Tag.Create error", "error", errMsg)
+		return 0, errors.New(errMsg)
+	}
+	t := time.Now()
+	_tag.CreatedAt = t
+	_tag.UpdatedAt = t
+    sql := `INSERT INTO tags (name,created_at,updated_at) VALUES (:name,:created_at,:updated_at)`
+    result, err := DB.NamedExecContext(ctx, sql, _tag)
+	if err != nil {
+		logger(ctx).Error("Tag.Create error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("Tag.Create error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+
+
+
+// ArticleTagsCreate is used for Tag to create the associated objects ArticleTags
+func (_tag *Tag) ArticleTagsCreate(ctx context.Context, am map[string]interface{}) error {
+			am["tag_id"] = _tag.Id
+		_, err := CreateArticleTag(ctx, am)
+	return err
+}
+
+// GetArticleTags is used for Tag to get associated objects ArticleTags
+// Say you have a Tag object named tag, when you call tag.GetArticleTags(),
+// the object will get the associated ArticleTags attributes evaluated in the struct.
+func (_tag *Tag) GetArticleTags(ctx context.Context) error {
+	_article_tags, err := TagGetArticleTags(ctx, _tag.Id)
+	if err == nil {
+		_tag.ArticleTags = _article_tags
+    }
+    return err
+}
+
+// TagGetArticleTags a helper fuction used to get associated objects for TagIncludesWhere().
+func TagGetArticleTags(ctx context.Context, id int64) ([]ArticleTag, error) {
+			_article_tags, err := FindArticleTagsBy(ctx, "tag_id", id)
+	return _article_tags, err
+}
+
+// GetArticles is used for Tag to get associated objects Articles through ArticleTags
+// Say you have a Tag object named tag, when you call tag.GetArticles(),
+// the object will get the associated Articles attributes evaluated in the struct.
+func (_tag *Tag) GetArticles(ctx context.Context) error {
+	_articles, err := TagGetArticles(ctx, _tag.Id)
+	if err == nil {
+		_tag.Articles = _articles
+    }
+    return err
+}
+
+// TagGetArticles a helper fuction used to get associated objects for TagIncludesWhere().
+func TagGetArticles(ctx context.Context, id int64) ([]Article, error) {
+			_articles, err := FindArticlesWhere(ctx, "id IN (SELECT article_id FROM article_tags WHERE tag_id = ?)", id)
+	return _articles, err
+}
+
+
+
+// Destroy is method used for a Tag object to be destroyed.
+func (_tag *Tag) Destroy(ctx context.Context) error {
+	if _tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := DestroyTag(ctx, _tag.Id)
+	return err
+}
+
+// DestroyTag will destroy a Tag record specified by the id parameter.
+func DestroyTag(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyTag")
+	// Destroy association objects at first
+	// Not care if exec properly temporarily
+	destroyTagAssociations(ctx, id)
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM tags WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DestroyTags will destroy Tag records those specified by the ids parameters.
+func DestroyTags(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyTags")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("DestroyTags error", "error", msg)
+		return 0, errors.New(msg)
+	}
+	// Destroy association objects at first
+	// Not care if exec properly temporarily
+	destroyTagAssociations(ctx, ids...)
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := fmt.Sprintf(`DELETE FROM tags WHERE id IN (?%s)`, idsHolder)
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DestroyTagsWhere delete records by a where clause restriction.
+// e.g. DestroyTagsWhere("name = ?", "John")
+// And this func will not call the association dependent action
+func DestroyTagsWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyTagsWhere")
+	sql := `DELETE FROM tags WHERE `
+	if len(where) > 0 {
+		sql = sql + where
+	} else {
+		return 0, errors.New("No WHERE conditions provided")
+	}
+	ids, x_err := TagIdsWhere(ctx, where, args...)
+	if x_err != nil {
+		logger(ctx).Error("DestroyTagsWhere associated objects error", "error", x_err)
+	} else {
+		destroyTagAssociations(ctx, ids...)
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+
+// destroyTagAssociations is a private function used to destroy a Tag record's associated objects.
+// The func not return err temporarily.
+func destroyTagAssociations(ctx context.Context, ids ...int64) {
+	idsHolder := ""
+	if len(ids) > 1 {
+		idsHolder = strings.Repeat(",?", len(ids)-1)
+	}
+	idsT := []interface{}{}
+	for _, id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	var err error
+	// make sure no declared-and-not-used exception
+	_, _, _ = idsHolder, idsT, err
+								where := fmt.Sprintf("tag_id IN (?%s)", idsHolder)
+							_, err = DestroyArticleTagsWhere(ctx, where, idsT...)
+							if err != nil {
+								logger(ctx).Error("destroyTagAssociations associated objects error", "assoc", "ArticleTags", "error", err)
+							}
+}
+
+// Save method is used for a Tag object to update an existed record mainly.
+// If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
+func (_tag *Tag) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "Tag.Save")
+	ok, err := govalidator.ValidateStruct(_tag)
+	if !ok {
+		errMsg := "Validate Tag struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate Tag struct error: " + err.Error()
+		}
+		logger(ctx).Warn("Tag.Save error", "error", errMsg)
+		return errors.New(errMsg)
+	}
+	if _tag.Id == 0 {
+		_, err = _tag.Create(ctx)
+		return err
+	}
+	_tag.UpdatedAt = time.Now()
+	sqlFmt := `UPDATE tags SET %s WHERE id = %v`
+	sqlStr := fmt.Sprintf(sqlFmt, "name = :name, updated_at = :updated_at", _tag.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _tag)
+    return err
+}
+
+// UpdateTag is used to update a record with a id and map[string]interface{} typed key-value parameters.
+func UpdateTag(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateTag")
+	if len(am) == 0 {
+		return errors.New("Zero key in the attributes map!")
+	}
+	am["updated_at"] = time.Now()
+	keys := allKeys(am)
+	sqlFmt := `UPDATE tags SET %s WHERE id = %v`
+	setKeysArr := []string{}
+	for _,v := range keys {
+		s := fmt.Sprintf(" %s = :%s", v, v)
+		setKeysArr = append(setKeysArr, s)
+	}
+	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
+	if err != nil {
+		logger(ctx).Error("UpdateTag error", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Update is a method used to update a Tag record with the map[string]interface{} typed key-value parameters.
+func (_tag *Tag) Update(ctx context.Context, am map[string]interface{}) error {
+	if _tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateTag(ctx, _tag.Id, am)
+	return err
+}
+
+// UpdateAttributes method is supposed to be used to update Tag records as corresponding update_attributes in Ruby on Rails.
+func (_tag *Tag) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
+	if _tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateTag(ctx, _tag.Id, am)
+	return err
+}
+
+// UpdateColumns method is supposed to be used to update Tag records as corresponding update_columns in Ruby on Rails.
+func (_tag *Tag) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
+	if _tag.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateTag(ctx, _tag.Id, am)
+	return err
+}
+
+// UpdateTagsBySql is used to update Tag records by a SQL clause
+// using the '?' binding syntax.
+func UpdateTagsBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateTagsBySql")
+	if sql == "" {
+		return 0, errors.New("A blank SQL clause")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
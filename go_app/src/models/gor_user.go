@@ -0,0 +1,930 @@
+
+
+// Package models includes the functions on the model User.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type User struct {
+	Id int64 `json:"id,omitempty" db:"id" valid:"-"`
+Name string `json:"name,omitempty" db:"name" valid:"required"`
+Email string `json:"email,omitempty" db:"email" valid:"required,email"`
+CreatedAt time.Time `json:"created_at,omitempty" db:"created_at" valid:"-"`
+UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at" valid:"-"`
+PasswordDigest string `json:"-" db:"password_digest" valid:"-"`
+Role string `json:"role,omitempty" db:"role" valid:"required,in(reader|commenter|editor|moderator|admin)"`
+Articles []Article `json:"articles,omitempty" db:"articles" valid:"-"`
+Comments []Comment `json:"comments,omitempty" db:"comments" valid:"-"`
+ApiKeys []ApiKey `json:"api_keys,omitempty" db:"api_keys" valid:"-"`
+}
+
+// DataStruct for the pagination
+type UserPage struct {
+	WhereString string
+	WhereParams []interface{}
+	Order       map[string]string
+	FirstId     int64
+	LastId      int64
+	PageNum     int
+	PerPage     int
+	TotalPages  int
+	TotalItems  int64
+	orderStr    string
+}
+
+// Current get the current page of UserPage object for pagination.
+func (_p *UserPage) Current(ctx context.Context) ([]User, error) {
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("current")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	users, err := FindUsersWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != 0 {
+		_p.FirstId, _p.LastId = users[0].Id, users[len(users)-1].Id
+	}
+	return users, nil
+}
+
+// Previous get the previous page of UserPage object for pagination.
+func (_p *UserPage) Previous(ctx context.Context) ([]User, error) {
+	if _p.PageNum == 0 {
+		return nil, errors.New("This's the first page, no previous page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("previous")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	users, err := FindUsersWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != 0 {
+		_p.FirstId, _p.LastId = users[0].Id, users[len(users)-1].Id
+	}
+	_p.PageNum -= 1
+	return users, nil
+}
+
+// Next get the next page of UserPage object for pagination.
+func (_p *UserPage) Next(ctx context.Context) ([]User, error) {
+	if _p.PageNum == _p.TotalPages-1 {
+		return nil, errors.New("This's the last page, no next page yet")
+	}
+	if _, exist := _p.Order["id"]; !exist {
+		return nil, errors.New("No id order specified in Order map")
+	}
+	err := _p.buildPageCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Calculate page count error: %v", err)
+	}
+	if _p.orderStr == "" {
+		_p.buildOrder()
+	}
+	idStr, idParams := _p.buildIdRestrict("next")
+	whereStr := fmt.Sprintf("%s %s %s LIMIT %v", _p.WhereString, idStr, _p.orderStr, _p.PerPage)
+	whereParams := []interface{}{}
+	whereParams = append(append(whereParams, _p.WhereParams...), idParams...)
+	users, err := FindUsersWhere(ctx, whereStr, whereParams...)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != 0 {
+		_p.FirstId, _p.LastId = users[0].Id, users[len(users)-1].Id
+	}
+	_p.PageNum += 1
+	return users, nil
+}
+
+// GetPage is a helper function for the UserPage object to return a corresponding page due to
+// the parameter passed in, i.e. one of "previous, current or next".
+func (_p *UserPage) GetPage(ctx context.Context, direction string) (ps []User, err error) {
+	switch direction {
+	case "previous":
+		ps, _ = _p.Previous(ctx)
+	case "next":
+		ps, _ = _p.Next(ctx)
+	case "current":
+		ps, _ = _p.Current(ctx)
+	default:
+		return nil, errors.New("Error: wrong dircetion! None of previous, current or next!")
+	}
+	return
+}
+
+// buildOrder is for UserPage object to build a SQL ORDER BY clause.
+func (_p *UserPage) buildOrder() {
+	tempList := []string{}
+	for k, v := range _p.Order {
+		tempList = append(tempList, fmt.Sprintf("%v %v", k, v))
+	}
+	_p.orderStr = " ORDER BY " + strings.Join(tempList, ", ")
+}
+
+// buildIdRestrict is for UserPage object to build a SQL clause for ID restriction,
+// implementing a simple keyset style pagination.
+func (_p *UserPage) buildIdRestrict(direction string) (idStr string, idParams []interface{}) {
+	switch direction {
+	case "previous":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.FirstId)
+		} else {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.FirstId)
+		}
+	case "current":
+		// trick to make Where function work
+		if _p.PageNum == 0 && _p.FirstId == 0 && _p.LastId == 0 {
+			idStr += "id > ? "
+			idParams = append(idParams, 0)
+		} else {
+			if strings.ToLower(_p.Order["id"]) == "desc" {
+				idStr += "id <= ? AND id >= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			} else {
+				idStr += "id >= ? AND id <= ? "
+				idParams = append(idParams, _p.FirstId, _p.LastId)
+			}
+		}
+	case "next":
+		if strings.ToLower(_p.Order["id"]) == "desc" {
+			idStr += "id < ? "
+			idParams = append(idParams, _p.LastId)
+		} else {
+			idStr += "id > ? "
+			idParams = append(idParams, _p.LastId)
+		}
+	}
+	if _p.WhereString != "" {
+		idStr = " AND " + idStr
+	}
+	return
+}
+
+// buildPageCount calculate the TotalItems/TotalPages for the UserPage object.
+func (_p *UserPage) buildPageCount(ctx context.Context) error {
+	count, err := UserCountWhere(ctx, _p.WhereString, _p.WhereParams...)
+	if err != nil {
+		return err
+	}
+	_p.TotalItems = count
+	if _p.PerPage == 0 {
+		_p.PerPage = 10
+	}
+	_p.TotalPages = int(math.Ceil(float64(_p.TotalItems) / float64(_p.PerPage)))
+	return nil
+}
+
+
+// FindUser find a single user by an ID.
+func FindUser(ctx context.Context, id int64) (*User, error) {
+	ctx = observeQuery(ctx, "FindUser")
+	if id == 0 {
+		return nil, errors.New("Invalid ID: it can't be zero")
+	}
+	_user := User{}
+	err := DB.GetContext(ctx, &_user, DB.Rebind(`SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users WHERE users.id = ? LIMIT 1`), id)
+	if err != nil {
+		logger(ctx).Error("FindUser error", "error", err)
+		return nil, err
+	}
+	return &_user, nil
+}
+
+// FirstUser find the first one user by ID ASC order.
+func FirstUser(ctx context.Context) (*User, error) {
+	ctx = observeQuery(ctx, "FirstUser")
+	_user := User{}
+	err := DB.GetContext(ctx, &_user, DB.Rebind(`SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users ORDER BY users.id ASC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("FirstUser error", "error", err)
+		return nil, err
+	}
+	return &_user, nil
+}
+
+// FirstUsers find the first N users by ID ASC order.
+func FirstUsers(ctx context.Context, n uint32) ([]User, error) {
+	ctx = observeQuery(ctx, "FirstUsers")
+	_users := []User{}
+	sql := fmt.Sprintf("SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users ORDER BY users.id ASC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_users, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FirstUsers error", "error", err)
+		return nil, err
+	}
+	return _users, nil
+}
+
+// LastUser find the last one user by ID DESC order.
+func LastUser(ctx context.Context) (*User, error) {
+	ctx = observeQuery(ctx, "LastUser")
+	_user := User{}
+	err := DB.GetContext(ctx, &_user, DB.Rebind(`SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users ORDER BY users.id DESC LIMIT 1`))
+	if err != nil {
+		logger(ctx).Error("LastUser error", "error", err)
+		return nil, err
+	}
+	return &_user, nil
+}
+
+// LastUsers find the last N users by ID DESC order.
+func LastUsers(ctx context.Context, n uint32) ([]User, error) {
+	ctx = observeQuery(ctx, "LastUsers")
+	_users := []User{}
+	sql := fmt.Sprintf("SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users ORDER BY users.id DESC LIMIT %v", n)
+	err := DB.SelectContext(ctx, &_users, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("LastUsers error", "error", err)
+		return nil, err
+	}
+	return _users, nil
+}
+
+// FindUsers find one or more users by the given ID(s).
+func FindUsers(ctx context.Context, ids ...int64) ([]User, error) {
+	ctx = observeQuery(ctx, "FindUsers")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("FindUsers error", "error", msg)
+		return nil, errors.New(msg)
+	}
+	_users := []User{}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := DB.Rebind(fmt.Sprintf(`SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users WHERE users.id IN (?%s)`, idsHolder))
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	err := DB.SelectContext(ctx, &_users, sql, idsT...)
+	if err != nil {
+		logger(ctx).Error("FindUsers error", "error", err)
+		return nil, err
+	}
+	return _users, nil
+}
+
+// FindUserBy find a single user by a field name and a value.
+func FindUserBy(ctx context.Context, field string, val interface{}) (*User, error) {
+	ctx = observeQuery(ctx, "FindUserBy")
+	_user := User{}
+	sqlFmt := `SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users WHERE %s = ? LIMIT 1`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err := DB.GetContext(ctx, &_user, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindUserBy error", "error", err)
+		return nil, err
+	}
+	return &_user, nil
+}
+
+// FindUsersBy find all users by a field name and a value.
+func FindUsersBy(ctx context.Context, field string, val interface{}) (_users []User, err error) {
+	ctx = observeQuery(ctx, "FindUsersBy")
+	sqlFmt := `SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users WHERE %s = ?`
+	sqlStr := fmt.Sprintf(sqlFmt, field)
+	err = DB.SelectContext(ctx, &_users, DB.Rebind(sqlStr), val)
+	if err != nil {
+		logger(ctx).Error("FindUsersBy error", "error", err)
+		return nil, err
+	}
+	return _users, nil
+}
+
+// AllUsers get all the User records.
+func AllUsers(ctx context.Context) (users []User, err error) {
+	ctx = observeQuery(ctx, "AllUsers")
+	err = DB.SelectContext(ctx, &users, "SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users")
+	if err != nil {
+		logger(ctx).Error("AllUsers error", "error", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// UserCount get the count of all the User records.
+func UserCount(ctx context.Context) (c int64, err error) {
+	ctx = observeQuery(ctx, "UserCount")
+	err = DB.GetContext(ctx, &c, "SELECT count(*) FROM users")
+	if err != nil {
+		logger(ctx).Error("UserCount error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// UserCountWhere get the count of all the User records with a where clause.
+func UserCountWhere(ctx context.Context, where string, args ...interface{}) (c int64, err error) {
+	ctx = observeQuery(ctx, "UserCountWhere")
+	sql := "SELECT count(*) FROM users"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("UserCountWhere error", "error", err)
+		return 0, err
+	}
+	err = stmt.GetContext(ctx, &c, args...)
+	if err != nil {
+		logger(ctx).Error("UserCountWhere error", "error", err)
+		return 0, err
+	}
+	return c, nil
+}
+
+// UserIncludesWhere get the User associated models records, currently it's not same as the corresponding "includes" function but "preload" instead in Ruby on Rails. It means that the "sql" should be restricted on User model.
+func UserIncludesWhere(ctx context.Context, assocs []string, sql string, args ...interface{}) (_users []User, err error) {
+	_users, err = FindUsersWhere(ctx, sql, args...)
+	if err != nil {
+		logger(ctx).Error("UserIncludesWhere error", "error", err)
+		return nil, err
+	}
+	if len(assocs) == 0 {
+		logger(ctx).Warn("UserIncludesWhere without associated fields")
+		return _users, err
+	}
+	if len(_users) <= 0 {
+		return nil, errors.New("No results available")
+	}
+	ids := make([]interface{}, len(_users))
+	for _, v := range _users {
+		ids = append(ids, interface{}(v.Id))
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	for _, assoc := range assocs {
+		switch assoc {
+				case "articles":
+							where := fmt.Sprintf("user_id IN (?%s)", idsHolder)
+						_articles, err := FindArticlesWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("UserIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _articles {
+							for i, vvv := range  _users {
+									if vv.UserId == vvv.Id {
+										vvv.Articles = append(vvv.Articles, vv)
+									}
+								_users[i].Articles = vvv.Articles
+						    }
+					    }
+				case "comments":
+							where := fmt.Sprintf("user_id IN (?%s)", idsHolder)
+						_comments, err := FindCommentsWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("UserIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _comments {
+							for i, vvv := range  _users {
+									if vv.UserId == vvv.Id {
+										vvv.Comments = append(vvv.Comments, vv)
+									}
+								_users[i].Comments = vvv.Comments
+						    }
+					    }
+				case "api_keys":
+							where := fmt.Sprintf("user_id IN (?%s)", idsHolder)
+						_api_keys, err := FindApiKeysWhere(ctx, where, ids...)
+						if err != nil {
+							logger(ctx).Error("UserIncludesWhere associated objects error", "assoc", assoc, "error", err)
+							continue
+						}
+						for _, vv := range _api_keys {
+							for i, vvv := range  _users {
+									if vv.UserId == vvv.Id {
+										vvv.ApiKeys = append(vvv.ApiKeys, vv)
+									}
+								_users[i].ApiKeys = vvv.ApiKeys
+						    }
+					    }
+		}
+	}
+	return _users, nil
+}
+
+// UserIds get all the IDs of User records.
+func UserIds(ctx context.Context) (ids []int64, err error) {
+	ctx = observeQuery(ctx, "UserIds")
+	err = DB.SelectContext(ctx, &ids, "SELECT id FROM users")
+	if err != nil {
+		logger(ctx).Error("UserIds error", "error", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UserIdsWhere get all the IDs of User records by where restriction.
+func UserIdsWhere(ctx context.Context, where string, args ...interface{}) ([]int64, error) {
+	ids, err := UserIntCol(ctx, "id", where, args...)
+	return ids, err
+}
+
+// UserIntCol get some int64 typed column of User by where restriction.
+func UserIntCol(ctx context.Context, col, where string, args ...interface{}) (intColRecs []int64, err error) {
+	ctx = observeQuery(ctx, "UserIntCol")
+	sql := "SELECT " + col + " FROM users"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("UserIntCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &intColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("UserIntCol error", "error", err)
+		return nil, err
+	}
+	return intColRecs, nil
+}
+
+// UserStrCol get some string typed column of User by where restriction.
+func UserStrCol(ctx context.Context, col, where string, args ...interface{}) (strColRecs []string, err error) {
+	ctx = observeQuery(ctx, "UserStrCol")
+	sql := "SELECT " + col + " FROM users"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("UserStrCol error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &strColRecs, args...)
+	if err != nil {
+		logger(ctx).Error("UserStrCol error", "error", err)
+		return nil, err
+	}
+	return strColRecs, nil
+}
+
+// FindUsersWhere query use a partial SQL clause that usually following after WHERE
+// with placeholders, eg: FindUsersWhere("first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindUsersWhere(ctx context.Context, where string, args ...interface{}) (users []User, err error) {
+	ctx = observeQuery(ctx, "FindUsersWhere")
+	sql := "SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindUsersWhere error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &users, args...)
+	if err != nil {
+		logger(ctx).Error("FindUsersWhere error", "error", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// EachUser iterate over the User records restricted by a where clause one by one,
+// the rows are streamed from the database instead of loaded into a slice at once,
+// eg: EachUser(ctx, "id > ?", []interface{}{100}, func(user User) error { ... }).
+// The iteration stops at the first error returned by fn, or when ctx is done.
+func EachUser(ctx context.Context, where string, args []interface{}, fn func(User) error) error {
+	ctx = observeQuery(ctx, "EachUser")
+	sql := "SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users"
+	if len(where) > 0 {
+		sql = sql + " WHERE " + where
+	}
+	rows, err := DB.QueryxContext(ctx, DB.Rebind(sql), args...)
+	if err != nil {
+		logger(ctx).Error("EachUser error", "error", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		_user := User{}
+		if err = rows.StructScan(&_user); err != nil {
+			logger(ctx).Error("EachUser error", "error", err)
+			return err
+		}
+		if err = fn(_user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// UsersInBatches iterate over all the User records in batches of batchSize,
+// see UsersInBatchesWhere.
+func UsersInBatches(ctx context.Context, batchSize int, fn func([]User) error) error {
+	return UsersInBatchesWhere(ctx, batchSize, "", nil, fn)
+}
+
+// UsersInBatchesWhere iterate over the User records restricted by a where clause in batches of batchSize,
+// ordered by ID ASC. Every batch is loaded with a keyset style query on the id column,
+// so only one batch is kept in memory at a time no matter how large the table is.
+func UsersInBatchesWhere(ctx context.Context, batchSize int, where string, args []interface{}, fn func([]User) error) error {
+	ctx = observeQuery(ctx, "UsersInBatchesWhere")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	sqlFmt := "SELECT COALESCE(users.password_digest, '') AS password_digest, users.id, users.name, users.email, users.created_at, users.updated_at, users.role FROM users WHERE %susers.id > ? ORDER BY users.id ASC LIMIT %v"
+	whereStr := ""
+	if len(where) > 0 {
+		whereStr = "(" + where + ") AND "
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(fmt.Sprintf(sqlFmt, whereStr, batchSize)))
+	if err != nil {
+		logger(ctx).Error("UsersInBatchesWhere error", "error", err)
+		return err
+	}
+	defer stmt.Close()
+	var lastId int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		_users := []User{}
+		batchArgs := append(append([]interface{}{}, args...), lastId)
+		err = stmt.SelectContext(ctx, &_users, batchArgs...)
+		if err != nil {
+			logger(ctx).Error("UsersInBatchesWhere error", "error", err)
+			return err
+		}
+		if len(_users) == 0 {
+			return nil
+		}
+		lastId = _users[len(_users)-1].Id
+		if err = fn(_users); err != nil {
+			return err
+		}
+		if len(_users) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindUserBySql query use a complete SQL clause
+// with placeholders, eg: FindUserBySql("SELECT * FROM users WHERE first_name = ? AND age > ? ORDER BY DESC LIMIT 1", "John", 18)
+// will return only One record in the table "users" whose first_name is "John" and age elder than 18.
+func FindUserBySql(ctx context.Context, sql string, args ...interface{}) (*User, error) {
+	ctx = observeQuery(ctx, "FindUserBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindUserBySql error", "error", err)
+		return nil, err
+	}
+	_user := &User{}
+	err = stmt.GetContext(ctx, _user, args...)
+	if err != nil {
+		logger(ctx).Error("FindUserBySql error", "error", err)
+		return nil, err
+	}
+	return _user, nil
+}
+
+// FindUsersBySql query use a complete SQL clause
+// with placeholders, eg: FindUsersBySql("SELECT * FROM users WHERE first_name = ? AND age > ?", "John", 18)
+// will return those records in the table "users" whose first_name is "John" and age elder than 18.
+func FindUsersBySql(ctx context.Context, sql string, args ...interface{}) (users []User, err error) {
+	ctx = observeQuery(ctx, "FindUsersBySql")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	if err != nil {
+		logger(ctx).Error("FindUsersBySql error", "error", err)
+		return nil, err
+	}
+	err = stmt.SelectContext(ctx, &users, args...)
+	if err != nil {
+		logger(ctx).Error("FindUsersBySql error", "error", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// CreateUser use a named params to create a single User record.
+// A named params is key-value map like map[string]interface{}{"first_name": "John", "age": 23} .
+func CreateUser(ctx context.Context, am map[string]interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "CreateUser")
+	if len(am) == 0 {
+		return 0, fmt.Errorf("Zero key in the attributes map!")
+	}
+	t := time.Now()
+	for _, v := range []string{"created_at", "updated_at"} {
+		if am[v] == nil {
+			am[v] = t
+		}
+	}
+	keys := allKeys(am)
+	sqlFmt := `INSERT INTO users (%s) VALUES (%s)`
+	sql := fmt.Sprintf(sqlFmt, strings.Join(keys, ","), ":"+strings.Join(keys, ",:"))
+	result, err := DB.NamedExecContext(ctx, sql, am)
+	if err != nil {
+		logger(ctx).Error("CreateUser error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("CreateUser error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+// Create is a method for User to create a record.
+func (_user *User) Create(ctx context.Context) (int64, error) {
+	ctx = observeQuery(ctx, "User.Create")
+	ok, err := govalidator.ValidateStruct(_user)
+	if !ok {
+		errMsg := "Validate User struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate User struct error: " + err.Error()
+		}
+		logger(ctx).Warn("User.Create error", "error", errMsg)
+		return 0, errors.New(errMsg)
+	}
+	t := time.Now()
+	_user.CreatedAt = t
+	_user.UpdatedAt = t
+    sql := `INSERT INTO users (name,email,created_at,updated_at,password_digest,role) VALUES (:name,:email,:created_at,:updated_at,:password_digest,:role)`
+    result, err := DB.NamedExecContext(ctx, sql, _user)
+	if err != nil {
+		logger(ctx).Error("User.Create error", "error", err)
+		return 0, err
+	}
+	lastId, err := result.LastInsertId()
+	if err != nil {
+		logger(ctx).Error("User.Create error", "error", err)
+		return 0, err
+	}
+	return lastId, nil
+}
+
+
+
+
+// ArticlesCreate is used for User to create the associated objects Articles
+func (_user *User) ArticlesCreate(ctx context.Context, am map[string]interface{}) error {
+			am["user_id"] = _user.Id
+		_, err := CreateArticle(ctx, am)
+	return err
+}
+
+// GetArticles is used for User to get associated objects Articles
+// Say you have a User object named user, when you call user.GetArticles(),
+// the object will get the associated Articles attributes evaluated in the struct.
+func (_user *User) GetArticles(ctx context.Context) error {
+	_articles, err := UserGetArticles(ctx, _user.Id)
+	if err == nil {
+		_user.Articles = _articles
+    }
+    return err
+}
+
+// UserGetArticles a helper fuction used to get associated objects for UserIncludesWhere().
+func UserGetArticles(ctx context.Context, id int64) ([]Article, error) {
+			_articles, err := FindArticlesBy(ctx, "user_id", id)
+	return _articles, err
+}
+
+// CommentsCreate is used for User to create the associated objects Comments
+func (_user *User) CommentsCreate(ctx context.Context, am map[string]interface{}) error {
+			am["user_id"] = _user.Id
+		_, err := CreateComment(ctx, am)
+	return err
+}
+
+// GetComments is used for User to get associated objects Comments
+// Say you have a User object named user, when you call user.GetComments(),
+// the object will get the associated Comments attributes evaluated in the struct.
+func (_user *User) GetComments(ctx context.Context) error {
+	_comments, err := UserGetComments(ctx, _user.Id)
+	if err == nil {
+		_user.Comments = _comments
+    }
+    return err
+}
+
+// UserGetComments a helper fuction used to get associated objects for UserIncludesWhere().
+func UserGetComments(ctx context.Context, id int64) ([]Comment, error) {
+			_comments, err := FindCommentsBy(ctx, "user_id", id)
+	return _comments, err
+}
+
+// ApiKeysCreate is used for User to create the associated objects ApiKeys
+func (_user *User) ApiKeysCreate(ctx context.Context, am map[string]interface{}) error {
+			am["user_id"] = _user.Id
+		_, err := CreateApiKey(ctx, am)
+	return err
+}
+
+// GetApiKeys is used for User to get associated objects ApiKeys
+// Say you have a User object named user, when you call user.GetApiKeys(),
+// the object will get the associated ApiKeys attributes evaluated in the struct.
+func (_user *User) GetApiKeys(ctx context.Context) error {
+	_api_keys, err := UserGetApiKeys(ctx, _user.Id)
+	if err == nil {
+		_user.ApiKeys = _api_keys
+    }
+    return err
+}
+
+// UserGetApiKeys a helper fuction used to get associated objects for UserIncludesWhere().
+func UserGetApiKeys(ctx context.Context, id int64) ([]ApiKey, error) {
+			_api_keys, err := FindApiKeysBy(ctx, "user_id", id)
+	return _api_keys, err
+}
+
+
+
+// Destroy is method used for a User object to be destroyed.
+func (_user *User) Destroy(ctx context.Context) error {
+	if _user.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := DestroyUser(ctx, _user.Id)
+	return err
+}
+
+// DestroyUser will destroy a User record specified by the id parameter.
+func DestroyUser(ctx context.Context, id int64) error {
+	ctx = observeQuery(ctx, "DestroyUser")
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(`DELETE FROM users WHERE id = ?`))
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DestroyUsers will destroy User records those specified by the ids parameters.
+func DestroyUsers(ctx context.Context, ids ...int64) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyUsers")
+	if len(ids) == 0 {
+		msg := "At least one or more ids needed"
+		logger(ctx).Warn("DestroyUsers error", "error", msg)
+		return 0, errors.New(msg)
+	}
+	idsHolder := strings.Repeat(",?", len(ids)-1)
+	sql := fmt.Sprintf(`DELETE FROM users WHERE id IN (?%s)`, idsHolder)
+	idsT := []interface{}{}
+	for _,id := range ids {
+		idsT = append(idsT, interface{}(id))
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, idsT...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// DestroyUsersWhere delete records by a where clause restriction.
+// e.g. DestroyUsersWhere("name = ?", "John")
+// And this func will not call the association dependent action
+func DestroyUsersWhere(ctx context.Context, where string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "DestroyUsersWhere")
+	sql := `DELETE FROM users WHERE `
+	if len(where) > 0 {
+		sql = sql + where
+	} else {
+		return 0, errors.New("No WHERE conditions provided")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+
+// Save method is used for a User object to update an existed record mainly.
+// If no id provided a new record will be created. FIXME: A UPSERT action will be implemented further.
+func (_user *User) Save(ctx context.Context) error {
+	ctx = observeQuery(ctx, "User.Save")
+	ok, err := govalidator.ValidateStruct(_user)
+	if !ok {
+		errMsg := "Validate User struct error: Unknown error"
+		if err != nil {
+			errMsg = "Validate User struct error: " + err.Error()
+		}
+		logger(ctx).Warn("User.Save error", "error", errMsg)
+		return errors.New(errMsg)
+	}
+	if _user.Id == 0 {
+		_, err = _user.Create(ctx)
+		return err
+	}
+	_user.UpdatedAt = time.Now()
+	sqlFmt := `UPDATE users SET %s WHERE id = %v`
+	sqlStr := fmt.Sprintf(sqlFmt, "name = :name, email = :email, updated_at = :updated_at, password_digest = :password_digest, role = :role", _user.Id)
+    _, err = DB.NamedExecContext(ctx, sqlStr, _user)
+    return err
+}
+
+// UpdateUser is used to update a record with a id and map[string]interface{} typed key-value parameters.
+func UpdateUser(ctx context.Context, id int64, am map[string]interface{}) error {
+	ctx = observeQuery(ctx, "UpdateUser")
+	if len(am) == 0 {
+		return errors.New("Zero key in the attributes map!")
+	}
+	am["updated_at"] = time.Now()
+	keys := allKeys(am)
+	sqlFmt := `UPDATE users SET %s WHERE id = %v`
+	setKeysArr := []string{}
+	for _,v := range keys {
+		s := fmt.Sprintf(" %s = :%s", v, v)
+		setKeysArr = append(setKeysArr, s)
+	}
+	sqlStr := fmt.Sprintf(sqlFmt, strings.Join(setKeysArr, ", "), id)
+	_, err := DB.NamedExecContext(ctx, sqlStr, am)
+	if err != nil {
+		logger(ctx).Error("UpdateUser error", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Update is a method used to update a User record with the map[string]interface{} typed key-value parameters.
+func (_user *User) Update(ctx context.Context, am map[string]interface{}) error {
+	if _user.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateUser(ctx, _user.Id, am)
+	return err
+}
+
+// UpdateAttributes method is supposed to be used to update User records as corresponding update_attributes in Ruby on Rails.
+func (_user *User) UpdateAttributes(ctx context.Context, am map[string]interface{}) error {
+	if _user.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateUser(ctx, _user.Id, am)
+	return err
+}
+
+// UpdateColumns method is supposed to be used to update User records as corresponding update_columns in Ruby on Rails.
+func (_user *User) UpdateColumns(ctx context.Context, am map[string]interface{}) error {
+	if _user.Id == 0 {
+		return errors.New("Invalid Id field: it can't be a zero value")
+	}
+	err := UpdateUser(ctx, _user.Id, am)
+	return err
+}
+
+// UpdateUsersBySql is used to update User records by a SQL clause
+// using the '?' binding syntax.
+func UpdateUsersBySql(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	ctx = observeQuery(ctx, "UpdateUsersBySql")
+	if sql == "" {
+		return 0, errors.New("A blank SQL clause")
+	}
+	stmt, err := DB.PreparexContext(ctx, DB.Rebind(sql))
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	cnt, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"../logging"
+)
+
+// The operations passed to the write hooks.
+const (
+	Created   = "create"
+	Updated   = "update"
+	Destroyed = "destroy"
+)
+
+// WriteHook is called with the context of the write after some records of a table are created, updated or destroyed
+// by the model functions, e.g. to keep a search index in sync. The *BySql functions don't call the hooks.
+type WriteHook func(ctx context.Context, table, op string, ids []int64)
+
+var writeHooks []WriteHook
+
+// OnWrite register a hook called after the writes on the articles and the comments,
+// the hooks should be registered at the start before any write.
+func OnWrite(h WriteHook) {
+	writeHooks = append(writeHooks, h)
+}
+
+// hooked tell whether the writes on the table are hooked or audited, so the ids of the records are needed.
+func hooked(table string) bool {
+	return len(writeHooks) > 0 || auditing(table)
+}
+
+// afterWrite record the write in the audit log with the rows before it, and call the hooks.
+func afterWrite(ctx context.Context, table, op string, before snapshots, ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	if n, ok := ctx.Value(writeCountKey{}).(*int64); ok {
+		atomic.AddInt64(n, int64(len(ids)))
+	}
+	if auditing(table) {
+		recordAudit(ctx, table, op, before, ids)
+	}
+	for _, h := range writeHooks {
+		h(ctx, table, op, ids)
+	}
+}
+
+type writeCountKey struct{}
+
+// CountWrites get a copy of ctx counting the rows written by the model functions called with it,
+// and a func returning the count so far, e.g. for the logs of the requests.
+func CountWrites(ctx context.Context) (context.Context, func() int64) {
+	n := new(int64)
+	return context.WithValue(ctx, writeCountKey{}, n), func() int64 { return atomic.LoadInt64(n) }
+}
+
+// logger get the logger of the model functions, the one of the request of ctx if any.
+func logger(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx)
+}
+
+// QueryHook is called with how long a statement of a model function took to run, e.g. for the metrics.
+// The function is named as in the code, e.g. "FindArticlesWhere" or "Article.Save".
+type QueryHook func(function string, d time.Duration)
+
+var queryHooks []QueryHook
+
+// OnQuery register a hook called after each statement run by the model functions,
+// the hooks should be registered at the start before any query.
+func OnQuery(h QueryHook) {
+	queryHooks = append(queryHooks, h)
+}
+
+type functionKey struct{}
+
+// observeQuery get a copy of ctx naming the model function for the spans and the query hooks of its statements.
+// The functions running statements by themselves call it first, e.g. FindArticlesWhere or Article.Save,
+// not the ones only calling others, so a statement is named by the innermost function and observed once.
+func observeQuery(ctx context.Context, function string) context.Context {
+	return context.WithValue(ctx, functionKey{}, function)
+}
+
+// ObserveQuery is observeQuery for the packages running statements on DB by themselves, e.g. the MySQL search backend.
+func ObserveQuery(ctx context.Context, function string) context.Context {
+	return observeQuery(ctx, function)
+}
+
+// observeStatement call the query hooks with how long a statement took to run, reading its rows aside,
+// e.g. the fn of the Each and InBatches functions isn't included. The statements run outside the model functions are skipped.
+func observeStatement(ctx context.Context, d time.Duration) {
+	fn := modelFunction(ctx)
+	if fn == "" {
+		return
+	}
+	for _, h := range queryHooks {
+		h(fn, d)
+	}
+}
+
+// modelFunction get the innermost model function of ctx, e.g. "FindArticlesWhere", empty if none.
+func modelFunction(ctx context.Context) string {
+	fn, _ := ctx.Value(functionKey{}).(string)
+	return fn
+}
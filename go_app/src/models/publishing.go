@@ -0,0 +1,123 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The statuses of an article, only the published ones are public.
+const (
+	ArticleDraft     = "draft"
+	ArticleScheduled = "scheduled"
+	ArticlePublished = "published"
+	ArticleArchived  = "archived"
+)
+
+// ArticleStatuses are all the statuses of an article.
+var ArticleStatuses = []string{ArticleDraft, ArticleScheduled, ArticlePublished, ArticleArchived}
+
+// ValidArticleStatus tell whether the status is a known one.
+func ValidArticleStatus(status string) bool {
+	for _, s := range ArticleStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ArticleStatusEvent is emitted after the status of an article changed.
+type ArticleStatusEvent struct {
+	ArticleId   int64      `json:"article_id"`
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	At          time.Time  `json:"at"`
+}
+
+// ArticleStatusHook is called with the events of the status changes.
+type ArticleStatusHook func(e ArticleStatusEvent)
+
+var articleStatusHooks []ArticleStatusHook
+
+// OnArticleStatus register a hook called after the status of an article changed,
+// the hooks should be registered at the start before any change.
+func OnArticleStatus(h ArticleStatusHook) {
+	articleStatusHooks = append(articleStatusHooks, h)
+}
+
+// PublishArticle publish the article at the time, or schedule it if the time is in the future.
+// A zero time publishes it now.
+func PublishArticle(ctx context.Context, ar *Article, at time.Time) error {
+	now := time.Now()
+	if at.IsZero() || !at.After(now) {
+		if ar.Status == ArticlePublished {
+			return nil
+		}
+		if at.IsZero() {
+			at = now
+		}
+		return setArticleStatus(ctx, ar, ArticlePublished, &at)
+	}
+	return setArticleStatus(ctx, ar, ArticleScheduled, &at)
+}
+
+// UnpublishArticle take the article back to a draft, or archive it keeping its publication time.
+func UnpublishArticle(ctx context.Context, ar *Article, archive bool) error {
+	if archive {
+		if ar.Status == ArticleArchived {
+			return nil
+		}
+		return setArticleStatus(ctx, ar, ArticleArchived, ar.PublishedAt)
+	}
+	if ar.Status == ArticleDraft {
+		return nil
+	}
+	return setArticleStatus(ctx, ar, ArticleDraft, nil)
+}
+
+// PublishDueArticles publish the scheduled articles whose time has come, and return their ids.
+// An article failing to publish doesn't hold back the others, the errors of all the failed ones are joined.
+func PublishDueArticles(ctx context.Context, now time.Time) ([]int64, error) {
+	articles, err := FindArticlesWhere(ctx, "status = ? AND published_at <= ?", ArticleScheduled, now)
+	if err != nil {
+		return nil, err
+	}
+	ids := []int64{}
+	var errs []error
+	for i := range articles {
+		if err = setArticleStatus(ctx, &articles[i], ArticlePublished, articles[i].PublishedAt); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, articles[i].Id)
+	}
+	return ids, errors.Join(errs...)
+}
+
+// setArticleStatus change the status of the article unless it was changed by someone else since it was read,
+// then call the write and the status hooks.
+func setArticleStatus(ctx context.Context, ar *Article, status string, publishedAt *time.Time) error {
+	ctx = observeQuery(ctx, "setArticleStatus")
+	t := time.Now()
+	before := beforeWrite(ctx, "articles", ar.Id)
+	result, err := DB.ExecContext(ctx, DB.Rebind("UPDATE articles SET status = ?, published_at = ?, updated_at = ? WHERE id = ? AND status = ?"),
+		status, publishedAt, t, ar.Id, ar.Status)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("The status of article %d was changed meanwhile, please retry", ar.Id)
+	}
+	e := ArticleStatusEvent{ArticleId: ar.Id, From: ar.Status, To: status, PublishedAt: publishedAt, At: t}
+	ar.Status, ar.PublishedAt, ar.UpdatedAt = status, publishedAt, t
+	afterWrite(ctx, "articles", Updated, before, ar.Id)
+	for _, h := range articleStatusHooks {
+		h(e)
+	}
+	return nil
+}
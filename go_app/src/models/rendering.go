@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+
+	"../markup"
+)
+
+// The HTML rendered from the text of the articles and the body of the comments is cached in their rows,
+// it's emptied when the text is updated, and by ClearHTMLCache when the allowlist of the sanitizer changed.
+// Caching isn't a change of the records, so it's neither audited nor hooked.
+
+// LoadTextHTML render the text of the article unless its HTML is cached already, then cache it.
+func (_article *Article) LoadTextHTML(ctx context.Context) {
+	if _article.TextHtml != "" || _article.Text == "" {
+		return
+	}
+	_article.TextHtml = markup.HTML(_article.Text)
+	if _article.Id == 0 {
+		return
+	}
+	ctx = observeQuery(ctx, "Article.LoadTextHTML")
+	// only cached if the text wasn't updated meanwhile
+	_, err := DB.ExecContext(ctx, DB.Rebind("UPDATE articles SET text_html = ? WHERE id = ? AND text = ? AND text_html IS NULL"), _article.TextHtml, _article.Id, _article.Text)
+	if err != nil {
+		logger(ctx).Error("Cache article HTML error", "id", _article.Id, "error", err)
+	}
+}
+
+// LoadArticlesHTML render the text of the articles whose HTML isn't cached yet.
+func LoadArticlesHTML(ctx context.Context, articles []Article) {
+	for i := range articles {
+		articles[i].LoadTextHTML(ctx)
+	}
+}
+
+// LoadBodyHTML render the body of the comment unless its HTML is cached already, then cache it.
+func (_comment *Comment) LoadBodyHTML(ctx context.Context) {
+	if _comment.BodyHtml != "" || _comment.Body == "" {
+		return
+	}
+	_comment.BodyHtml = markup.HTML(_comment.Body)
+	if _comment.Id == 0 {
+		return
+	}
+	ctx = observeQuery(ctx, "Comment.LoadBodyHTML")
+	_, err := DB.ExecContext(ctx, DB.Rebind("UPDATE comments SET body_html = ? WHERE id = ? AND body = ? AND body_html IS NULL"), _comment.BodyHtml, _comment.Id, _comment.Body)
+	if err != nil {
+		logger(ctx).Error("Cache comment HTML error", "id", _comment.Id, "error", err)
+	}
+}
+
+// LoadCommentsHTML render the body of the comments and their nested replies whose HTML isn't cached yet.
+func LoadCommentsHTML(ctx context.Context, comments []Comment) {
+	for i := range comments {
+		comments[i].LoadBodyHTML(ctx)
+		LoadCommentsHTML(ctx, comments[i].Replies)
+	}
+}
+
+// ClearHTMLCache empty the cached HTML of all the articles and the comments, it's rendered again on the next reads.
+func ClearHTMLCache(ctx context.Context) (articles, comments int64, err error) {
+	ctx = observeQuery(ctx, "ClearHTMLCache")
+	result, err := DB.ExecContext(ctx, "UPDATE articles SET text_html = NULL WHERE text_html IS NOT NULL")
+	if err != nil {
+		return 0, 0, err
+	}
+	if articles, err = result.RowsAffected(); err != nil {
+		return 0, 0, err
+	}
+	if result, err = DB.ExecContext(ctx, "UPDATE comments SET body_html = NULL WHERE body_html IS NOT NULL"); err != nil {
+		return articles, 0, err
+	}
+	comments, err = result.RowsAffected()
+	return articles, comments, err
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SaveArticleRevision store the title and the text of the article as they are now as its next revision,
+// edited by the user of editorId, 0 for none. Nothing is stored if they didn't change since the last revision.
+func SaveArticleRevision(ctx context.Context, articleId, editorId int64) (*ArticleRevision, error) {
+	ar, err := FindArticle(ctx, articleId)
+	if err != nil {
+		return nil, err
+	}
+	last, err := FindArticleRevisionsWhere(ctx, "article_id = ? ORDER BY rev DESC LIMIT 1", articleId)
+	if err != nil {
+		return nil, err
+	}
+	rev := &ArticleRevision{ArticleId: articleId, Rev: 1, Title: ar.Title, Text: ar.Text, UserId: editorId}
+	if len(last) != 0 {
+		if last[0].Title == ar.Title && last[0].Text == ar.Text {
+			return &last[0], nil
+		}
+		rev.Rev = last[0].Rev + 1
+	}
+	// the unique index on article_id and rev fails the concurrent saves of the same revision, the next rev is tried then
+	for tries := 0; ; tries++ {
+		if rev.Id, err = rev.Create(ctx); err == nil || tries == 2 {
+			break
+		}
+		if revs, e := ArticleRevisionIntCol(ctx, "rev", "article_id = ? ORDER BY rev DESC LIMIT 1", articleId); e == nil && len(revs) != 0 {
+			rev.Rev = revs[0] + 1
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// FindArticleRevisionByRev find a revision of the article by its number.
+func FindArticleRevisionByRev(ctx context.Context, articleId, rev int64) (*ArticleRevision, error) {
+	revs, err := FindArticleRevisionsWhere(ctx, "article_id = ? AND rev = ? LIMIT 1", articleId, rev)
+	if err == nil && len(revs) == 0 {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revs[0], nil
+}
+
+// RestoreArticleRevision put the title and the text of the revision back in the article,
+// which is stored as a new revision by the editor.
+func RestoreArticleRevision(ctx context.Context, rev *ArticleRevision, editorId int64) (*ArticleRevision, error) {
+	err := UpdateArticle(ctx, rev.ArticleId, map[string]interface{}{"title": rev.Title, "text": rev.Text})
+	if err != nil {
+		return nil, err
+	}
+	return SaveArticleRevision(ctx, rev.ArticleId, editorId)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SchemaVersion is the latest migration of the Rails app the models rely on, bump it with each new migration.
+const SchemaVersion = "20261018210000"
+
+// SchemaStatus tell the latest migration run on the database, and an error if SchemaVersion isn't run yet,
+// e.g. the app was deployed before the migrations.
+func SchemaStatus(ctx context.Context) (latest string, err error) {
+	ctx = observeQuery(ctx, "SchemaStatus")
+	var v sql.NullString
+	if err = DB.GetContext(ctx, &v, "SELECT MAX(version) FROM schema_migrations"); err != nil {
+		return "", err
+	}
+	var n int
+	if err = DB.GetContext(ctx, &n, DB.Rebind("SELECT COUNT(*) FROM schema_migrations WHERE version = ?"), SchemaVersion); err != nil {
+		return v.String, err
+	}
+	if n == 0 {
+		return v.String, fmt.Errorf("The migration %s is pending", SchemaVersion)
+	}
+	return v.String, nil
+}
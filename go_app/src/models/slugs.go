@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"../slug"
+)
+
+var slugSuffix = regexp.MustCompile(`^(.+)-([0-9]+)$`)
+
+// slugTaken tell whether the slug is the current or an old slug of another article than id.
+func slugTaken(ctx context.Context, slug string, id int64) (bool, error) {
+	n, err := ArticleCountWhere(ctx, "slug = ? AND id <> ?", slug, id)
+	if err != nil || n > 0 {
+		return n > 0, err
+	}
+	n, err = ArticleSlugCountWhere(ctx, "slug = ? AND article_id <> ?", slug, id)
+	return n > 0, err
+}
+
+// UniqueArticleSlug slugify the title and add a "-2", "-3"... suffix until no other article than id has it,
+// the id is 0 for a new article.
+func UniqueArticleSlug(ctx context.Context, title string, id int64) (string, error) {
+	base := slug.Make(title)
+	slug := base
+	for i := 2; ; i++ {
+		taken, err := slugTaken(ctx, slug, id)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// articleSlugFor get the slug of the article with the title: the current one is kept while it still
+// matches the title, otherwise the current one goes to the history so its links are redirected.
+// The slugs backfilled by the migration don't follow slug.Make for every title, e.g. with a "&",
+// so they're kept as long as the slug of the title doesn't change.
+func articleSlugFor(ctx context.Context, id int64, title string) (string, error) {
+	ar, err := FindArticle(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	base := slug.Make(title)
+	if ar.Slug != "" && base == slug.Make(ar.Title) {
+		return ar.Slug, nil
+	}
+	current := ar.Slug
+	if sm := slugSuffix.FindStringSubmatch(current); sm != nil && sm[1] == base {
+		current = base
+	}
+	if current == base {
+		return ar.Slug, nil
+	}
+	slug, err := UniqueArticleSlug(ctx, title, id)
+	if err != nil {
+		return "", err
+	}
+	if _, err = DestroyArticleSlugsWhere(ctx, "article_id = ? AND slug = ?", id, slug); err != nil {
+		return "", err
+	}
+	if ar.Slug != "" {
+		if err = ar.ArticleSlugsCreate(ctx, map[string]interface{}{"slug": ar.Slug}); err != nil {
+			return "", err
+		}
+	}
+	return slug, nil
+}
+
+// FindArticleBySlug find an article by its current or an old slug,
+// current is false for an old one which should be redirected.
+func FindArticleBySlug(ctx context.Context, slug string) (ar *Article, current bool, err error) {
+	if ar, err = FindArticleBy(ctx, "slug", slug); err == nil {
+		return ar, true, nil
+	}
+	old, err := FindArticleSlugBy(ctx, "slug", slug)
+	if err != nil {
+		return nil, false, err
+	}
+	ar, err = FindArticle(ctx, old.ArticleId)
+	return ar, false, err
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxArticleTags is the most tags an article can have.
+const MaxArticleTags = 10
+
+// TagUsage is a tag with the count of the articles tagged.
+type TagUsage struct {
+	Id            int64  `json:"id" db:"id"`
+	Name          string `json:"name" db:"name"`
+	ArticlesCount int64  `json:"articles_count" db:"articles_count"`
+}
+
+// NormalizeTagNames lower the case of the tag names, replace their inner spaces with "-",
+// and drop the blank and the duplicate ones, e.g. " Go,go , Data Base" gives "go", "data-base".
+func NormalizeTagNames(names []string) ([]string, error) {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, n := range names {
+		n = strings.Join(strings.Fields(strings.ToLower(n)), "-")
+		if n == "" || seen[n] {
+			continue
+		}
+		if utf8.RuneCountInString(n) > 64 {
+			return nil, fmt.Errorf("The tag %q is longer than 64 characters", n)
+		}
+		seen[n] = true
+		normalized = append(normalized, n)
+	}
+	if len(normalized) > MaxArticleTags {
+		return nil, fmt.Errorf("An article can't have more than %d tags", MaxArticleTags)
+	}
+	return normalized, nil
+}
+
+// FindOrCreateTags find the tags by their names, creating the missing ones.
+func FindOrCreateTags(ctx context.Context, names []string) ([]Tag, error) {
+	tags := []Tag{}
+	for _, n := range names {
+		tag, err := FindTagBy(ctx, "name", n)
+		if err != nil {
+			tag = &Tag{Name: n}
+			tag.Id, err = tag.Create(ctx)
+		}
+		if err != nil {
+			// it may have been created concurrently, the name is unique
+			if tag, err = FindTagBy(ctx, "name", n); err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+// SetArticleTags replace the tags of the article by the ones named, the missing tags are created.
+func SetArticleTags(ctx context.Context, articleId int64, names []string) ([]Tag, error) {
+	names, err := NormalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := FindOrCreateTags(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	current, err := ArticleGetArticleTags(ctx, articleId)
+	if err != nil {
+		return nil, err
+	}
+	keep := map[int64]bool{}
+	for _, t := range tags {
+		keep[t.Id] = true
+	}
+	stale := []int64{}
+	for _, at := range current {
+		if keep[at.TagId] {
+			delete(keep, at.TagId)
+		} else {
+			stale = append(stale, at.Id)
+		}
+	}
+	if len(stale) > 0 {
+		if _, err = DestroyArticleTags(ctx, stale...); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range tags {
+		if !keep[t.Id] {
+			continue
+		}
+		at := ArticleTag{ArticleId: articleId, TagId: t.Id}
+		if _, err = at.Create(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// TagUsages get all the tags with the counts of their articles, the most used first.
+func TagUsages(ctx context.Context) (counts []TagUsage, err error) {
+	ctx = observeQuery(ctx, "TagUsages")
+	err = DB.SelectContext(ctx, &counts, `SELECT tags.id, tags.name, COUNT(article_tags.id) AS articles_count FROM tags
+		LEFT JOIN article_tags ON article_tags.tag_id = tags.id GROUP BY tags.id, tags.name ORDER BY articles_count DESC, tags.name ASC`)
+	return counts, err
+}
+
+// LoadArticlesTags get the tags of all the articles at once into their Tags.
+func LoadArticlesTags(ctx context.Context, articles []Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	ids := make([]interface{}, len(articles))
+	for i, ar := range articles {
+		ids[i] = ar.Id
+	}
+	rows := []struct {
+		ArticleId int64 `db:"article_id"`
+		Tag
+	}{}
+	sql := `SELECT article_tags.article_id, tags.id, tags.name, tags.created_at, tags.updated_at FROM tags
+		JOIN article_tags ON article_tags.tag_id = tags.id WHERE article_tags.article_id IN (?` + strings.Repeat(",?", len(ids)-1) + `) ORDER BY tags.name`
+	if err := DB.SelectContext(ctx, &rows, DB.Rebind(sql), ids...); err != nil {
+		return err
+	}
+	index := make(map[int64]int, len(articles))
+	for i, ar := range articles {
+		index[ar.Id] = i
+	}
+	for _, r := range rows {
+		ar := &articles[index[r.ArticleId]]
+		ar.Tags = append(ar.Tags, r.Tag)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The SQL statements run by the model functions are traced and timed by a wrapper of the database driver,
+// each one is a span with the statement and the rows it returned or affected, named by the model function.
+// The spans go nowhere until a tracer provider is set, e.g. by tracing.Setup.
+
+var tracer = otel.Tracer("myapp/models")
+
+// Attributes of the statement spans besides the semantic conventions.
+const (
+	rowsReturnedKey = attribute.Key("db.response.returned_rows")
+	rowsAffectedKey = attribute.Key("db.response.affected_rows")
+)
+
+// traceStatement record the span of a statement run since start, and observe how long it took.
+// The span of a query is ended by the rows when they are closed.
+func traceStatement(ctx context.Context, system, query string, start time.Time, err error) trace.Span {
+	observeStatement(ctx, time.Since(start))
+	_, span := tracer.Start(ctx, statementVerb(query), trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(start))
+	if !span.IsRecording() {
+		return span
+	}
+	if fn := modelFunction(ctx); fn != "" {
+		span.SetName(fn)
+		span.SetAttributes(semconv.CodeFunction(fn))
+	}
+	span.SetAttributes(semconv.DBSystemKey.String(system), semconv.DBQueryText(query))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return span
+}
+
+// statementVerb get the first word of the statement, e.g. SELECT, the name of its span outside the model functions.
+func statementVerb(query string) string {
+	if f := strings.Fields(query); len(f) > 0 {
+		return strings.ToUpper(f[0])
+	}
+	return "SQL"
+}
+
+func endStatement(span trace.Span, res driver.Result) {
+	if res != nil && span.IsRecording() {
+		if n, err := res.RowsAffected(); err == nil {
+			span.SetAttributes(rowsAffectedKey.Int64(n))
+		}
+	}
+	span.End()
+}
+
+// tracedDriver wraps a database driver to trace the statements run by its connections.
+type tracedDriver struct {
+	driver.Driver
+	system string
+}
+
+func (d tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, system: d.system}, nil
+}
+
+// tracedConn passes everything through to the connection of the driver, the statements are traced.
+type tracedConn struct {
+	driver.Conn
+	system string
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (stmt driver.Stmt, err error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query, system: c.system}, nil
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	// ErrSkip makes database/sql prepare the statement instead, which is traced then
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	endStatement(traceStatement(ctx, c.system, query, start, err), res)
+	return res, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	span := traceStatement(ctx, c.system, query, start, err)
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// tracedStmt traces the runs of a prepared statement.
+type tracedStmt struct {
+	driver.Stmt
+	query  string
+	system string
+}
+
+func (s *tracedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	res, err := s.Stmt.Exec(args)
+	endStatement(traceStatement(context.Background(), s.system, s.query, start, err), res)
+	return res, err
+}
+
+func (s *tracedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.Stmt.Query(args)
+	span := traceStatement(context.Background(), s.system, s.query, start, err)
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (res driver.Result, err error) {
+	start := time.Now()
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		res, err = s.Stmt.Exec(namedValues(args))
+	}
+	endStatement(traceStatement(ctx, s.system, s.query, start, err), res)
+	return res, err
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	start := time.Now()
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedValues(args))
+	}
+	span := traceStatement(ctx, s.system, s.query, start, err)
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (s *tracedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return values
+}
+
+// tracedRows count the rows returned by a query, its span ends when they're closed.
+type tracedRows struct {
+	driver.Rows
+	span trace.Span
+	n    int64
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.n++
+	} else if err != io.EOF {
+		r.span.RecordError(err)
+		r.span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	r.span.SetAttributes(rowsReturnedKey.Int64(r.n))
+	r.span.End()
+	return err
+}
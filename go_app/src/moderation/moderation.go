@@ -0,0 +1,208 @@
+// Package moderation scores the new comments for spam and decides whether they're published,
+// held in the moderation queue or marked as spam.
+// The scorers are pluggable, the ones looking back at the former comments get them from a History,
+// so the package doesn't depend on the database.
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The statuses of a comment, only the approved comments are shown to the public.
+const (
+	Pending  = "pending"
+	Approved = "approved"
+	Rejected = "rejected"
+	Spam     = "spam"
+)
+
+// Statuses are all the statuses of a comment.
+var Statuses = []string{Pending, Approved, Rejected, Spam}
+
+// ValidStatus tell whether the status is a known one.
+func ValidStatus(s string) bool {
+	for _, v := range Statuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Comment is the new comment to be scored.
+type Comment struct {
+	ArticleId int64
+	// UserId is the id of the commenter, 0 if the comment is not from a user.
+	UserId    int64
+	Commenter string
+	Body      string
+	CreatedAt time.Time
+}
+
+// Signal is what a scorer found in a comment, the higher the score the more likely spam.
+type Signal struct {
+	Scorer string `json:"scorer"`
+	Score  int64  `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// Scorer scores a comment, a zero Score means nothing suspicious found.
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, cm Comment) (Signal, error)
+}
+
+// History tells about the former comments, for the scorers looking for repetitions.
+type History interface {
+	// CountBody count the comments with the same body since the time.
+	CountBody(ctx context.Context, body string, since time.Time) (int64, error)
+	// CountByCommenter count the comments of the same commenter since the time.
+	CountByCommenter(ctx context.Context, cm Comment, since time.Time) (int64, error)
+}
+
+// Verdict is the result of the pipeline on a comment.
+type Verdict struct {
+	Status  string   `json:"status"`
+	Score   int64    `json:"spam_score"`
+	Signals []Signal `json:"signals,omitempty"`
+}
+
+// Pipeline runs all its scorers on a comment and sums their scores:
+// below HoldAt the comment is approved, from SpamAt on it's marked as spam, else it's held as pending.
+type Pipeline struct {
+	Scorers []Scorer
+	HoldAt  int64
+	SpamAt  int64
+}
+
+// NewPipeline build the default pipeline with the blocklisted words and the history of the comments.
+func NewPipeline(blocklist []string, h History) *Pipeline {
+	return &Pipeline{
+		Scorers: []Scorer{
+			&LinkCount{Max: 2, Weight: 2},
+			&Blocklist{Words: blocklist, Weight: 3},
+			&RepeatedBody{History: h, Window: 24 * time.Hour, Weight: 3},
+			&CommenterRate{History: h, Window: 10 * time.Minute, Max: 3, Weight: 2},
+		},
+		HoldAt: 2,
+		SpamAt: 6,
+	}
+}
+
+// Run score the comment, a failing scorer holds the comment for a human to decide.
+func (p *Pipeline) Run(ctx context.Context, cm Comment) (Verdict, error) {
+	var v Verdict
+	var failed error
+	for _, s := range p.Scorers {
+		sig, err := s.Score(ctx, cm)
+		if err != nil {
+			failed = fmt.Errorf("%s scorer error: %v", s.Name(), err)
+			continue
+		}
+		if sig.Score != 0 {
+			sig.Scorer = s.Name()
+			v.Signals = append(v.Signals, sig)
+			v.Score += sig.Score
+		}
+	}
+	switch {
+	case v.Score >= p.SpamAt:
+		v.Status = Spam
+	case v.Score >= p.HoldAt || failed != nil:
+		v.Status = Pending
+	default:
+		v.Status = Approved
+	}
+	return v, failed
+}
+
+var linkRe = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// LinkCount scores Weight for each link over Max.
+type LinkCount struct {
+	Max    int
+	Weight int64
+}
+
+func (s *LinkCount) Name() string { return "link_count" }
+
+func (s *LinkCount) Score(ctx context.Context, cm Comment) (Signal, error) {
+	n := len(linkRe.FindAllString(cm.Body, -1))
+	if n <= s.Max {
+		return Signal{}, nil
+	}
+	return Signal{Score: int64(n-s.Max) * s.Weight, Reason: fmt.Sprintf("%d links", n)}, nil
+}
+
+// Blocklist scores Weight for each blocklisted word or phrase found, case insensitively.
+type Blocklist struct {
+	Words  []string
+	Weight int64
+}
+
+func (s *Blocklist) Name() string { return "blocklist" }
+
+func (s *Blocklist) Score(ctx context.Context, cm Comment) (Signal, error) {
+	body := strings.ToLower(cm.Body)
+	found := []string{}
+	for _, w := range s.Words {
+		if w != "" && strings.Contains(body, strings.ToLower(w)) {
+			found = append(found, w)
+		}
+	}
+	if len(found) == 0 {
+		return Signal{}, nil
+	}
+	return Signal{Score: int64(len(found)) * s.Weight, Reason: "blocklisted: " + strings.Join(found, ", ")}, nil
+}
+
+// RepeatedBody scores Weight when the same body was already posted within the Window, by anyone.
+type RepeatedBody struct {
+	History History
+	Window  time.Duration
+	Weight  int64
+}
+
+func (s *RepeatedBody) Name() string { return "repeated_body" }
+
+func (s *RepeatedBody) Score(ctx context.Context, cm Comment) (Signal, error) {
+	n, err := s.History.CountBody(ctx, cm.Body, cm.CreatedAt.Add(-s.Window))
+	if err != nil || n == 0 {
+		return Signal{}, err
+	}
+	return Signal{Score: s.Weight, Reason: fmt.Sprintf("same body posted %d times within %v", n, s.Window)}, nil
+}
+
+// CommenterRate scores Weight when the commenter posted more than Max comments within the Window.
+type CommenterRate struct {
+	History History
+	Window  time.Duration
+	Max     int64
+	Weight  int64
+}
+
+func (s *CommenterRate) Name() string { return "commenter_rate" }
+
+func (s *CommenterRate) Score(ctx context.Context, cm Comment) (Signal, error) {
+	n, err := s.History.CountByCommenter(ctx, cm, cm.CreatedAt.Add(-s.Window))
+	if err != nil || n < s.Max {
+		return Signal{}, err
+	}
+	return Signal{Score: s.Weight, Reason: fmt.Sprintf("%d comments within %v", n, s.Window)}, nil
+}
+
+// LoadBlocklist parse a blocklist, one word or phrase per line, the blank lines and the "#" comments are skipped.
+func LoadBlocklist(text string) []string {
+	words := []string{}
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "#") {
+			words = append(words, l)
+		}
+	}
+	return words
+}
@@ -0,0 +1,412 @@
+// Package openapi builds an OpenAPI 3.1 document, with the schemas of the request and response bodies
+// derived from the Go structs by their json tags and the govalidator rules of their valid tags.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the version of the OpenAPI specification of the documents.
+const Version = "3.1.0"
+
+// Object is a JSON object of the document, e.g. a schema.
+type Object = map[string]interface{}
+
+// Param is a parameter of an operation, in the path, the query or a header.
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      Object
+}
+
+// Response is a response of an operation, its Content is the schema by the media type.
+type Response struct {
+	Description string
+	Content     map[string]Object
+	Headers     map[string]Object
+}
+
+// Operation is a route of the API, its Path is the gin path, e.g. /articles/:id.
+type Operation struct {
+	Method      string
+	Path        string
+	Id          string
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []Param
+	// Body is the schema of the request body by the media type.
+	Body      map[string]Object
+	Responses map[string]Response
+	// Security is the names of the security schemes accepted, any of them, none for a public route.
+	Security []string
+}
+
+// Spec is an OpenAPI document being built.
+type Spec struct {
+	Info            Object
+	SecuritySchemes map[string]Object
+	// Invalid are the responses to the requests not matching the schemas, added to the operations with params or a body.
+	Invalid map[string]Response
+	ops     map[string]map[string]Operation
+	schemas map[string]Object
+	types   map[reflect.Type]string
+}
+
+// New create a document of the API with the title and the version.
+func New(title, version, description string) *Spec {
+	return &Spec{
+		Info:            Object{"title": title, "version": version, "description": description},
+		SecuritySchemes: map[string]Object{},
+		ops:             map[string]map[string]Operation{},
+		schemas:         map[string]Object{},
+		types:           map[reflect.Type]string{},
+	}
+}
+
+// ginParam is a param of a gin path, e.g. :id or *path.
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// Path convert a gin path to an OpenAPI one, e.g. /articles/:id to /articles/{id}.
+func Path(ginPath string) string {
+	return ginParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// Add add an operation, the params of its path are added if not given.
+func (s *Spec) Add(op Operation) {
+	for _, name := range ginParam.FindAllStringSubmatch(op.Path, -1) {
+		if !hasParam(op.Params, name[1], "path") {
+			op.Params = append([]Param{{Name: name[1], In: "path", Required: true, Schema: Object{"type": "string"}}}, op.Params...)
+		}
+	}
+	path := Path(op.Path)
+	if s.ops[path] == nil {
+		s.ops[path] = map[string]Operation{}
+	}
+	s.ops[path][strings.ToLower(op.Method)] = op
+}
+
+func hasParam(params []Param, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// Has tell whether the route of the method and the gin path is documented.
+func (s *Spec) Has(method, ginPath string) bool {
+	_, ok := s.ops[Path(ginPath)][strings.ToLower(method)]
+	return ok
+}
+
+// Schema get the schema of the type of v, the structs are components referenced by their names.
+func (s *Spec) Schema(v interface{}) Object {
+	return s.schemaOf(reflect.TypeOf(v))
+}
+
+// Input get the schema of a request body of the fields of the struct v by their json names,
+// with the required ones by their valid tags.
+func (s *Spec) Input(v interface{}, fields ...string) Object {
+	full := s.structSchema(reflect.TypeOf(v), true)
+	props := full["properties"].(Object)
+	required := map[string]bool{}
+	for _, name := range full["required"].([]string) {
+		required[name] = true
+	}
+	schema := Object{"type": "object", "properties": Object{}}
+	names := []string{}
+	for _, f := range fields {
+		if p, ok := props[f]; ok {
+			schema["properties"].(Object)[f] = p
+			if required[f] {
+				names = append(names, f)
+			}
+		}
+	}
+	if len(names) > 0 {
+		schema["required"] = names
+	}
+	return schema
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (s *Spec) schemaOf(t reflect.Type) Object {
+	switch {
+	case t == nil:
+		return Object{}
+	case t == timeType:
+		return Object{"type": "string", "format": "date-time"}
+	case t == rawJSONType:
+		return Object{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := s.schemaOf(t.Elem())
+		if typ, ok := schema["type"].(string); ok {
+			copied := Object{}
+			for k, v := range schema {
+				copied[k] = v
+			}
+			copied["type"] = []string{typ, "null"}
+			return copied
+		}
+		return Object{"oneOf": []Object{schema, {"type": "null"}}}
+	case reflect.Bool:
+		return Object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Object{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return Object{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return Object{"type": "number"}
+	case reflect.String:
+		return Object{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return Object{"type": "array", "items": s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return Object{"type": "object", "additionalProperties": s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t, false)
+		}
+		name, ok := s.types[t]
+		if !ok {
+			name = s.componentName(t)
+			s.types[t] = name
+			// registered before its fields for the types referencing each other, e.g. Article and Comment
+			s.schemas[name] = Object{}
+			s.schemas[name] = s.structSchema(t, false)
+		}
+		return Object{"$ref": "#/components/schemas/" + name}
+	}
+	return Object{}
+}
+
+// componentName name a component by its type, prefixed by its package on a conflict.
+func (s *Spec) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := s.schemas[name]; taken {
+		pkg := t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	return name
+}
+
+// structSchema get the schema of the exported fields of a struct by their json names, the embedded structs are
+// flattened as the JSON encoding does. The required fields are only listed with withRequired, since the responses
+// omit the empty fields.
+func (s *Spec) structSchema(t reflect.Type, withRequired bool) Object {
+	props := Object{}
+	required := []string{}
+	s.addFields(t, props, &required)
+	schema := Object{"type": "object", "properties": props}
+	if withRequired {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields add the fields of the struct to props, the fields of the embedded structs are shadowed by the outer ones.
+func (s *Spec) addFields(t reflect.Type, props Object, required *[]string) {
+	embedded, embeddedRequired := Object{}, []string{}
+	defer func() {
+		shadowed := map[string]bool{}
+		for name, schema := range embedded {
+			if _, ok := props[name]; ok {
+				shadowed[name] = true
+				continue
+			}
+			props[name] = schema
+		}
+		for _, name := range embeddedRequired {
+			if !shadowed[name] {
+				*required = append(*required, name)
+			}
+		}
+	}()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(ft, embedded, &embeddedRequired)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		schema := s.schemaOf(f.Type)
+		if rules := f.Tag.Get("valid"); rules != "" && rules != "-" {
+			if applyRules(schema, rules) {
+				*required = append(*required, name)
+			}
+		}
+		props[name] = schema
+	}
+}
+
+// validRule is a govalidator rule with its args, e.g. length(10|30).
+var validRule = regexp.MustCompile(`^([a-z]+)(?:\((.*)\))?$`)
+
+// applyRules add the constraints of the govalidator rules to the schema, and tell whether the field is required.
+func applyRules(schema Object, rules string) (required bool) {
+	for _, rule := range strings.Split(rules, ",") {
+		m := validRule.FindStringSubmatch(rule)
+		if m == nil {
+			continue
+		}
+		args := strings.Split(m[2], "|")
+		switch m[1] {
+		case "required":
+			required = true
+		case "length", "runelength", "stringlength":
+			if len(args) == 2 {
+				if min, err := strconv.Atoi(args[0]); err == nil && min > 0 {
+					schema["minLength"] = min
+				}
+				// the text columns have no max worth telling
+				if max, err := strconv.ParseInt(args[1], 10, 64); err == nil && max < 1<<32-1 {
+					schema["maxLength"] = max
+				}
+			}
+		case "range":
+			if len(args) == 2 {
+				if min, err := strconv.ParseFloat(args[0], 64); err == nil {
+					schema["minimum"] = min
+				}
+				if max, err := strconv.ParseFloat(args[1], 64); err == nil {
+					schema["maximum"] = max
+				}
+			}
+		case "in":
+			schema["enum"] = args
+		case "email":
+			schema["format"] = "email"
+		case "url":
+			schema["format"] = "uri"
+		}
+	}
+	return required
+}
+
+// MarshalJSON encode the document.
+func (s *Spec) MarshalJSON() ([]byte, error) {
+	paths := Object{}
+	for path, ops := range s.ops {
+		item := Object{}
+		for method, op := range ops {
+			item[method] = s.operationObject(op)
+		}
+		paths[path] = item
+	}
+	components := Object{"schemas": s.schemas}
+	if len(s.SecuritySchemes) > 0 {
+		components["securitySchemes"] = s.SecuritySchemes
+	}
+	return json.Marshal(Object{
+		"openapi":    Version,
+		"info":       s.Info,
+		"paths":      paths,
+		"components": components,
+	})
+}
+
+func (s *Spec) operationObject(op Operation) Object {
+	o := Object{"operationId": op.Id, "summary": op.Summary}
+	if op.Description != "" {
+		o["description"] = op.Description
+	}
+	if len(op.Tags) > 0 {
+		o["tags"] = op.Tags
+	}
+	if len(op.Params) > 0 {
+		params := []Object{}
+		for _, p := range op.Params {
+			param := Object{"name": p.Name, "in": p.In, "required": p.Required, "schema": p.Schema}
+			if p.Description != "" {
+				param["description"] = p.Description
+			}
+			params = append(params, param)
+		}
+		o["parameters"] = params
+	}
+	if len(op.Body) > 0 {
+		// the body is optional unless it has required fields
+		required := false
+		for _, schema := range op.Body {
+			_, ok := schema["required"]
+			required = required || ok
+		}
+		o["requestBody"] = Object{"required": required, "content": content(op.Body)}
+	}
+	responses := Object{}
+	all := op.Responses
+	if len(op.Params) > 0 || len(op.Body) > 0 {
+		all = map[string]Response{}
+		for code, r := range s.Invalid {
+			all[code] = r
+		}
+		for code, r := range op.Responses {
+			all[code] = r
+		}
+	}
+	for code, r := range all {
+		resp := Object{"description": r.Description}
+		if len(r.Content) > 0 {
+			resp["content"] = content(r.Content)
+		}
+		if len(r.Headers) > 0 {
+			headers := Object{}
+			for name, schema := range r.Headers {
+				headers[name] = Object{"schema": schema}
+			}
+			resp["headers"] = headers
+		}
+		responses[code] = resp
+	}
+	o["responses"] = responses
+	if len(op.Security) > 0 {
+		security := []Object{}
+		for _, name := range op.Security {
+			security = append(security, Object{name: []string{}})
+		}
+		o["security"] = security
+	}
+	return o
+}
+
+func content(schemas map[string]Object) Object {
+	c := Object{}
+	for mediaType, schema := range schemas {
+		c[mediaType] = Object{"schema": schema}
+	}
+	return c
+}
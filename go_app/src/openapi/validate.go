@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/asaskevich/govalidator"
+)
+
+// Violation is a part of a request not matching its schema, at the JSON pointer of the body, e.g. /tags/0,
+// or of the param, e.g. /limit.
+type Violation struct {
+	In      string `json:"in"`
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// Operation find the operation of the method and the gin path.
+func (s *Spec) Operation(method, ginPath string) (Operation, bool) {
+	op, ok := s.ops[Path(ginPath)][strings.ToLower(method)]
+	return op, ok
+}
+
+// ValidateParam check the values of a param against its schema, the values are converted to the type of the schema.
+// An array param takes all the values, the others the first one.
+func (s *Spec) ValidateParam(p Param, values []string) []Violation {
+	var v interface{}
+	schema := s.resolve(p.Schema)
+	if schemaTypes(schema)["array"] {
+		items := make([]interface{}, len(values))
+		for i, value := range values {
+			items[i] = paramValue(s.resolve(schemaItems(schema)), value)
+		}
+		v = items
+	} else if len(values) > 0 {
+		v = paramValue(schema, values[0])
+	}
+	var out []Violation
+	s.validate(p.Schema, v, p.In, "/"+escapePointer(p.Name), false, &out)
+	return out
+}
+
+// paramValue convert the value of a param to the type of its schema, it's kept as a string if it can't be,
+// which is then reported by the type check.
+func paramValue(schema Object, value string) interface{} {
+	types := schemaTypes(schema)
+	if types["integer"] || types["number"] {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	}
+	if types["boolean"] {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// ValidateJSON check a JSON body against the schema, with strict the fields unknown to the schema are violations.
+func (s *Spec) ValidateJSON(schema Object, body []byte, strict bool) []Violation {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return []Violation{{In: "body", Pointer: "", Message: "Invalid JSON: " + err.Error()}}
+	}
+	if d.More() {
+		return []Violation{{In: "body", Pointer: "", Message: "Invalid JSON: more than one value"}}
+	}
+	var out []Violation
+	s.validate(schema, v, "body", "", strict, &out)
+	return out
+}
+
+// resolve get the component of a $ref schema.
+func (s *Spec) resolve(schema Object) Object {
+	for {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		schema = s.schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	}
+}
+
+func (s *Spec) validate(schema Object, v interface{}, in, pointer string, strict bool, out *[]Violation) {
+	schema = s.resolve(schema)
+	report := func(format string, args ...interface{}) {
+		*out = append(*out, Violation{In: in, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	if all, ok := schema["allOf"].([]Object); ok {
+		// the parts only know their own fields
+		for _, part := range all {
+			s.validate(part, v, in, pointer, false, out)
+		}
+	}
+	if one, ok := schema["oneOf"].([]Object); ok {
+		matched := 0
+		for _, part := range one {
+			var partOut []Violation
+			s.validate(part, v, in, pointer, strict, &partOut)
+			if len(partOut) == 0 {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			report("should match one of the schemas")
+		case matched > 1:
+			report("should match only one of the schemas")
+		}
+	}
+	types := schemaTypes(schema)
+	if len(types) > 0 && !types[jsonType(v)] && !(types["number"] && jsonType(v) == "integer") {
+		names := make([]string, 0, len(types))
+		for t := range types {
+			names = append(names, t)
+		}
+		sort.Strings(names)
+		report("should be %s", strings.Join(names, " or "))
+		return
+	}
+	if enum := schemaEnum(schema); enum != nil && v != nil {
+		found := false
+		for _, e := range enum {
+			found = found || fmt.Sprint(v) == e
+		}
+		if !found {
+			report("should be one of %s", strings.Join(enum, ", "))
+		}
+	}
+	switch v := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if min, ok := number(schema["minLength"]); ok && float64(n) < min {
+			report("should be at least %v characters", min)
+		}
+		if max, ok := number(schema["maxLength"]); ok && float64(n) > max {
+			report("should be at most %v characters", max)
+		}
+		switch schema["format"] {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				report("should be a RFC3339 time")
+			}
+		case "email":
+			if !govalidator.IsEmail(v) {
+				report("should be an email")
+			}
+		}
+	case json.Number:
+		f, _ := v.Float64()
+		if min, ok := number(schema["minimum"]); ok && f < min {
+			report("should be at least %v", min)
+		}
+		if max, ok := number(schema["maximum"]); ok && f > max {
+			report("should be at most %v", max)
+		}
+	case []interface{}:
+		if items := schemaItems(schema); items != nil {
+			for i, item := range v {
+				s.validate(items, item, in, pointer+"/"+strconv.Itoa(i), strict, out)
+			}
+		}
+	case map[string]interface{}:
+		props, _ := schema["properties"].(Object)
+		for _, name := range schemaRequired(schema) {
+			if _, ok := v[name]; !ok {
+				*out = append(*out, Violation{In: in, Pointer: pointer + "/" + escapePointer(name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		additional, hasAdditional := schema["additionalProperties"].(Object)
+		for _, name := range names {
+			p := pointer + "/" + escapePointer(name)
+			if prop, ok := props[name].(Object); ok {
+				s.validate(prop, v[name], in, p, strict, out)
+			} else if hasAdditional {
+				s.validate(additional, v[name], in, p, strict, out)
+			} else if strict && props != nil {
+				*out = append(*out, Violation{In: in, Pointer: p, Message: "is an unknown field"})
+			}
+		}
+	}
+}
+
+// jsonType get the type of a decoded JSON value as named by the schemas.
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
+
+func schemaTypes(schema Object) map[string]bool {
+	types := map[string]bool{}
+	switch t := schema["type"].(type) {
+	case string:
+		types[t] = true
+	case []string:
+		for _, name := range t {
+			types[name] = true
+		}
+	}
+	return types
+}
+
+func schemaItems(schema Object) Object {
+	items, _ := schema["items"].(Object)
+	return items
+}
+
+func schemaEnum(schema Object) []string {
+	enum, _ := schema["enum"].([]string)
+	return enum
+}
+
+func schemaRequired(schema Object) []string {
+	required, _ := schema["required"].([]string)
+	return required
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// escapePointer escape a name in a JSON pointer, as of RFC 6901.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testTag struct {
+	Name string `json:"name" valid:"required,length(1|5)"`
+}
+
+type testArticle struct {
+	Title  string            `json:"title" valid:"required,length(3|10)"`
+	Status string            `json:"status" valid:"in(draft|published)"`
+	Stars  int               `json:"stars" valid:"range(0|5)"`
+	Tags   []testTag         `json:"tags"`
+	Meta   map[string]string `json:"meta"`
+	Email  string            `json:"email" valid:"email"`
+	At     *string           `json:"at"`
+}
+
+func testSpec() (*Spec, Object) {
+	s := New("test", "1", "")
+	return s, s.Input(testArticle{}, "title", "status", "stars", "tags", "meta", "email")
+}
+
+func TestValidateJSON(t *testing.T) {
+	s, schema := testSpec()
+	tests := []struct {
+		name   string
+		body   string
+		strict bool
+		want   []Violation
+	}{
+		{"valid", `{"title":"Hello","status":"draft","stars":3,"tags":[{"name":"go"}],"meta":{"a":"b"}}`, true, nil},
+		{"missing required", `{"status":"draft"}`, false, []Violation{{"body", "/title", "is required"}}},
+		{"wrong type", `{"title":42}`, false, []Violation{{"body", "/title", "should be string"}}},
+		{"too short", `{"title":"Hi"}`, false, []Violation{{"body", "/title", "should be at least 3 characters"}}},
+		{"runes not bytes", `{"title":"ééééééééé"}`, false, nil},
+		{"not in enum", `{"title":"Hello","status":"gone"}`, false, []Violation{{"body", "/status", "should be one of draft, published"}}},
+		{"out of range", `{"title":"Hello","stars":6}`, false, []Violation{{"body", "/stars", "should be at most 5"}}},
+		{"not an integer", `{"title":"Hello","stars":1.5}`, false, []Violation{{"body", "/stars", "should be integer"}}},
+		{"not an email", `{"title":"Hello","email":"nope"}`, false, []Violation{{"body", "/email", "should be an email"}}},
+		{"nested item", `{"title":"Hello","tags":[{"name":"go"},{"name":"toolong"}]}`, false, []Violation{{"body", "/tags/1/name", "should be at most 5 characters"}}},
+		{"additional properties", `{"title":"Hello","meta":{"a/b":1}}`, false, []Violation{{"body", "/meta/a~1b", "should be string"}}},
+		{"unknown field", `{"title":"Hello","x~y":1}`, false, nil},
+		{"unknown field strict", `{"title":"Hello","x~y":1}`, true, []Violation{{"body", "/x~0y", "is an unknown field"}}},
+		{"unknown nested field strict", `{"title":"Hello","tags":[{"name":"go","color":"red"}]}`, true, []Violation{{"body", "/tags/0/color", "is an unknown field"}}},
+		{"not an object", `[]`, false, []Violation{{"body", "", "should be object"}}},
+		{"invalid JSON", `{"title":`, false, []Violation{{"body", "", "Invalid JSON: unexpected EOF"}}},
+		{"two values", `{} {}`, false, []Violation{{"body", "", "Invalid JSON: more than one value"}}},
+	}
+	for _, tt := range tests {
+		got := s.ValidateJSON(schema, []byte(tt.body), tt.strict)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ValidateJSON = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateJSONNullable(t *testing.T) {
+	s := New("test", "1", "")
+	schema := s.Schema(testArticle{})
+	for body, ok := range map[string]bool{`{"at":null}`: true, `{"at":"x"}`: true, `{"at":1}`: false} {
+		if got := s.ValidateJSON(schema, []byte(body), false); (len(got) == 0) != ok {
+			t.Errorf("ValidateJSON(%s) = %v, want valid %v", body, got, ok)
+		}
+	}
+}
+
+func TestValidateJSONCombined(t *testing.T) {
+	s := New("test", "1", "")
+	named := Object{"type": "object", "properties": Object{"name": Object{"type": "string"}}, "required": []string{"name"}}
+	aged := Object{"type": "object", "properties": Object{"age": Object{"type": "integer"}}}
+	all := Object{"allOf": []Object{named, aged}}
+	one := Object{"oneOf": []Object{{"type": "string"}, {"type": "integer"}}}
+	overlapping := Object{"oneOf": []Object{{"type": "number"}, {"type": "integer"}}}
+	tests := []struct {
+		name   string
+		schema Object
+		body   string
+		strict bool
+		want   []Violation
+	}{
+		{"allOf", all, `{"name":"a","age":1}`, true, nil},
+		{"allOf part", all, `{"age":"old"}`, false, []Violation{{"body", "/name", "is required"}, {"body", "/age", "should be integer"}}},
+		{"oneOf", one, `3`, false, nil},
+		{"oneOf none", one, `true`, false, []Violation{{"body", "", "should match one of the schemas"}}},
+		{"oneOf several", overlapping, `3`, false, []Violation{{"body", "", "should match only one of the schemas"}}},
+		{"oneOf exactly one", overlapping, `1.5`, false, nil},
+	}
+	for _, tt := range tests {
+		got := s.ValidateJSON(tt.schema, []byte(tt.body), tt.strict)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ValidateJSON = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateParam(t *testing.T) {
+	s := New("test", "1", "")
+	limit := Param{Name: "limit", In: "query", Schema: Object{"type": "integer", "minimum": 1, "maximum": 100}}
+	ids := Param{Name: "ids/all", In: "query", Schema: Object{"type": "array", "items": Object{"type": "integer"}}}
+	flag := Param{Name: "tree", In: "query", Schema: Object{"type": "boolean"}}
+	tests := []struct {
+		name   string
+		param  Param
+		values []string
+		want   []Violation
+	}{
+		{"integer", limit, []string{"50"}, nil},
+		{"first value only", limit, []string{"50", "x"}, nil},
+		{"not an integer", limit, []string{"x"}, []Violation{{"query", "/limit", "should be integer"}}},
+		{"too large", limit, []string{"500"}, []Violation{{"query", "/limit", "should be at most 100"}}},
+		{"array", ids, []string{"1", "2"}, nil},
+		{"array item", ids, []string{"1", "x"}, []Violation{{"query", "/ids~1all/1", "should be integer"}}},
+		{"boolean", flag, []string{"true"}, nil},
+		{"not a boolean", flag, []string{"maybe"}, []Violation{{"query", "/tree", "should be boolean"}}},
+	}
+	for _, tt := range tests {
+		got := s.ValidateParam(tt.param, tt.values)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ValidateParam = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package policy decides who can do what on the articles and comments.
+// It only depends on the roles and the ownership of the records, neither on HTTP nor the database,
+// so every rule can be checked with plain values.
+package policy
+
+import (
+	"fmt"
+	"time"
+)
+
+// Role of a user, from the least to the most privileged.
+type Role string
+
+const (
+	// Reader can only read.
+	Reader Role = "reader"
+	// Commenter can comment, and edit the own comments within CommentEditWindow.
+	Commenter Role = "commenter"
+	// Editor can write articles and update any article.
+	Editor Role = "editor"
+	// Moderator can moderate and delete any comment.
+	Moderator Role = "moderator"
+	// Admin can do everything.
+	Admin Role = "admin"
+)
+
+// Roles are all the roles, from the least to the most privileged.
+var Roles = []Role{Reader, Commenter, Editor, Moderator, Admin}
+
+// CommentEditWindow is how long commenters can edit their comments after posting.
+var CommentEditWindow = 15 * time.Minute
+
+// Valid tell whether the role is a known one.
+func (r Role) Valid() bool {
+	for _, v := range Roles {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor is the user doing an action, a nil *Actor is an anonymous visitor.
+type Actor struct {
+	Id   int64
+	Role Role
+}
+
+// Resource is a record an action is done on.
+type Resource struct {
+	// OwnerId is the id of the user owning the record, 0 for none.
+	OwnerId   int64
+	CreatedAt time.Time
+}
+
+// Decision is the result of a policy, with the reason if denied.
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+var allow = Decision{Allowed: true}
+
+func deny(format string, args ...interface{}) Decision {
+	return Decision{Reason: fmt.Sprintf(format, args...)}
+}
+
+func (a *Actor) is(roles ...Role) bool {
+	if a == nil {
+		return false
+	}
+	for _, r := range roles {
+		if a.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Actor) owns(res Resource) bool {
+	return a != nil && res.OwnerId != 0 && res.OwnerId == a.Id
+}
+
+// signedIn deny the anonymous visitors and the readers, who can't write anything.
+func signedIn(a *Actor) (Decision, bool) {
+	if a == nil {
+		return deny("No user associated with the credentials"), false
+	}
+	if !a.Role.Valid() {
+		return deny("Unknown role %q", a.Role), false
+	}
+	if a.Role == Reader {
+		return deny("Readers can't write anything"), false
+	}
+	return allow, true
+}
+
+// ReadArticle decide whether the actor can read articles, which are public.
+func ReadArticle(a *Actor) Decision {
+	return allow
+}
+
+// CreateArticle decide whether the actor can write a new article.
+func CreateArticle(a *Actor) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	if a.is(Editor, Admin) {
+		return allow
+	}
+	return deny("Only editors can write articles")
+}
+
+// UpdateArticle decide whether the actor can update the article.
+func UpdateArticle(a *Actor, ar Resource) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	if a.is(Editor, Admin) || a.owns(ar) {
+		return allow
+	}
+	return deny("Only the author or an editor can update the article")
+}
+
+// ReadUnpublishedArticle decide whether the actor can read the article while it isn't published,
+// as a draft, scheduled or archived.
+func ReadUnpublishedArticle(a *Actor, ar Resource) Decision {
+	if a.is(Editor, Admin) || a.owns(ar) {
+		return allow
+	}
+	return deny("Only the author or an editor can read the unpublished article")
+}
+
+// PublishArticle decide whether the actor can publish, schedule, unpublish or archive the article.
+func PublishArticle(a *Actor, ar Resource) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	if a.is(Editor, Admin) || a.owns(ar) {
+		return allow
+	}
+	return deny("Only the author or an editor can publish the article")
+}
+
+// DestroyArticle decide whether the actor can destroy the article along with its comments.
+func DestroyArticle(a *Actor, ar Resource) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	if a.is(Admin) || a.owns(ar) {
+		return allow
+	}
+	return deny("Only the author or an admin can destroy the article")
+}
+
+// ReadComment decide whether the actor can read comments, which are public.
+func ReadComment(a *Actor) Decision {
+	return allow
+}
+
+// CreateComment decide whether the actor can comment.
+func CreateComment(a *Actor) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	return allow
+}
+
+// UpdateComment decide whether the actor can edit the comment at the time now.
+// Commenters can edit their own comments within CommentEditWindow, the other roles any time.
+func UpdateComment(a *Actor, cm Resource, now time.Time) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	if a.is(Admin) {
+		return allow
+	}
+	if !a.owns(cm) {
+		return deny("Only the commenter can edit the comment")
+	}
+	if a.is(Commenter) && now.Sub(cm.CreatedAt) > CommentEditWindow {
+		return deny("Comments can only be edited within %v after posting", CommentEditWindow)
+	}
+	return allow
+}
+
+// DestroyComment decide whether the actor can delete the comment.
+func DestroyComment(a *Actor, cm Resource) Decision {
+	if d, ok := signedIn(a); !ok {
+		return d
+	}
+	if a.is(Moderator, Admin) || a.owns(cm) {
+		return allow
+	}
+	return deny("Only the commenter or a moderator can delete the comment")
+}
+
+// ModerateComment decide whether the actor can review the held comments, approve or reject them.
+// The comments of the moderators skip the moderation queue as well.
+func ModerateComment(a *Actor) Decision {
+	if a.is(Moderator, Admin) {
+		return allow
+	}
+	return deny("Only moderators can moderate comments")
+}
+
+// Import decide whether the actor can import records in bulk, which may belong to any user.
+func Import(a *Actor) Decision {
+	if a.is(Admin) {
+		return allow
+	}
+	return deny("Only admins can import")
+}
+
+// ReadAudit decide whether the actor can read the audit log of the writes.
+func ReadAudit(a *Actor) Decision {
+	if a.is(Admin) {
+		return allow
+	}
+	return deny("Only admins can read the audit log")
+}
@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	anonymous *Actor
+	reader    = &Actor{Id: 1, Role: Reader}
+	commenter = &Actor{Id: 2, Role: Commenter}
+	editor    = &Actor{Id: 3, Role: Editor}
+	moderator = &Actor{Id: 4, Role: Moderator}
+	admin     = &Actor{Id: 5, Role: Admin}
+	unknown   = &Actor{Id: 6, Role: "owner"}
+)
+
+func TestPolicies(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	ownedBy := func(a *Actor) Resource { return Resource{OwnerId: a.Id, CreatedAt: now} }
+	ownerless := Resource{CreatedAt: now}
+	tests := []struct {
+		name    string
+		got     Decision
+		allowed bool
+	}{
+		{"anonymous reads articles", ReadArticle(anonymous), true},
+		{"anonymous reads comments", ReadComment(anonymous), true},
+		{"anonymous creates an article", CreateArticle(anonymous), false},
+		{"anonymous comments", CreateComment(anonymous), false},
+		{"anonymous updates an article", UpdateArticle(anonymous, ownerless), false},
+		{"anonymous reads an unpublished article", ReadUnpublishedArticle(anonymous, ownerless), false},
+		{"unknown role comments", CreateComment(unknown), false},
+
+		{"reader comments", CreateComment(reader), false},
+		{"reader creates an article", CreateArticle(reader), false},
+		{"reader updates the own article", UpdateArticle(reader, ownedBy(reader)), false},
+		{"reader destroys the own comment", DestroyComment(reader, ownedBy(reader)), false},
+		{"reader reads the own unpublished article", ReadUnpublishedArticle(reader, ownedBy(reader)), true},
+
+		{"commenter comments", CreateComment(commenter), true},
+		{"commenter creates an article", CreateArticle(commenter), false},
+		{"editor creates an article", CreateArticle(editor), true},
+		{"admin creates an article", CreateArticle(admin), true},
+		{"moderator creates an article", CreateArticle(moderator), false},
+
+		{"commenter updates the own article", UpdateArticle(commenter, ownedBy(commenter)), true},
+		{"commenter updates another's article", UpdateArticle(commenter, ownedBy(editor)), false},
+		{"editor updates another's article", UpdateArticle(editor, ownedBy(commenter)), true},
+		{"commenter updates an ownerless article", UpdateArticle(commenter, ownerless), false},
+		{"editor reads another's unpublished article", ReadUnpublishedArticle(editor, ownedBy(commenter)), true},
+		{"moderator reads another's unpublished article", ReadUnpublishedArticle(moderator, ownedBy(commenter)), false},
+		{"commenter publishes the own article", PublishArticle(commenter, ownedBy(commenter)), true},
+		{"moderator publishes another's article", PublishArticle(moderator, ownedBy(commenter)), false},
+
+		{"editor destroys another's article", DestroyArticle(editor, ownedBy(commenter)), false},
+		{"editor destroys the own article", DestroyArticle(editor, ownedBy(editor)), true},
+		{"admin destroys another's article", DestroyArticle(admin, ownedBy(editor)), true},
+
+		{"commenter deletes the own comment", DestroyComment(commenter, ownedBy(commenter)), true},
+		{"commenter deletes another's comment", DestroyComment(commenter, ownedBy(editor)), false},
+		{"moderator deletes another's comment", DestroyComment(moderator, ownedBy(commenter)), true},
+		{"editor deletes another's comment", DestroyComment(editor, ownedBy(commenter)), false},
+
+		{"commenter moderates", ModerateComment(commenter), false},
+		{"moderator moderates", ModerateComment(moderator), true},
+		{"admin moderates", ModerateComment(admin), true},
+		{"editor imports", Import(editor), false},
+		{"admin imports", Import(admin), true},
+		{"moderator reads the audit log", ReadAudit(moderator), false},
+		{"admin reads the audit log", ReadAudit(admin), true},
+	}
+	for _, tt := range tests {
+		if tt.got.Allowed != tt.allowed {
+			t.Errorf("%s: allowed %v, want %v (%s)", tt.name, tt.got.Allowed, tt.allowed, tt.got.Reason)
+		}
+		if !tt.got.Allowed && tt.got.Reason == "" {
+			t.Errorf("%s: denied without a reason", tt.name)
+		}
+	}
+}
+
+func TestUpdateCommentWindow(t *testing.T) {
+	posted := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	within := posted.Add(CommentEditWindow)
+	after := posted.Add(CommentEditWindow + time.Second)
+	tests := []struct {
+		name    string
+		actor   *Actor
+		ownerId int64
+		now     time.Time
+		allowed bool
+	}{
+		{"commenter right after posting", commenter, commenter.Id, posted, true},
+		{"commenter at the end of the window", commenter, commenter.Id, within, true},
+		{"commenter after the window", commenter, commenter.Id, after, false},
+		{"commenter on another's comment", commenter, editor.Id, posted, false},
+		{"editor on the own comment after the window", editor, editor.Id, after, true},
+		{"moderator on another's comment", moderator, commenter.Id, posted, false},
+		{"admin on another's comment after the window", admin, commenter.Id, after, true},
+		{"reader on the own comment", reader, reader.Id, posted, false},
+		{"anonymous", anonymous, commenter.Id, posted, false},
+	}
+	for _, tt := range tests {
+		d := UpdateComment(tt.actor, Resource{OwnerId: tt.ownerId, CreatedAt: posted}, tt.now)
+		if d.Allowed != tt.allowed {
+			t.Errorf("%s: allowed %v, want %v (%s)", tt.name, d.Allowed, tt.allowed, d.Reason)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of takes between the sweeps of the idle buckets.
+const sweepEvery = 1024
+
+// MemoryStore keeps the buckets in the process, so every instance of the app limits on its own.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	takes   int
+}
+
+type memoryBucket struct {
+	bucket
+	limit Limit
+}
+
+// NewMemoryStore create an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(key string, l Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		s.buckets[key] = b
+	}
+	b.limit = l
+	return b.take(l, now), nil
+}
+
+// sweep drop the buckets full again, they are the same as the new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	for k, b := range s.buckets {
+		if b.idle(b.limit, now) {
+			delete(s.buckets, k)
+		}
+	}
+}
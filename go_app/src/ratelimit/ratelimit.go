@@ -0,0 +1,128 @@
+// Package ratelimit implements token bucket rate limiting with pluggable stores of the buckets.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst tokens,
+// every request takes a token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Per create a limit of n requests per duration d, with a burst of n.
+func Per(n int, d time.Duration) Limit {
+	return Limit{Rate: float64(n) / d.Seconds(), Burst: n}
+}
+
+// ParseLimit parse a limit like "5/1m" (5 requests per minute) or "5/1m,burst=10".
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.Split(strings.TrimSpace(s), ",")
+	nd := strings.SplitN(parts[0], "/", 2)
+	if len(nd) != 2 {
+		return Limit{}, fmt.Errorf("Invalid rate limit %q, N/DURATION expected", s)
+	}
+	n, err := strconv.Atoi(nd[0])
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("Invalid number of requests in rate limit %q", s)
+	}
+	d, err := time.ParseDuration(nd[1])
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("Invalid duration in rate limit %q", s)
+	}
+	l := Per(n, d)
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) != 2 || kv[0] != "burst" {
+			return Limit{}, fmt.Errorf("Invalid option %q in rate limit %q", p, s)
+		}
+		if l.Burst, err = strconv.Atoi(kv[1]); err != nil || l.Burst <= 0 {
+			return Limit{}, fmt.Errorf("Invalid burst in rate limit %q", s)
+		}
+	}
+	return l, nil
+}
+
+// ParseRules parse the per route limits like "POST /comments=5/1m;POST /articles=10/1h,burst=2",
+// the routes are the gin route patterns, e.g. "PUT /articles/:id".
+func ParseRules(s string) (map[string]Limit, error) {
+	rules := map[string]Limit{}
+	for _, rule := range strings.Split(s, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		i := strings.LastIndex(rule, "=")
+		if j := strings.Index(rule, ",burst="); j >= 0 {
+			i = strings.LastIndex(rule[:j], "=")
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("Invalid rate limit rule %q, ROUTE=LIMIT expected", rule)
+		}
+		l, err := ParseLimit(rule[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		rules[strings.Join(strings.Fields(rule[:i]), " ")] = l
+	}
+	return rules, nil
+}
+
+// Result is the state of a bucket after taking a token.
+type Result struct {
+	Allowed bool
+	// Limit is the capacity of the bucket.
+	Limit int
+	// Remaining is the number of requests allowed right now.
+	Remaining int
+	// RetryAfter is how long to wait for a token when denied.
+	RetryAfter time.Duration
+	// Reset is how long the bucket takes to be full again.
+	Reset time.Duration
+}
+
+// Store keeps the buckets, Take takes a token from the bucket of key.
+type Store interface {
+	Take(key string, l Limit, now time.Time) (Result, error)
+}
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	Tokens float64
+	Last   time.Time
+}
+
+// take refill the bucket till now then take a token from it if any.
+func (b *bucket) take(l Limit, now time.Time) Result {
+	burst := float64(l.Burst)
+	if b.Last.IsZero() {
+		b.Tokens = burst
+	} else if elapsed := now.Sub(b.Last).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed*l.Rate)
+	}
+	b.Last = now
+	res := Result{Limit: l.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.Tokens) / l.Rate)
+	}
+	res.Remaining = int(b.Tokens)
+	res.Reset = seconds((burst - b.Tokens) / l.Rate)
+	return res
+}
+
+// idle tell the bucket is full again at now, so it can be dropped.
+func (b *bucket) idle(l Limit, now time.Time) bool {
+	return b.Tokens+now.Sub(b.Last).Seconds()*l.Rate >= float64(l.Burst)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Limit
+		ok   bool
+	}{
+		{"5/1m", Limit{Rate: 5.0 / 60, Burst: 5}, true},
+		{" 10/1h ", Limit{Rate: 10.0 / 3600, Burst: 10}, true},
+		{"5/1m,burst=10", Limit{Rate: 5.0 / 60, Burst: 10}, true},
+		{"5/1m, burst=1", Limit{Rate: 5.0 / 60, Burst: 1}, true},
+		{"5", Limit{}, false},
+		{"0/1m", Limit{}, false},
+		{"x/1m", Limit{}, false},
+		{"5/0s", Limit{}, false},
+		{"5/minute", Limit{}, false},
+		{"5/1m,burst=0", Limit{}, false},
+		{"5/1m,rate=2", Limit{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseLimit(%q) error = %v, want ok %v", tt.in, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	got, err := ParseRules("POST /comments=5/1m; PUT  /articles/:id=10/1h,burst=2;")
+	if err != nil {
+		t.Fatalf("ParseRules error: %v", err)
+	}
+	want := map[string]Limit{
+		"POST /comments":    {Rate: 5.0 / 60, Burst: 5},
+		"PUT /articles/:id": {Rate: 10.0 / 3600, Burst: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRules = %v, want %v", got, want)
+	}
+	if got, err := ParseRules(""); err != nil || len(got) != 0 {
+		t.Errorf("ParseRules of nothing = %v, %v, want no rules", got, err)
+	}
+	for _, in := range []string{"POST /comments", "POST /comments=5"} {
+		if _, err := ParseRules(in); err == nil {
+			t.Errorf("ParseRules(%q) gave no error", in)
+		}
+	}
+}
+
+// testStore take tokens from a bucket of 2 refilled with a token per second.
+func testStore(t *testing.T, name string, s Store) {
+	l := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+	steps := []struct {
+		key        string
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{"a", 0, true, 1, 0},
+		{"a", 0, true, 0, 0},
+		{"a", 0, false, 0, time.Second},
+		{"b", 0, true, 1, 0},
+		{"a", 500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+		{"a", time.Second, true, 0, 0},
+		{"a", 10 * time.Second, true, 1, 0},
+	}
+	for i, st := range steps {
+		res, err := s.Take(st.key, l, now.Add(st.at))
+		if err != nil {
+			t.Fatalf("%s: take #%d error: %v", name, i+1, err)
+		}
+		if res.Allowed != st.allowed || res.Remaining != st.remaining || res.RetryAfter != st.retryAfter || res.Limit != l.Burst {
+			t.Errorf("%s: take #%d of %q at %v = %+v, want allowed %v, remaining %d, retry after %v",
+				name, i+1, st.key, st.at, res, st.allowed, st.remaining, st.retryAfter)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, "memory", NewMemoryStore())
+}
+
+func TestSharedStore(t *testing.T) {
+	testStore(t, "shared", &SharedStore{Backend: NewLocalBackend(), Prefix: "test:"})
+}
+
+// conflictingBackend loses every compare-and-swap, as if other instances always took first.
+type conflictingBackend struct{}
+
+func (conflictingBackend) Get(key string) ([]byte, uint64, error) { return nil, 0, nil }
+
+func (conflictingBackend) CompareAndSwap(key string, version uint64, value []byte, ttl time.Duration) (bool, error) {
+	return false, nil
+}
+
+func TestSharedStoreConflicts(t *testing.T) {
+	s := &SharedStore{Backend: conflictingBackend{}}
+	if _, err := s.Take("a", Per(1, time.Second), time.Now()); err == nil {
+		t.Errorf("Take gave no error after %d conflicts", casRetries)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore()
+	l := Per(1, time.Second)
+	now := time.Now()
+	s.Take("idle", l, now)
+	for i := 1; i < sweepEvery; i++ {
+		s.Take("busy", l, now.Add(time.Minute))
+	}
+	if _, ok := s.buckets["idle"]; ok {
+		t.Errorf("The bucket full again wasn't swept")
+	}
+	if _, ok := s.buckets["busy"]; !ok {
+		t.Errorf("The bucket in use was swept")
+	}
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// casRetries is how many times SharedStore retries a take conflicting with other instances.
+const casRetries = 8
+
+// Backend is a key-value store shared by all the instances of the app, e.g. Redis or memcached,
+// with a compare-and-swap on versioned values. The values expire after ttl.
+type Backend interface {
+	// Get get the value and its version of key, a missing key is a nil value with version 0.
+	Get(key string) (value []byte, version uint64, err error)
+	// CompareAndSwap set the value of key if its version is still version, ok is false otherwise.
+	CompareAndSwap(key string, version uint64, value []byte, ttl time.Duration) (ok bool, err error)
+}
+
+// SharedStore keeps the buckets in a Backend, so the limits are shared by all the instances.
+type SharedStore struct {
+	Backend Backend
+	// Prefix is prepended to the keys in the backend.
+	Prefix string
+}
+
+// Take implements Store.
+func (s *SharedStore) Take(key string, l Limit, now time.Time) (Result, error) {
+	key = s.Prefix + key
+	// keep the bucket till it's full again, then it's the same as a missing one
+	ttl := seconds(float64(l.Burst)/l.Rate) + time.Second
+	for i := 0; i < casRetries; i++ {
+		v, ver, err := s.Backend.Get(key)
+		if err != nil {
+			return Result{}, err
+		}
+		b := bucket{}
+		if v != nil {
+			if err = json.Unmarshal(v, &b); err != nil {
+				return Result{}, err
+			}
+		}
+		res := b.take(l, now)
+		if v, err = json.Marshal(b); err != nil {
+			return Result{}, err
+		}
+		ok, err := s.Backend.CompareAndSwap(key, ver, v, ttl)
+		if err != nil {
+			return Result{}, err
+		}
+		if ok {
+			return res, nil
+		}
+	}
+	return Result{}, errors.New("Too many conflicts on the rate limit bucket " + key)
+}
+
+// LocalBackend is an in-process stand-in of a shared Backend, for development and single instances.
+type LocalBackend struct {
+	mu      sync.Mutex
+	entries map[string]localEntry
+}
+
+type localEntry struct {
+	value   []byte
+	version uint64
+	expires time.Time
+}
+
+// NewLocalBackend create an empty LocalBackend.
+func NewLocalBackend() *LocalBackend {
+	return &LocalBackend{entries: map[string]localEntry{}}
+}
+
+// Get implements Backend.
+func (b *LocalBackend) Get(key string) ([]byte, uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, 0, nil
+	}
+	return e.value, e.version, nil
+}
+
+// CompareAndSwap implements Backend.
+func (b *LocalBackend) CompareAndSwap(key string, version uint64, value []byte, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	e, ok := b.entries[key]
+	if !ok || now.After(e.expires) {
+		e = localEntry{}
+	}
+	if e.version != version {
+		return false, nil
+	}
+	b.entries[key] = localEntry{value: value, version: version + 1, expires: now.Add(ttl)}
+	if len(b.entries)%sweepEvery == 0 {
+		for k, e := range b.entries {
+			if now.After(e.expires) {
+				delete(b.entries, k)
+			}
+		}
+	}
+	return true, nil
+}
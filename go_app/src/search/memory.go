@@ -0,0 +1,144 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// titleWeight is how many times a word in a title counts.
+const titleWeight = 2
+
+type docKey struct {
+	typ string
+	id  int64
+}
+
+// MemoryBackend is an in-process inverted index ranked by BM25, the fallback when there is no search database.
+// It's lost on restart, so the server rebuilds it at the start.
+type MemoryBackend struct {
+	mu       sync.RWMutex
+	docs     map[docKey]Document
+	lens     map[docKey]int
+	totalLen int
+	postings map[string]map[docKey]int
+}
+
+// NewMemoryBackend create an empty in-process index.
+func NewMemoryBackend() *MemoryBackend {
+	b := &MemoryBackend{}
+	b.Reset()
+	return b
+}
+
+func (b *MemoryBackend) Name() string { return "memory" }
+
+// Reset remove all the documents.
+func (b *MemoryBackend) Reset() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.docs = map[docKey]Document{}
+	b.lens = map[docKey]int{}
+	b.totalLen = 0
+	b.postings = map[string]map[docKey]int{}
+	return nil
+}
+
+// Index add or replace the documents.
+func (b *MemoryBackend) Index(docs ...Document) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, d := range docs {
+		k := docKey{d.Type, d.Id}
+		b.remove(k)
+		freqs := map[string]int{}
+		n := 0
+		for _, t := range Tokenize(d.Title) {
+			freqs[t] += titleWeight
+			n += titleWeight
+		}
+		for _, t := range Tokenize(d.Body) {
+			freqs[t]++
+			n++
+		}
+		for t, f := range freqs {
+			if b.postings[t] == nil {
+				b.postings[t] = map[docKey]int{}
+			}
+			b.postings[t][k] = f
+		}
+		b.docs[k] = d
+		b.lens[k] = n
+		b.totalLen += n
+	}
+	return nil
+}
+
+// Delete remove the documents of the type by their ids.
+func (b *MemoryBackend) Delete(typ string, ids ...int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range ids {
+		b.remove(docKey{typ, id})
+	}
+	return nil
+}
+
+func (b *MemoryBackend) remove(k docKey) {
+	d, ok := b.docs[k]
+	if !ok {
+		return
+	}
+	for _, t := range append(Tokenize(d.Title), Tokenize(d.Body)...) {
+		if p := b.postings[t]; p != nil {
+			delete(p, k)
+			if len(p) == 0 {
+				delete(b.postings, t)
+			}
+		}
+	}
+	b.totalLen -= b.lens[k]
+	delete(b.lens, k)
+	delete(b.docs, k)
+}
+
+// Search find the documents having all the terms, ranked by BM25.
+func (b *MemoryBackend) Search(ctx context.Context, q Query) ([]Match, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.docs) == 0 {
+		return nil, nil
+	}
+	const k1, bb = 1.2, 0.75
+	n := float64(len(b.docs))
+	avgLen := float64(b.totalLen) / n
+	types := map[string]bool{}
+	for _, t := range q.Types {
+		types[t] = true
+	}
+	scores := map[docKey]float64{}
+	for i, t := range q.Terms {
+		p := b.postings[t]
+		if len(p) == 0 {
+			return nil, nil
+		}
+		idf := math.Log(1 + (n-float64(len(p))+0.5)/(float64(len(p))+0.5))
+		next := map[docKey]float64{}
+		for k, f := range p {
+			if !types[k.typ] {
+				continue
+			}
+			if _, ok := scores[k]; i > 0 && !ok {
+				continue
+			}
+			tf := float64(f)
+			next[k] = scores[k] + idf*tf*(k1+1)/(tf+k1*(1-bb+bb*float64(b.lens[k])/avgLen))
+		}
+		scores = next
+	}
+	matches := make([]Match, 0, len(scores))
+	for k, s := range scores {
+		matches = append(matches, Match{Document: b.docs[k], Score: s})
+	}
+	return matches, nil
+}
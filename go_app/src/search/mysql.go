@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	m "../models"
+	"../moderation"
+)
+
+// MySQLBackend searches the articles and the approved comments of the app database by their FULLTEXT indexes,
+// which MySQL keeps in sync itself, so Index, Delete and Reset do nothing.
+type MySQLBackend struct{}
+
+// NewMySQLBackend create the backend on the app database.
+func NewMySQLBackend() *MySQLBackend {
+	return &MySQLBackend{}
+}
+
+func (b *MySQLBackend) Name() string { return "mysql" }
+
+func (b *MySQLBackend) readsTables() {}
+
+func (b *MySQLBackend) Index(docs ...Document) error { return nil }
+
+func (b *MySQLBackend) Delete(typ string, ids ...int64) error { return nil }
+
+func (b *MySQLBackend) Reset() error { return nil }
+
+var mysqlSearches = map[string]string{
+	Articles: `SELECT 'articles' AS type, id, id AS article_id, title, COALESCE(text, '') AS body, MATCH(title, text) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM articles WHERE MATCH(title, text) AGAINST (? IN BOOLEAN MODE) ORDER BY score DESC LIMIT ?`,
+	Comments: `SELECT 'comments' AS type, id, COALESCE(article_id, 0) AS article_id, '' AS title, COALESCE(body, '') AS body, MATCH(body) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM comments WHERE MATCH(body) AGAINST (? IN BOOLEAN MODE) AND status = ? ORDER BY score DESC LIMIT ?`,
+}
+
+// Search find the documents having all the terms, each term is required in the boolean mode.
+func (b *MySQLBackend) Search(ctx context.Context, q Query) ([]Match, error) {
+	ctx = m.ObserveQuery(ctx, "MySQLBackend.Search")
+	against := "+" + strings.Join(q.Terms, " +")
+	matches := []Match{}
+	for _, typ := range q.Types {
+		args := []interface{}{against, against}
+		if typ == Comments {
+			args = append(args, moderation.Approved)
+		}
+		found := []Match{}
+		if err := m.DB.SelectContext(ctx, &found, mysqlSearches[typ], append(args, limitOf(q))...); err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
+	}
+	return matches, nil
+}
+
+// limitOf is the most matches a backend returns per type.
+func limitOf(q Query) int {
+	if q.Limit > 0 {
+		return q.Limit
+	}
+	return 100
+}
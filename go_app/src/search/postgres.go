@@ -0,0 +1,95 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// PostgresBackend keeps the documents in a table of a Postgres database with a generated tsvector,
+// the titles weighted higher, ranked by ts_rank.
+type PostgresBackend struct {
+	db *sqlx.DB
+}
+
+// NewPostgresBackend open the Postgres database of the dsn, and create the table if missing.
+func NewPostgresBackend(dsn string) (*PostgresBackend, error) {
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	for _, sql := range []string{
+		`CREATE TABLE IF NOT EXISTS search_documents (
+			type varchar(16) NOT NULL,
+			id bigint NOT NULL,
+			article_id bigint NOT NULL,
+			title text NOT NULL DEFAULT '',
+			body text NOT NULL DEFAULT '',
+			tsv tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', body), 'B')) STORED,
+			PRIMARY KEY (type, id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS index_search_documents_on_tsv ON search_documents USING GIN (tsv)`,
+	} {
+		if _, err = db.Exec(sql); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &PostgresBackend{db: db}, nil
+}
+
+func (b *PostgresBackend) Name() string { return "postgres" }
+
+// Close close the database of the backend.
+func (b *PostgresBackend) Close() error { return b.db.Close() }
+
+// Index add or replace the documents.
+func (b *PostgresBackend) Index(docs ...Document) error {
+	tx, err := b.db.Beginx()
+	if err != nil {
+		return err
+	}
+	for _, d := range docs {
+		_, err = tx.NamedExec(`INSERT INTO search_documents (type, id, article_id, title, body) VALUES (:type, :id, :article_id, :title, :body)
+			ON CONFLICT (type, id) DO UPDATE SET article_id = EXCLUDED.article_id, title = EXCLUDED.title, body = EXCLUDED.body`, d)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Delete remove the documents of the type by their ids.
+func (b *PostgresBackend) Delete(typ string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	sql, args, err := sqlx.In(`DELETE FROM search_documents WHERE type = ? AND id IN (?)`, typ, ids)
+	if err != nil {
+		return err
+	}
+	_, err = b.db.Exec(b.db.Rebind(sql), args...)
+	return err
+}
+
+// Reset remove all the documents.
+func (b *PostgresBackend) Reset() error {
+	_, err := b.db.Exec(`TRUNCATE search_documents`)
+	return err
+}
+
+// Search find the documents having all the terms, stemmed as english.
+func (b *PostgresBackend) Search(ctx context.Context, q Query) ([]Match, error) {
+	sql, args, err := sqlx.In(`SELECT type, id, article_id, title, body, ts_rank(tsv, query) AS score
+		FROM search_documents, plainto_tsquery('english', ?) query WHERE tsv @@ query AND type IN (?) ORDER BY score DESC LIMIT ?`,
+		strings.Join(q.Terms, " "), q.Types, limitOf(q))
+	if err != nil {
+		return nil, err
+	}
+	matches := []Match{}
+	err = b.db.SelectContext(ctx, &matches, b.db.Rebind(sql), args...)
+	return matches, err
+}
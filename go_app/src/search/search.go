@@ -0,0 +1,195 @@
+// Package search finds the articles and the comments by their words, ranked, with highlighted snippets.
+// The index is kept by a pluggable Backend: the MySQL FULLTEXT indexes of the tables themselves,
+// a SQLite FTS5 or a Postgres tsvector side index, or an in-process inverted index.
+package search
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The types of the documents, named after their tables.
+const (
+	Articles = "articles"
+	Comments = "comments"
+)
+
+// Types are all the types of the documents.
+var Types = []string{Articles, Comments}
+
+// Document is an article or a comment as indexed, the ArticleId of an article is its own id.
+type Document struct {
+	Type      string `json:"type" db:"type"`
+	Id        int64  `json:"id" db:"id"`
+	ArticleId int64  `json:"article_id" db:"article_id"`
+	Title     string `json:"title,omitempty" db:"title"`
+	Body      string `json:"-" db:"body"`
+}
+
+// Match is a document found by a backend, the higher the score the more relevant.
+type Match struct {
+	Document
+	Score float64 `db:"score"`
+}
+
+// Hit is a search result, the Snippet is HTML escaped with the matched words in <mark>.
+type Hit struct {
+	Document
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// Query is a search of all the Terms in the documents of the Types.
+type Query struct {
+	Terms []string
+	Types []string
+	Limit int
+}
+
+// Backend indexes the documents and finds the ones having all the terms of a query.
+type Backend interface {
+	Name() string
+	// Index add or replace the documents.
+	Index(docs ...Document) error
+	// Delete remove the documents of the type by their ids, the unknown ones are skipped.
+	Delete(typ string, ids ...int64) error
+	// Reset remove all the documents before a reindex.
+	Reset() error
+	Search(ctx context.Context, q Query) ([]Match, error)
+}
+
+// tablesReader is implemented by the backends searching the tables themselves,
+// they need neither the sync nor the reindex.
+type tablesReader interface {
+	readsTables()
+}
+
+// Search run the query on the backend and build the snippets of the results.
+func Search(ctx context.Context, b Backend, q Query) ([]Hit, error) {
+	if len(q.Terms) == 0 {
+		return []Hit{}, nil
+	}
+	matches, err := b.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	re := termsRegexp(q.Terms)
+	hits := make([]Hit, len(matches))
+	for i, mt := range matches {
+		text := mt.Body
+		if !re.MatchString(text) && re.MatchString(mt.Title) {
+			text = mt.Title
+		}
+		hits[i] = Hit{Document: mt.Document, Score: mt.Score, Snippet: Snippet(text, re, 200)}
+	}
+	return hits, nil
+}
+
+// ParseTypes parse the comma separated types, all of them if empty.
+func ParseTypes(s string) ([]string, error) {
+	if s == "" {
+		return Types, nil
+	}
+	types := []string{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t != Articles && t != Comments {
+			return nil, fmt.Errorf("Unknown type %q, it should be articles or comments", t)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// Tokenize split a text into its lower case words of two letters or more.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := words[:0]
+	for _, w := range words {
+		if utf8.RuneCountInString(w) >= 2 {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+func termsRegexp(terms []string) *regexp.Regexp {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return regexp.MustCompile(`(?i)(` + strings.Join(quoted, "|") + `)`)
+}
+
+// Snippet cut about width bytes of the text around the first match of re, on word boundaries,
+// and return it HTML escaped with the matches in <mark>.
+func Snippet(text string, re *regexp.Regexp, width int) string {
+	start, end := 0, len(text)
+	if loc := re.FindStringIndex(text); loc != nil && loc[0] > width/3 {
+		start = loc[0] - width/3
+		if i := strings.IndexFunc(text[start:loc[0]], unicode.IsSpace); i >= 0 {
+			start += i + 1
+		}
+	}
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	if start+width < end {
+		end = start + width
+		if i := strings.LastIndexFunc(text[start:end], unicode.IsSpace); i > 0 {
+			end = start + i
+		}
+		for end > start && !utf8.RuneStart(text[end]) {
+			end--
+		}
+	}
+	part := text[start:end]
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	last := 0
+	for _, loc := range re.FindAllStringIndex(part, -1) {
+		b.WriteString(html.EscapeString(part[last:loc[0]]))
+		b.WriteString("<mark>" + html.EscapeString(part[loc[0]:loc[1]]) + "</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(part[last:]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// Open the backend by its name: memory, mysql on the app database, sqlite or postgres with the dsn.
+func Open(name, dsn string) (Backend, error) {
+	switch name {
+	case "memory":
+		return NewMemoryBackend(), nil
+	case "mysql":
+		return NewMySQLBackend(), nil
+	case "sqlite":
+		if dsn == "" {
+			dsn = "search.sqlite3"
+		}
+		return NewSQLiteBackend(dsn)
+	case "postgres":
+		if dsn == "" {
+			return nil, fmt.Errorf("The postgres search backend needs a dsn")
+		}
+		return NewPostgresBackend(dsn)
+	}
+	return nil, fmt.Errorf("Unknown search backend %q, it should be memory, mysql, sqlite or postgres", name)
+}
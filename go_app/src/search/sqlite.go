@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/railstack/go-sqlite3"
+)
+
+// SQLiteBackend keeps the documents in a FTS5 table of a SQLite database, ranked by bm25 with the titles weighted.
+// The sqlite3 driver should be built with FTS5, i.e. with the tag sqlite_fts5.
+type SQLiteBackend struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteBackend open the SQLite database of the dsn, and create the FTS5 table if missing.
+func NewSQLiteBackend(dsn string) (*SQLiteBackend, error) {
+	db, err := sqlx.Connect("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS search_documents USING fts5(type UNINDEXED, id UNINDEXED, article_id UNINDEXED, title, body)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteBackend{db: db}, nil
+}
+
+func (b *SQLiteBackend) Name() string { return "sqlite" }
+
+// Close close the database of the backend.
+func (b *SQLiteBackend) Close() error { return b.db.Close() }
+
+// Index add or replace the documents.
+func (b *SQLiteBackend) Index(docs ...Document) error {
+	tx, err := b.db.Beginx()
+	if err != nil {
+		return err
+	}
+	for _, d := range docs {
+		_, err = tx.Exec(`DELETE FROM search_documents WHERE type = ? AND id = ?`, d.Type, d.Id)
+		if err == nil {
+			_, err = tx.NamedExec(`INSERT INTO search_documents (type, id, article_id, title, body) VALUES (:type, :id, :article_id, :title, :body)`, d)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Delete remove the documents of the type by their ids.
+func (b *SQLiteBackend) Delete(typ string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	sql, args, err := sqlx.In(`DELETE FROM search_documents WHERE type = ? AND id IN (?)`, typ, ids)
+	if err != nil {
+		return err
+	}
+	_, err = b.db.Exec(sql, args...)
+	return err
+}
+
+// Reset remove all the documents.
+func (b *SQLiteBackend) Reset() error {
+	_, err := b.db.Exec(`DELETE FROM search_documents`)
+	return err
+}
+
+// Search find the documents having all the terms.
+func (b *SQLiteBackend) Search(ctx context.Context, q Query) ([]Match, error) {
+	// every term is quoted as a string in the FTS5 query syntax, they're all required
+	terms := make([]string, len(q.Terms))
+	for i, t := range q.Terms {
+		terms[i] = `"` + strings.Replace(t, `"`, `""`, -1) + `"`
+	}
+	sql, args, err := sqlx.In(`SELECT type, id, article_id, title, body, -bm25(search_documents, 0, 0, 0, 2.0, 1.0) AS score
+		FROM search_documents WHERE search_documents MATCH ? AND type IN (?) ORDER BY score DESC LIMIT ?`,
+		strings.Join(terms, " "), q.Types, limitOf(q))
+	if err != nil {
+		return nil, err
+	}
+	matches := []Match{}
+	err = b.db.SelectContext(ctx, &matches, sql, args...)
+	return matches, err
+}
@@ -0,0 +1,111 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+
+	m "../models"
+	"../moderation"
+)
+
+// reindexBatchSize is how many records are indexed at once by Reindex.
+const reindexBatchSize = 500
+
+// ArticleDocument is the document of an article.
+func ArticleDocument(ar m.Article) Document {
+	return Document{Type: Articles, Id: ar.Id, ArticleId: ar.Id, Title: ar.Title, Body: ar.Text}
+}
+
+// CommentDocument is the document of a comment.
+func CommentDocument(cm m.Comment) Document {
+	return Document{Type: Comments, Id: cm.Id, ArticleId: cm.ArticleId, Body: cm.Body}
+}
+
+// searchable tell whether the comment is shown to the public, so it can be found.
+func searchable(cm m.Comment) bool {
+	return cm.Status == moderation.Approved && !cm.IsTombstone()
+}
+
+// Sync is a write hook of the models keeping the backend in sync with the articles and the comments,
+// e.g. models.OnWrite(search.Sync(b)). The errors are only logged, a reindex fixes a stale index.
+func Sync(b Backend) m.WriteHook {
+	return func(ctx context.Context, table, op string, ids []int64) {
+		if _, ok := b.(tablesReader); ok || (table != Articles && table != Comments) {
+			return
+		}
+		var err error
+		if op == m.Destroyed {
+			err = b.Delete(table, ids...)
+		} else {
+			err = refresh(ctx, b, table, ids)
+		}
+		if err != nil {
+			slog.Error("Sync search index error", "table", table, "ids", ids, "error", err)
+		}
+	}
+}
+
+// refresh index the records again as they are in the database now.
+func refresh(ctx context.Context, b Backend, table string, ids []int64) error {
+	if table == Articles {
+		articles, err := m.FindArticles(ctx, ids...)
+		if err != nil {
+			return err
+		}
+		docs := make([]Document, len(articles))
+		for i, ar := range articles {
+			docs[i] = ArticleDocument(ar)
+		}
+		return b.Index(docs...)
+	}
+	comments, err := m.FindComments(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	docs, hidden := []Document{}, []int64{}
+	for _, cm := range comments {
+		if searchable(cm) {
+			docs = append(docs, CommentDocument(cm))
+		} else {
+			hidden = append(hidden, cm.Id)
+		}
+	}
+	if err = b.Index(docs...); err != nil {
+		return err
+	}
+	return b.Delete(Comments, hidden...)
+}
+
+// Reindex rebuild the index of the backend from all the articles and the approved comments,
+// it returns the counts of the documents indexed by type.
+func Reindex(ctx context.Context, b Backend) (map[string]int, error) {
+	counts := map[string]int{Articles: 0, Comments: 0}
+	if _, ok := b.(tablesReader); ok {
+		return counts, nil
+	}
+	if err := b.Reset(); err != nil {
+		return counts, err
+	}
+	err := m.ArticlesInBatches(ctx, reindexBatchSize, func(articles []m.Article) error {
+		docs := make([]Document, len(articles))
+		for i, ar := range articles {
+			docs[i] = ArticleDocument(ar)
+		}
+		counts[Articles] += len(docs)
+		return b.Index(docs...)
+	})
+	if err != nil {
+		return counts, err
+	}
+	err = m.CommentsInBatchesWhere(ctx, reindexBatchSize, "status = ?", []interface{}{moderation.Approved}, func(comments []m.Comment) error {
+		docs := []Document{}
+		for _, cm := range comments {
+			if searchable(cm) {
+				docs = append(docs, CommentDocument(cm))
+			}
+		}
+		counts[Comments] += len(docs)
+		return b.Index(docs...)
+	})
+	return counts, err
+}
@@ -0,0 +1,50 @@
+// Package slug turns the titles of the articles into the slugs of their URLs,
+// the models keep them unique.
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the most characters of a slug before the "-N" suffix of the models.
+const MaxLength = 80
+
+// letters replace the letters which don't decompose into a plain one with marks.
+var letters = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "đ", "d", "ð", "d", "þ", "th", "ł", "l", "ı", "i", "&", " and ",
+)
+
+// Make turn a title into a slug of lower case ascii letters, digits and "-",
+// e.g. "Crème Brûlée & Straße" gives "creme-brulee-and-strasse".
+// A slug is never all digits, so it can't be taken for an id.
+func Make(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(letters.Replace(strings.ToLower(title))) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > MaxLength {
+		slug = strings.TrimRight(slug[:MaxLength], "-")
+	}
+	if slug == "" {
+		return "article"
+	}
+	if strings.Trim(slug, "0123456789") == "" {
+		return "article-" + slug
+	}
+	return slug
+}
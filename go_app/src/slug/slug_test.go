@@ -0,0 +1,42 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	long := strings.Repeat("word ", 30)
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Crème Brûlée & Straße", "creme-brulee-and-strasse"},
+		{"Œuvre de Łódź", "oeuvre-de-lodz"},
+		{"Go 1.22 released", "go-1-22-released"},
+		{"snake_case and kebab-case", "snake-case-and-kebab-case"},
+		{"日本語", "article"},
+		{"", "article"},
+		{"!!!", "article"},
+		{"2026", "article-2026"},
+		{"2026 10", "2026-10"},
+		{long, strings.TrimRight(strings.Repeat("word-", 16), "-")},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.title); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestMakeMaxLength(t *testing.T) {
+	got := Make(strings.Repeat("a", MaxLength) + " b")
+	if len(got) > MaxLength {
+		t.Errorf("Make gave %d characters, want at most %d", len(got), MaxLength)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("Make gave %q ending with a dash", got)
+	}
+}
@@ -0,0 +1,207 @@
+// Package textdiff compares two texts by lines or by words, with the Myers algorithm,
+// and formats the line differences as a unified diff.
+package textdiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Op is what an edit does to go from the old text to the new one.
+type Op string
+
+const (
+	Equal  Op = "="
+	Insert Op = "+"
+	Delete Op = "-"
+)
+
+// Edit is a part of the texts, kept, inserted or deleted.
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines compare the texts line by line, every edit is a line without its line break.
+func Lines(a, b string) []Edit {
+	return diff(splitLines(a), splitLines(b))
+}
+
+// Words compare the texts word by word, the consecutive edits of the same op are merged,
+// so joining the texts of the equal and the inserted edits gives the new text back.
+func Words(a, b string) []Edit {
+	edits := diff(wordRe.FindAllString(a, -1), wordRe.FindAllString(b, -1))
+	merged := []Edit{}
+	for _, e := range edits {
+		if n := len(merged); n > 0 && merged[n-1].Op == e.Op {
+			merged[n-1].Text += e.Text
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+var wordRe = regexp.MustCompile(`\s+|[\p{L}\p{N}_]+|.`)
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.Replace(s, "\r\n", "\n", -1), "\n"), "\n")
+}
+
+// Unified format the line differences of the texts as a unified diff with the lines of context
+// around the changes, empty if the texts are the same.
+func Unified(fromName, toName, a, b string, context int) string {
+	edits := Lines(a, b)
+	changed := []int{}
+	for i, e := range edits {
+		if e.Op != Equal {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+	// the line numbers in the old and the new text before each edit
+	aLine, bLine := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.Op != Insert {
+			aLine[i+1]++
+		}
+		if e.Op != Delete {
+			bLine[i+1]++
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	hunk := func(start, end int) {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[start], aLine[end]-aLine[start]), hunkRange(bLine[start], bLine[end]-bLine[start]))
+		for _, e := range edits[start:end] {
+			prefix := " "
+			if e.Op != Equal {
+				prefix = string(e.Op)
+			}
+			sb.WriteString(prefix + e.Text + "\n")
+		}
+	}
+	start, end := clamp(changed[0]-context, len(edits)), clamp(changed[0]+context+1, len(edits))
+	for _, i := range changed[1:] {
+		if i-context > end {
+			hunk(start, end)
+			start = clamp(i-context, len(edits))
+		}
+		end = clamp(i+context+1, len(edits))
+	}
+	hunk(start, end)
+	return sb.String()
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line)
+	}
+	if count == 1 {
+		return fmt.Sprint(line + 1)
+	}
+	return fmt.Sprintf("%d,%d", line+1, count)
+}
+
+func clamp(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// diff find the shortest edit script from a to b with the Myers algorithm, one edit per token.
+func diff(a, b []string) []Edit {
+	// the common prefix and suffix are kept as they are, it's the usual case of a small change
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, t := range a[:pre] {
+		edits = append(edits, Edit{Equal, t})
+	}
+	edits = append(edits, myers(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, t := range a[len(a)-suf:] {
+		edits = append(edits, Edit{Equal, t})
+	}
+	return edits
+}
+
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	// v[max+k] is the furthest x on the diagonal k, trace keeps v before each step d for the backtrack
+	v := make([]int, 2*max+2)
+	trace := [][]int{}
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+	edits := []Edit{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Equal, a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{Insert, b[y-1]})
+				y--
+			} else {
+				edits = append(edits, Edit{Delete, a[x-1]})
+				x--
+			}
+		}
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -0,0 +1,115 @@
+package textdiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{"same", "a\nb\n", "a\nb\n", []Edit{{Equal, "a"}, {Equal, "b"}}},
+		{"both empty", "", "", []Edit{}},
+		{"from empty", "", "a\nb", []Edit{{Insert, "a"}, {Insert, "b"}}},
+		{"to empty", "a\nb", "", []Edit{{Delete, "a"}, {Delete, "b"}}},
+		{"changed line", "a\nb\nc", "a\nx\nc", []Edit{{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"}}},
+		{"inserted line", "a\nc", "a\nb\nc", []Edit{{Equal, "a"}, {Insert, "b"}, {Equal, "c"}}},
+		{"deleted line", "a\nb\nc", "a\nc", []Edit{{Equal, "a"}, {Delete, "b"}, {Equal, "c"}}},
+		{"CRLF", "a\r\nb\r\n", "a\nb", []Edit{{Equal, "a"}, {Equal, "b"}}},
+	}
+	for _, tt := range tests {
+		if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Lines = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestLinesShortest check the edit scripts are the shortest and rebuild both texts.
+func TestLinesShortest(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", 5},
+		{"x\ny\nz", "z\ny\nx", 4},
+		{"a\nb\nc\nd\ne", "a\nc\ne\nf", 3},
+	}
+	for _, tt := range tests {
+		edits := Lines(tt.a, tt.b)
+		var old, new []string
+		n := 0
+		for _, e := range edits {
+			if e.Op != Insert {
+				old = append(old, e.Text)
+			}
+			if e.Op != Delete {
+				new = append(new, e.Text)
+			}
+			if e.Op != Equal {
+				n++
+			}
+		}
+		if got := strings.Join(old, "\n"); got != tt.a {
+			t.Errorf("Lines(%q, %q) gives the old text %q", tt.a, tt.b, got)
+		}
+		if got := strings.Join(new, "\n"); got != tt.b {
+			t.Errorf("Lines(%q, %q) gives the new text %q", tt.a, tt.b, got)
+		}
+		if n != tt.edits {
+			t.Errorf("Lines(%q, %q) has %d inserts and deletes, want %d", tt.a, tt.b, n, tt.edits)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	a, b := "The quick brown fox.", "The slow brown fox!"
+	want := []Edit{{Equal, "The "}, {Delete, "quick"}, {Insert, "slow"}, {Equal, " brown fox"}, {Delete, "."}, {Insert, "!"}}
+	got := Words(a, b)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %v, want %v", got, want)
+	}
+	var rebuilt strings.Builder
+	for _, e := range got {
+		if e.Op != Delete {
+			rebuilt.WriteString(e.Text)
+		}
+	}
+	if rebuilt.String() != b {
+		t.Errorf("Words gives the new text %q, want %q", rebuilt.String(), b)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	if got := Unified("a", "b", "same\n", "same\n", 3); got != "" {
+		t.Errorf("Unified of the same texts = %q, want empty", got)
+	}
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\nten\n"
+	want := `--- rev 1
++++ rev 2
+@@ -1,3 +1,3 @@
+ 1
+-2
++two
+ 3
+@@ -9,2 +9,2 @@
+ 9
+-10
++ten
+`
+	if got := Unified("rev 1", "rev 2", a, b, 1); got != want {
+		t.Errorf("Unified = %q, want %q", got, want)
+	}
+	want = `--- rev 1
++++ rev 2
+@@ -0,0 +1 @@
++new
+`
+	if got := Unified("rev 1", "rev 2", "", "new", 3); got != want {
+		t.Errorf("Unified from empty = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+// Package tlsconfig serves TLS with the certificate and the client CA bundle of files, reloaded when they change
+// so a renewed certificate is served without restarting the app.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// The client authentication modes of the mutual TLS with a client CA bundle.
+const (
+	// ClientAuthRequire reject the clients without a certificate signed by the CA.
+	ClientAuthRequire = "require"
+	// ClientAuthVerifyIfGiven accept the clients without a certificate, e.g. the browsers,
+	// but reject the ones with a certificate not signed by the CA.
+	ClientAuthVerifyIfGiven = "verify-if-given"
+)
+
+// Files are the files of the TLS config.
+type Files struct {
+	Cert string
+	Key  string
+	// ClientCA is the PEM bundle of the CAs of the client certificates, empty for no mutual TLS.
+	ClientCA   string
+	ClientAuth string
+}
+
+// Reloader keeps the TLS config built from the files, a reload failing keeps the previous one.
+type Reloader struct {
+	files    Files
+	mu       sync.RWMutex
+	config   *tls.Config
+	modTimes map[string]time.Time
+}
+
+// NewReloader load the files, it fails if they can't be loaded.
+func NewReloader(files Files) (*Reloader, error) {
+	if files.Cert == "" || files.Key == "" {
+		return nil, errors.New("Both the TLS certificate and key are needed")
+	}
+	switch files.ClientAuth {
+	case "", ClientAuthRequire, ClientAuthVerifyIfGiven:
+	default:
+		return nil, fmt.Errorf("Unknown client auth %q, it should be %s or %s", files.ClientAuth, ClientAuthRequire, ClientAuthVerifyIfGiven)
+	}
+	r := &Reloader{files: files}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload load the files again, the TLS config is replaced only if they're all valid.
+func (r *Reloader) Reload() error {
+	modTimes := r.modTimesNow()
+	cert, err := tls.LoadX509KeyPair(r.files.Cert, r.files.Key)
+	if err != nil {
+		return err
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if r.files.ClientCA != "" {
+		pem, err := os.ReadFile(r.files.ClientCA)
+		if err != nil {
+			return err
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("No certificate found in the client CA bundle %s", r.files.ClientCA)
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		if r.files.ClientAuth == ClientAuthVerifyIfGiven {
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config, r.modTimes = config, modTimes
+	return nil
+}
+
+// Config get the TLS config of the server, each handshake uses the config loaded last.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}
+}
+
+func (r *Reloader) current() *tls.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.config
+}
+
+// Watch reload the files when one of them changed, checking every interval until ctx is done.
+// The errors are logged and retried on the next change.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			slog.Error("Reload TLS certificate error", "error", err)
+			// not retried until the files change again
+			r.mu.Lock()
+			r.modTimes = r.modTimesNow()
+			r.mu.Unlock()
+			continue
+		}
+		slog.Info("Reloaded TLS certificate", "cert", r.files.Cert)
+	}
+}
+
+// changed tell whether a file was modified since the last reload.
+func (r *Reloader) changed() bool {
+	now := r.modTimesNow()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, t := range now {
+		if !t.Equal(r.modTimes[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) modTimesNow() map[string]time.Time {
+	times := map[string]time.Time{}
+	for _, name := range []string{r.files.Cert, r.files.Key, r.files.ClientCA} {
+		if name == "" {
+			continue
+		}
+		if fi, err := os.Stat(name); err == nil {
+			times[name] = fi.ModTime()
+		}
+	}
+	return times
+}
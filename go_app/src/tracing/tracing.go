@@ -0,0 +1,68 @@
+// Package tracing sets up the OpenTelemetry tracing of the app: where the spans are exported,
+// and the propagation of the trace context by the traceparent headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Exporters are the exporters of the spans: none, otlp to an OTLP/HTTP collector,
+// stdout, or file to append them as JSON to a file.
+var Exporters = []string{"none", "otlp", "stdout", "file"}
+
+// Setup set the global tracer provider exporting the spans of the service by the exporter, to the endpoint:
+// the URL of the collector for otlp, e.g. "http://localhost:4318", the OTEL_EXPORTER_OTLP_* variables if empty,
+// or the path of the file for file. The returned func flushes the spans left and stops the exporter.
+func Setup(ctx context.Context, service, exporter, endpoint string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	var exp sdktrace.SpanExporter
+	var file *os.File
+	switch exporter {
+	case "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		opts := []otlptracehttp.Option{}
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		exp, err = otlptracehttp.New(ctx, opts...)
+	case "stdout":
+		exp, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		if endpoint == "" {
+			return nil, fmt.Errorf("The file exporter needs the path of the file as the endpoint")
+		}
+		if file, err = os.OpenFile(endpoint, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644); err == nil {
+			exp, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		}
+	default:
+		return nil, fmt.Errorf("Unknown trace exporter %q, it should be one of %s", exporter, strings.Join(Exporters, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(service)))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if file != nil {
+			file.Close()
+		}
+		return err
+	}, nil
+}